@@ -3,11 +3,23 @@ package cosmosapp
 type Client interface {
 	Validator(validatorAddress string) (*Validator, error)
 	Delegation(delegator string, validator string) (*DelegationResponse, error)
+
+	// State queries at a block height, height 0 means the latest state. The node must keep the state
+	// of the height.
+
+	// Validators returns all staking validators at the height
+	Validators(height int64) ([]Validator, error)
+	// ValidatorSet returns the Tendermint validator set and the voting powers at the height
+	ValidatorSet(height int64) ([]ValidatorSetValidator, error)
+	// Accounts returns all accounts at the height
+	Accounts(height int64) ([]Account, error)
+	// Balances returns all balances of the account at the height
+	Balances(accountAddress string, height int64) ([]AccountBalance, error)
 }
 
 type Validator struct {
 	OperatorAddress   string               `json:"operator_address"`
-	ConsensusPubkey   string               `json:"consensus_pubkey"`
+	ConsensusPubkey   PubKey               `json:"consensus_pubkey"`
 	Jailed            bool                 `json:"jailed"`
 	Status            string               `json:"status"`
 	Tokens            string               `json:"tokens"`
@@ -19,6 +31,18 @@ type Validator struct {
 	MinSelfDelegation string               `json:"min_self_delegation"`
 }
 
+type PubKey struct {
+	Type string `json:"@type"`
+	Key  string `json:"key"`
+}
+
+type ValidatorSetValidator struct {
+	Address          string `json:"address"`
+	PubKey           PubKey `json:"pub_key"`
+	VotingPower      string `json:"voting_power"`
+	ProposerPriority string `json:"proposer_priority"`
+}
+
 type ValidatorCommission struct {
 	CommissionRates ValidatorCommissionRates `json:"commission_rates"`
 	UpdateTime      string                   `json:"update_time"`
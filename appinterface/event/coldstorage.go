@@ -0,0 +1,203 @@
+package event
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+const DEFAULT_COLD_TABLE = "events_cold"
+
+// ARCHIVED_PAYLOAD replaces the payload of the events archived to cold storage in the events table
+const ARCHIVED_PAYLOAD = "{}"
+
+// Events cold storage table should have the following schema
+// | Field   | Data Type | Constraint                                       |
+// | ------- | --------- | ------------------------------------------------ |
+// | uuid    | VARCHAR   | PRIMARY KEY, REFERENCES events ON DELETE CASCADE |
+// | payload | BYTEA     | NOT NULL                                         |
+
+// selectEvents returns the statement selecting the columns of the events table together with the
+// archived payload in cold storage, which is NULL when the payload is not archived
+func (store *RDbStore) selectEvents(columns ...string) sq.SelectBuilder {
+	qualifiedColumns := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		qualifiedColumns = append(qualifiedColumns, store.table+"."+column)
+	}
+	qualifiedColumns = append(qualifiedColumns, store.coldTable+".payload")
+
+	return store.rdbHandle.StmtBuilder.Select(
+		qualifiedColumns...,
+	).From(
+		store.table,
+	).LeftJoin(
+		fmt.Sprintf("%s ON %s.uuid = %s.uuid", store.coldTable, store.coldTable, store.table),
+	)
+}
+
+// ArchivePayloads moves the payloads of the events named eventName before height beforeHeight to the
+// compressed cold storage, batchSize events at a time. The archived payloads are rehydrated when the
+// events are read. Each batch is archived in a single transaction. It returns the number of archived
+// payloads.
+func (store *RDbStore) ArchivePayloads(
+	rdbConn rdb.Conn, eventName string, beforeHeight int64, batchSize int,
+) (int64, error) {
+	count := int64(0)
+	for {
+		archivedCount, err := store.archivePayloadsBatch(rdbConn, eventName, beforeHeight, batchSize)
+		if err != nil {
+			return count, err
+		}
+		if archivedCount == 0 {
+			return count, nil
+		}
+		count += archivedCount
+	}
+}
+
+// archivePayloadsBatch copies a batch of payloads to cold storage and replaces them in the events table
+// in a single transaction. It returns the number of archived payloads.
+func (store *RDbStore) archivePayloadsBatch(
+	rdbConn rdb.Conn, eventName string, beforeHeight int64, batchSize int,
+) (int64, error) {
+	tx, err := rdbConn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	txHandle := tx.ToHandle()
+
+	uuids, compressedPayloads, err := store.getPayloadsToArchiveWithRDbHandle(
+		txHandle, eventName, beforeHeight, batchSize,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if len(uuids) == 0 {
+		_ = tx.Rollback()
+		return 0, nil
+	}
+
+	insertStmtBuilder := txHandle.StmtBuilder.Insert(
+		store.coldTable,
+	).Columns(
+		"uuid", "payload",
+	)
+	for i, uuid := range uuids {
+		insertStmtBuilder = insertStmtBuilder.Values(uuid, compressedPayloads[i])
+	}
+	sql, args, err := insertStmtBuilder.Suffix("ON CONFLICT (uuid) DO NOTHING").ToSql()
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("error building archived payloads insertion SQL: %v", err)
+	}
+	if _, err = txHandle.Exec(sql, args...); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("error executing archived payloads insertion SQL: %v", err)
+	}
+
+	sql, args, err = txHandle.StmtBuilder.Update(
+		store.table,
+	).Set(
+		"payload", sq.Expr("?::jsonb", ARCHIVED_PAYLOAD),
+	).Where(
+		sq.Eq{"uuid": uuids},
+	).ToSql()
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("error building archived payloads replacement SQL: %v", err)
+	}
+	if _, err = txHandle.Exec(sql, args...); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("error executing archived payloads replacement SQL: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing archived payloads: %v", err)
+	}
+	return int64(len(uuids)), nil
+}
+
+func (store *RDbStore) getPayloadsToArchiveWithRDbHandle(
+	rdbHandle *rdb.Handle, eventName string, beforeHeight int64, batchSize int,
+) ([]string, [][]byte, error) {
+	// the archived payload is inlined so that the condition matches the partial index of the payloads
+	// to archive
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"uuid", "payload",
+	).From(
+		store.table,
+	).Where(
+		fmt.Sprintf("name = ? AND height < ? AND payload <> '%s'::jsonb", ARCHIVED_PAYLOAD), eventName, beforeHeight,
+	).OrderBy("height").Limit(uint64(batchSize)).ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building payloads to archive selection SQL: %v", err)
+	}
+
+	rows, err := rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing payloads to archive selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	uuids := make([]string, 0, batchSize)
+	compressedPayloads := make([][]byte, 0, batchSize)
+	for rows.Next() {
+		var (
+			uuid    string
+			payload string
+		)
+		if err = rows.Scan(&uuid, &payload); err != nil {
+			return nil, nil, fmt.Errorf("error scanning payload to archive: %v", err)
+		}
+
+		compressedPayload, err := compressPayload(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error compressing payload of event %s: %v", uuid, err)
+		}
+		uuids = append(uuids, uuid)
+		compressedPayloads = append(compressedPayloads, compressedPayload)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating payloads to archive: %v", err)
+	}
+
+	return uuids, compressedPayloads, nil
+}
+
+func compressPayload(payload string) ([]byte, error) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(payload)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
+
+// rehydratePayload returns the payload archived in cold storage, or the payload in the events table
+// when it is not archived
+func rehydratePayload(payload string, maybeArchivedPayload []byte) (string, error) {
+	if maybeArchivedPayload == nil {
+		return payload, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(maybeArchivedPayload))
+	if err != nil {
+		return "", fmt.Errorf("error reading archived payload: %v", err)
+	}
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("error decompressing archived payload: %v", err)
+	}
+
+	return string(decompressed), nil
+}
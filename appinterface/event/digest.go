@@ -0,0 +1,304 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+const DEFAULT_DIGEST_TABLE = "event_digests"
+
+// DIGEST_VERIFICATION_BATCH_SIZE is the number of heights verified at a time
+const DIGEST_VERIFICATION_BATCH_SIZE = 1000
+
+// Event digests table should have the following schema
+// | Field  | Data Type | Constraint  |
+// | ------ | --------- | ----------- |
+// | height | INT64     | PRIMARY KEY |
+// | digest | VARCHAR   | NOT NULL    |
+
+// RDbDigestStore keeps the per height digests chaining the stored events, which makes any alteration
+// of the events after indexing detectable
+type RDbDigestStore struct {
+	rdbHandle *rdb.Handle
+
+	table string
+}
+
+func NewRDbDigestStore(handle *rdb.Handle) *RDbDigestStore {
+	return &RDbDigestStore{
+		rdbHandle: handle,
+
+		table: DEFAULT_DIGEST_TABLE,
+	}
+}
+
+// FindByHeight returns the digest at height, nil if there is none
+func (store *RDbDigestStore) FindByHeight(height int64) (*string, error) {
+	return store.findByHeightWithRDbHandle(store.rdbHandle, height)
+}
+
+func (store *RDbDigestStore) findByHeightWithRDbHandle(rdbHandle *rdb.Handle, height int64) (*string, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"digest",
+	).From(
+		store.table,
+	).Where(
+		"height = ?", height,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building event digest selection SQL: %v", err)
+	}
+
+	var digest string
+	if err = rdbHandle.QueryRow(sql, args...).Scan(&digest); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error executing event digest selection SQL: %v", err)
+	}
+
+	return &digest, nil
+}
+
+// findAllByHeightRange returns the digests from `fromHeight` to `toHeight` inclusively by height
+func (store *RDbDigestStore) findAllByHeightRange(fromHeight int64, toHeight int64) (map[int64]string, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"height", "digest",
+	).From(
+		store.table,
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building event digests selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing event digests selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	digests := make(map[int64]string)
+	for rows.Next() {
+		var (
+			height int64
+			digest string
+		)
+		if err = rows.Scan(&height, &digest); err != nil {
+			return nil, fmt.Errorf("error scanning event digest: %v", err)
+		}
+		digests[height] = digest
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event digests: %v", err)
+	}
+
+	return digests, nil
+}
+
+func (store *RDbDigestStore) upsertWithRDbHandle(rdbHandle *rdb.Handle, height int64, digest string) error {
+	sql, args, err := rdbHandle.StmtBuilder.Insert(
+		store.table,
+	).Columns(
+		"height", "digest",
+	).Values(
+		height, digest,
+	).Suffix("ON CONFLICT (height) DO UPDATE SET digest = EXCLUDED.digest").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building event digest insertion SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing event digest insertion SQL: %v", err)
+	}
+
+	return nil
+}
+
+func (store *RDbDigestStore) deleteWithRDbHandle(rdbHandle *rdb.Handle, condition string, height int64) error {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		store.table,
+	).Where(
+		condition, height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building event digests deletion SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing event digests deletion SQL: %v", err)
+	}
+
+	return nil
+}
+
+// InsertDigestWithRDbHandle computes the digest of the events at height chained with the digest of
+// the previous height and stores it. It should be called with the handle of the transaction storing
+// the events.
+func (store *RDbStore) InsertDigestWithRDbHandle(
+	rdbHandle *rdb.Handle, height int64, events []entity_event.Event,
+) error {
+	maybePreviousDigest, err := store.digestStore.findByHeightWithRDbHandle(rdbHandle, height-1)
+	if err != nil {
+		return fmt.Errorf("error getting event digest at height %d: %v", height-1, err)
+	}
+	previousDigest := ""
+	if maybePreviousDigest != nil {
+		previousDigest = *maybePreviousDigest
+	}
+
+	digest, err := entity_event.ComputeEventsDigest(previousDigest, events)
+	if err != nil {
+		return fmt.Errorf("error computing event digest at height %d: %v", height, err)
+	}
+	if err = store.digestStore.upsertWithRDbHandle(rdbHandle, height, digest); err != nil {
+		return fmt.Errorf("error storing event digest at height %d: %v", height, err)
+	}
+
+	return nil
+}
+
+// insertStoredEventsDigestWithRDbHandle computes the digest of the stored events at height chained
+// with the digest of the previous height and stores it, see InsertDigestWithRDbHandle
+func (store *RDbStore) insertStoredEventsDigestWithRDbHandle(rdbHandle *rdb.Handle, height int64) error {
+	maybePreviousDigest, err := store.digestStore.findByHeightWithRDbHandle(rdbHandle, height-1)
+	if err != nil {
+		return fmt.Errorf("error getting event digest at height %d: %v", height-1, err)
+	}
+	previousDigest := ""
+	if maybePreviousDigest != nil {
+		previousDigest = *maybePreviousDigest
+	}
+
+	encodedEventsByHeight, err := store.getAllPayloadsByHeightRangeWithRDbHandle(rdbHandle, height, height)
+	if err != nil {
+		return err
+	}
+	digest, err := entity_event.ComputeDigest(previousDigest, encodedEventsByHeight[height])
+	if err != nil {
+		return fmt.Errorf("error computing event digest at height %d: %v", height, err)
+	}
+	if err = store.digestStore.upsertWithRDbHandle(rdbHandle, height, digest); err != nil {
+		return fmt.Errorf("error storing event digest at height %d: %v", height, err)
+	}
+
+	return nil
+}
+
+// GetDigest returns the event digest at height, nil if there is none
+func (store *RDbStore) GetDigest(height int64) (*string, error) {
+	return store.digestStore.FindByHeight(height)
+}
+
+// VerifyDigests recomputes the digest of every height from `fromHeight` to `toHeight` inclusively
+// from the stored events and returns the heights of which the stored digest is missing or does not
+// match. Each digest is chained with the stored digest of the previous height, so that an altered
+// height is reported on its own, together with the next height when its digest is altered as well.
+func (store *RDbStore) VerifyDigests(fromHeight int64, toHeight int64) ([]DigestMismatch, error) {
+	mismatches := make([]DigestMismatch, 0)
+	for batchFromHeight := fromHeight; batchFromHeight <= toHeight; batchFromHeight += DIGEST_VERIFICATION_BATCH_SIZE {
+		batchToHeight := batchFromHeight + DIGEST_VERIFICATION_BATCH_SIZE - 1
+		if batchToHeight > toHeight {
+			batchToHeight = toHeight
+		}
+
+		batchMismatches, err := store.verifyDigestsBatch(batchFromHeight, batchToHeight)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, batchMismatches...)
+	}
+
+	return mismatches, nil
+}
+
+func (store *RDbStore) verifyDigestsBatch(fromHeight int64, toHeight int64) ([]DigestMismatch, error) {
+	digests, err := store.digestStore.findAllByHeightRange(fromHeight-1, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	encodedEventsByHeight, err := store.getAllPayloadsByHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]DigestMismatch, 0)
+	for height := fromHeight; height <= toHeight; height++ {
+		computedDigest, err := entity_event.ComputeDigest(digests[height-1], encodedEventsByHeight[height])
+		if err != nil {
+			return nil, fmt.Errorf("error computing event digest at height %d: %v", height, err)
+		}
+
+		storedDigest, exist := digests[height]
+		if !exist {
+			mismatches = append(mismatches, DigestMismatch{
+				Height:         height,
+				ComputedDigest: computedDigest,
+			})
+		} else if storedDigest != computedDigest {
+			mismatches = append(mismatches, DigestMismatch{
+				Height:            height,
+				MaybeStoredDigest: &storedDigest,
+				ComputedDigest:    computedDigest,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
+
+func (store *RDbStore) getAllPayloadsByHeightRange(fromHeight int64, toHeight int64) (map[int64][]string, error) {
+	return store.getAllPayloadsByHeightRangeWithRDbHandle(store.rdbHandle, fromHeight, toHeight)
+}
+
+func (store *RDbStore) getAllPayloadsByHeightRangeWithRDbHandle(
+	rdbHandle *rdb.Handle, fromHeight int64, toHeight int64,
+) (map[int64][]string, error) {
+	sql, args, err := store.selectEvents(
+		"height", "payload",
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events payload selection SQL: %v", err)
+	}
+
+	rows, err := rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing events payload selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	payloadsByHeight := make(map[int64][]string)
+	for rows.Next() {
+		var (
+			height  int64
+			payload string
+
+			maybeArchivedPayload []byte
+		)
+		if err = rows.Scan(&height, &payload, &maybeArchivedPayload); err != nil {
+			return nil, fmt.Errorf("error scanning event payload: %v", err)
+		}
+		if payload, err = rehydratePayload(payload, maybeArchivedPayload); err != nil {
+			return nil, fmt.Errorf("error rehydrating event payload at height %d: %v", height, err)
+		}
+		payloadsByHeight[height] = append(payloadsByHeight[height], payload)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events payload: %v", err)
+	}
+
+	return payloadsByHeight, nil
+}
+
+// DigestMismatch is a height of which the stored event digest is missing or does not match the events
+type DigestMismatch struct {
+	Height            int64
+	MaybeStoredDigest *string
+	ComputedDigest    string
+}
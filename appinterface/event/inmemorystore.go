@@ -0,0 +1,140 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+var _ entity_event.Store = &InMemoryStore{}
+var _ entity_event.HeightNotifier = &InMemoryStore{}
+
+// InMemoryStore is an event store keeping all events in memory. It is for running the system without
+// database, all events are lost when the process exits.
+type InMemoryStore struct {
+	mutex sync.RWMutex
+
+	eventsByHeight    map[int64][]entity_event.Event
+	maybeLatestHeight *int64
+
+	subscribers map[chan<- int64]struct{}
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		eventsByHeight: make(map[int64][]entity_event.Event),
+
+		subscribers: make(map[chan<- int64]struct{}),
+	}
+}
+
+// GetLatestHeight returns latest event height, nil if no event is stored
+func (store *InMemoryStore) GetLatestHeight() (*int64, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	if store.maybeLatestHeight == nil {
+		return nil, nil
+	}
+	latestHeight := *store.maybeLatestHeight
+	return &latestHeight, nil
+}
+
+func (store *InMemoryStore) GetAllByHeight(height int64) ([]entity_event.Event, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	events := make([]entity_event.Event, len(store.eventsByHeight[height]))
+	copy(events, store.eventsByHeight[height])
+	return events, nil
+}
+
+func (store *InMemoryStore) GetAllByHeightRange(
+	fromHeight int64, toHeight int64,
+) (entity_event.HeightRangeCursor, error) {
+	return entity_event.NewHeightByHeightCursor(store.GetAllByHeight, fromHeight, toHeight), nil
+}
+
+func (store *InMemoryStore) Insert(event entity_event.Event) error {
+	return store.InsertAll([]entity_event.Event{event})
+}
+
+// InsertAll insert all events into store. The events already stored are skipped, see RDbStore.InsertAll.
+func (store *InMemoryStore) InsertAll(events []entity_event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, event := range events {
+		if store.hasEvent(event) {
+			continue
+		}
+
+		height := event.Height()
+		store.eventsByHeight[height] = append(store.eventsByHeight[height], event)
+		if store.maybeLatestHeight == nil || height > *store.maybeLatestHeight {
+			latestHeight := height
+			store.maybeLatestHeight = &latestHeight
+		}
+	}
+
+	store.notifySubscribers(latestEventHeight(events))
+	return nil
+}
+
+func (store *InMemoryStore) hasEvent(event entity_event.Event) bool {
+	for _, storedEvent := range store.eventsByHeight[event.Height()] {
+		if storedEvent.UUID() == event.UUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteAllAfterHeight deletes all events after the provided height. It is used to roll back the event
+// store on chain reorganisation.
+func (store *InMemoryStore) DeleteAllAfterHeight(height int64) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var maybeLatestHeight *int64
+	for eventHeight := range store.eventsByHeight {
+		if eventHeight > height {
+			delete(store.eventsByHeight, eventHeight)
+			continue
+		}
+		if maybeLatestHeight == nil || eventHeight > *maybeLatestHeight {
+			latestHeight := eventHeight
+			maybeLatestHeight = &latestHeight
+		}
+	}
+	store.maybeLatestHeight = maybeLatestHeight
+}
+
+// SubscribeHeights sends the height of newly inserted events to heightCh until the context is
+// cancelled. Heights are dropped when heightCh is not ready to receive.
+func (store *InMemoryStore) SubscribeHeights(ctx context.Context, heightCh chan<- int64) error {
+	store.mutex.Lock()
+	store.subscribers[heightCh] = struct{}{}
+	store.mutex.Unlock()
+
+	<-ctx.Done()
+
+	store.mutex.Lock()
+	delete(store.subscribers, heightCh)
+	store.mutex.Unlock()
+	return nil
+}
+
+func (store *InMemoryStore) notifySubscribers(height int64) {
+	for heightCh := range store.subscribers {
+		select {
+		case heightCh <- height:
+		default:
+		}
+	}
+}
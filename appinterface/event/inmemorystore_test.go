@@ -0,0 +1,81 @@
+package event_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appinterface_event "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+var _ = Describe("InMemoryStore", func() {
+	It("should return nil latest height when no event is stored", func() {
+		store := appinterface_event.NewInMemoryStore()
+
+		latestHeight, err := store.GetLatestHeight()
+		Expect(err).To(BeNil())
+		Expect(latestHeight).To(BeNil())
+	})
+
+	It("should return the inserted events by height", func() {
+		store := appinterface_event.NewInMemoryStore()
+		events := []event.Event{newHeightEvent(1), newHeightEvent(3), newHeightEvent(3)}
+		Expect(store.InsertAll(events)).To(BeNil())
+
+		Expect(store.GetLatestHeight()).To(Equal(primptr.Int64(3)))
+		Expect(store.GetAllByHeight(3)).To(Equal(events[1:]))
+
+		cursor, err := store.GetAllByHeightRange(1, 3)
+		Expect(err).To(BeNil())
+		eventCounts := make(map[int64]int)
+		for cursor.Next() {
+			eventCounts[cursor.Height()] = len(cursor.Events())
+		}
+		Expect(eventCounts).To(Equal(map[int64]int{1: 1, 2: 0, 3: 2}))
+	})
+
+	It("should skip the events already inserted", func() {
+		store := appinterface_event.NewInMemoryStore()
+		anyEvent := newHeightEvent(1)
+		Expect(store.Insert(anyEvent)).To(BeNil())
+
+		anotherEvent := newHeightEvent(2)
+		Expect(store.InsertAll([]event.Event{anotherEvent, anyEvent})).To(BeNil())
+		Expect(store.GetAllByHeight(1)).To(Equal([]event.Event{anyEvent}))
+		Expect(store.GetAllByHeight(2)).To(Equal([]event.Event{anotherEvent}))
+	})
+
+	It("should delete the events after height", func() {
+		store := appinterface_event.NewInMemoryStore()
+		Expect(store.InsertAll([]event.Event{newHeightEvent(1), newHeightEvent(2), newHeightEvent(3)})).To(BeNil())
+
+		store.DeleteAllAfterHeight(1)
+
+		Expect(store.GetLatestHeight()).To(Equal(primptr.Int64(1)))
+		Expect(store.GetAllByHeight(2)).To(BeEmpty())
+	})
+
+	It("should notify the height of inserted events to subscribers", func() {
+		store := appinterface_event.NewInMemoryStore()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		heightCh := make(chan int64, 1)
+		go func() {
+			_ = store.SubscribeHeights(ctx, heightCh)
+		}()
+
+		Eventually(func() int64 {
+			_ = store.Insert(newHeightEvent(5))
+			select {
+			case height := <-heightCh:
+				return height
+			default:
+				return 0
+			}
+		}).Should(Equal(int64(5)))
+	})
+})
@@ -0,0 +1,246 @@
+package event
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+const DEFAULT_IMPORT_BATCH_SIZE = 1000
+
+// maxNDJSONLineSize is the maximum size of a record line, large enough for raw block events
+const maxNDJSONLineSize = 64 * 1024 * 1024
+
+// Record is an event as stored in the events table. It is the line format of NDJSON export.
+type Record struct {
+	UUID    string              `json:"uuid"`
+	Height  int64               `json:"height"`
+	Name    string              `json:"name"`
+	Version int                 `json:"version"`
+	Payload jsoniter.RawMessage `json:"payload"`
+}
+
+// ExportNDJSON writes the stored events from `fromHeight` to `toHeight` inclusively to writer as
+// newline-delimited JSON records in height order. It returns the number of exported records.
+func (store *RDbStore) ExportNDJSON(writer io.Writer, fromHeight int64, toHeight int64) (int64, error) {
+	sql, args, err := store.selectEvents(
+		"uuid", "height", "name", "version", "payload",
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).OrderBy("height", "id").ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building events export selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing events export selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	bufferedWriter := bufio.NewWriter(writer)
+	count := int64(0)
+	for rows.Next() {
+		var (
+			record  Record
+			payload string
+
+			maybeArchivedPayload []byte
+		)
+		if err = rows.Scan(
+			&record.UUID, &record.Height, &record.Name, &record.Version, &payload, &maybeArchivedPayload,
+		); err != nil {
+			return count, fmt.Errorf("error scanning event to export: %v", err)
+		}
+		if payload, err = rehydratePayload(payload, maybeArchivedPayload); err != nil {
+			return count, fmt.Errorf("error rehydrating event %s to export: %v", record.UUID, err)
+		}
+		record.Payload = jsoniter.RawMessage(payload)
+
+		encoded, err := jsoniter.Marshal(record)
+		if err != nil {
+			return count, fmt.Errorf("error encoding event %s: %v", record.UUID, err)
+		}
+		if _, err = bufferedWriter.Write(append(encoded, '\n')); err != nil {
+			return count, fmt.Errorf("error writing event %s: %v", record.UUID, err)
+		}
+		count += 1
+	}
+	if err = rows.Err(); err != nil {
+		return count, fmt.Errorf("error iterating events to export: %v", err)
+	}
+
+	if err = bufferedWriter.Flush(); err != nil {
+		return count, fmt.Errorf("error writing events: %v", err)
+	}
+	return count, nil
+}
+
+// ImportNDJSON reads newline-delimited JSON records from reader and inserts them height by height.
+// Every record is validated by decoding it with the registry, but the record is stored as is, without
+// the upcasting applied on decoding. The records of a height are inserted in batches of batchSize
+// together with the event digest of the height in a single transaction. Records already in the store
+// are skipped, so that importing the same records again is harmless. It returns the number of inserted
+// records.
+func (store *RDbStore) ImportNDJSON(rdbConn rdb.Conn, reader io.Reader, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = DEFAULT_IMPORT_BATCH_SIZE
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	count := int64(0)
+	line := 0
+	heightRecords := make([]Record, 0)
+	for scanner.Scan() {
+		line += 1
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		record, err := store.decodeRecord(scanner.Bytes())
+		if err != nil {
+			return count, fmt.Errorf("error validating record at line %d: %v", line, err)
+		}
+
+		if len(heightRecords) > 0 && heightRecords[0].Height != record.Height {
+			insertedCount, err := store.importHeight(rdbConn, heightRecords, batchSize)
+			if err != nil {
+				return count, fmt.Errorf("error importing records before line %d: %v", line, err)
+			}
+			count += insertedCount
+			heightRecords = heightRecords[:0]
+		}
+		heightRecords = append(heightRecords, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("error reading records after line %d: %v", line, err)
+	}
+
+	if len(heightRecords) > 0 {
+		insertedCount, err := store.importHeight(rdbConn, heightRecords, batchSize)
+		if err != nil {
+			return count, fmt.Errorf("error importing records: %v", err)
+		}
+		count += insertedCount
+	}
+
+	return count, nil
+}
+
+// importHeight inserts the records of a height and stores the event digest of the height chained with
+// the digest of the previous height in a single transaction. The digest is computed from all the stored
+// events of the height. An existing digest is kept when no record is inserted, so that importing does
+// not hide an alteration of the stored events.
+func (store *RDbStore) importHeight(rdbConn rdb.Conn, records []Record, batchSize int) (int64, error) {
+	height := records[0].Height
+
+	tx, err := rdbConn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	txHandle := tx.ToHandle()
+
+	count := int64(0)
+	for batchStart := 0; batchStart < len(records); batchStart += batchSize {
+		batchEnd := batchStart + batchSize
+		if batchEnd > len(records) {
+			batchEnd = len(records)
+		}
+
+		insertedCount, err := store.insertRecordsWithRDbHandle(txHandle, records[batchStart:batchEnd])
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("error inserting records at height %d: %v", height, err)
+		}
+		count += insertedCount
+	}
+
+	maybeDigest, err := store.digestStore.findByHeightWithRDbHandle(txHandle, height)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("error getting event digest at height %d: %v", height, err)
+	}
+	if count > 0 || maybeDigest == nil {
+		if err = store.insertStoredEventsDigestWithRDbHandle(txHandle, height); err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing records at height %d: %v", height, err)
+	}
+	return count, nil
+}
+
+// decodeRecord decodes the record and checks that its payload decodes to the event it describes
+func (store *RDbStore) decodeRecord(encoded []byte) (Record, error) {
+	var record Record
+	if err := jsoniter.Unmarshal(encoded, &record); err != nil {
+		return Record{}, fmt.Errorf("error decoding record: %v", err)
+	}
+
+	event, err := store.Registry.DecodeByType(record.Name, record.Version, record.Payload)
+	if err != nil {
+		return Record{}, err
+	}
+	if event.UUID() != record.UUID || event.Height() != record.Height {
+		return Record{}, fmt.Errorf(
+			"mismatched event %s at height %d with record %s at height %d",
+			event.UUID(), event.Height(), record.UUID, record.Height,
+		)
+	}
+
+	return record, nil
+}
+
+// insertRecordsWithRDbHandle inserts the records not in the store yet and returns the number of
+// inserted records
+func (store *RDbStore) insertRecordsWithRDbHandle(rdbHandle *rdb.Handle, records []Record) (int64, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	stmtBuilder := rdbHandle.StmtBuilder.Insert(
+		store.table,
+	).Columns(
+		"uuid", "height", "name", "version", "payload",
+	)
+	latestHeight := records[0].Height
+	for _, record := range records {
+		stmtBuilder = stmtBuilder.Values(
+			record.UUID,
+			record.Height,
+			record.Name,
+			record.Version,
+			string(record.Payload),
+		)
+		if record.Height > latestHeight {
+			latestHeight = record.Height
+		}
+	}
+	sql, args, err := stmtBuilder.Suffix("ON CONFLICT (uuid) DO NOTHING").ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building records insertion SQL: %v", err)
+	}
+
+	result, err := rdbHandle.Exec(sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing records insertion SQL: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	// the notification is delivered when the transaction is committed
+	if err := store.notifyInsertedHeight(rdbHandle, latestHeight); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
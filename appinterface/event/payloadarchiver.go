@@ -0,0 +1,79 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+const PAYLOAD_ARCHIVE_INTERVAL = time.Minute
+
+// PAYLOAD_ARCHIVE_BATCH_SIZE is the number of payloads archived at a time. Payloads to archive are
+// usually large, e.g. raw blocks.
+const PAYLOAD_ARCHIVE_BATCH_SIZE = 100
+
+// PayloadArchiver periodically archives the payloads of an event type which are more than
+// retentionDepth heights behind the latest event height to cold storage
+type PayloadArchiver struct {
+	logger applogger.Logger
+
+	rdbConn        rdb.Conn
+	store          *RDbStore
+	eventName      string
+	retentionDepth int64
+}
+
+func NewPayloadArchiver(
+	logger applogger.Logger, rdbConn rdb.Conn, store *RDbStore, eventName string, retentionDepth int64,
+) *PayloadArchiver {
+	return &PayloadArchiver{
+		logger: logger.WithFields(applogger.LogFields{
+			"module":    "PayloadArchiver",
+			"eventName": eventName,
+		}),
+
+		rdbConn:        rdbConn,
+		store:          store,
+		eventName:      eventName,
+		retentionDepth: retentionDepth,
+	}
+}
+
+// Run archives the payloads every PAYLOAD_ARCHIVE_INTERVAL until the context is cancelled. Errors are
+// logged and retried on the next run.
+func (archiver *PayloadArchiver) Run(ctx context.Context) {
+	for {
+		archiver.archive()
+
+		select {
+		case <-time.After(PAYLOAD_ARCHIVE_INTERVAL):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (archiver *PayloadArchiver) archive() {
+	maybeLatestHeight, err := archiver.store.GetLatestHeight()
+	if err != nil {
+		archiver.logger.Errorf("error getting latest event height: %v", err)
+		return
+	}
+	if maybeLatestHeight == nil {
+		return
+	}
+
+	beforeHeight := *maybeLatestHeight - archiver.retentionDepth
+	count, err := archiver.store.ArchivePayloads(
+		archiver.rdbConn, archiver.eventName, beforeHeight, PAYLOAD_ARCHIVE_BATCH_SIZE,
+	)
+	if err != nil {
+		archiver.logger.Errorf("error archiving payloads before height %d: %v", beforeHeight, err)
+		return
+	}
+	if count > 0 {
+		archiver.logger.Infof("archived %d payloads before height %d", count, beforeHeight)
+	}
+}
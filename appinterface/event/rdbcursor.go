@@ -0,0 +1,119 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+var _ entity_event.HeightRangeCursor = &RDbHeightRangeCursor{}
+
+// RDbHeightRangeCursor groups the event rows of a height range query by height. Heights without any
+// row are visited with no events.
+type RDbHeightRangeCursor struct {
+	rows     rdb.RowsResult
+	registry *entity_event.Registry
+	toHeight int64
+
+	height int64
+	events []entity_event.Event
+	err    error
+
+	// maybePendingEvent is the event read ahead which belongs to a later height
+	maybePendingEvent entity_event.Event
+	rowsDone          bool
+}
+
+func newRDbHeightRangeCursor(
+	rows rdb.RowsResult, registry *entity_event.Registry, fromHeight int64, toHeight int64,
+) *RDbHeightRangeCursor {
+	return &RDbHeightRangeCursor{
+		rows:     rows,
+		registry: registry,
+		toHeight: toHeight,
+
+		height: fromHeight - 1,
+	}
+}
+
+func (cursor *RDbHeightRangeCursor) Next() bool {
+	if cursor.err != nil || cursor.height >= cursor.toHeight {
+		return false
+	}
+
+	cursor.height += 1
+	cursor.events = make([]entity_event.Event, 0)
+	if cursor.maybePendingEvent != nil {
+		if cursor.maybePendingEvent.Height() != cursor.height {
+			return true
+		}
+		cursor.events = append(cursor.events, cursor.maybePendingEvent)
+		cursor.maybePendingEvent = nil
+	}
+
+	for !cursor.rowsDone {
+		if !cursor.rows.Next() {
+			cursor.rowsDone = true
+			if err := cursor.rows.Err(); err != nil {
+				cursor.err = fmt.Errorf("error iterating events by height range: %v", err)
+				return false
+			}
+			break
+		}
+
+		event, err := cursor.scanEvent()
+		if err != nil {
+			cursor.err = err
+			return false
+		}
+		if event.Height() != cursor.height {
+			cursor.maybePendingEvent = event
+			break
+		}
+		cursor.events = append(cursor.events, event)
+	}
+
+	return true
+}
+
+func (cursor *RDbHeightRangeCursor) scanEvent() (entity_event.Event, error) {
+	var (
+		height  int64
+		name    string
+		version int
+		payload string
+
+		maybeArchivedPayload []byte
+	)
+	if err := cursor.rows.Scan(&height, &name, &version, &payload, &maybeArchivedPayload); err != nil {
+		return nil, fmt.Errorf("error scanning event by height range: %v", err)
+	}
+	payload, err := rehydratePayload(payload, maybeArchivedPayload)
+	if err != nil {
+		return nil, fmt.Errorf("error rehydrating event at height %d: %v", height, err)
+	}
+
+	event, err := cursor.registry.DecodeByType(name, version, []byte(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the event string into type: %v", err)
+	}
+
+	return event, nil
+}
+
+func (cursor *RDbHeightRangeCursor) Height() int64 {
+	return cursor.height
+}
+
+func (cursor *RDbHeightRangeCursor) Events() []entity_event.Event {
+	return cursor.events
+}
+
+func (cursor *RDbHeightRangeCursor) Err() error {
+	return cursor.err
+}
+
+func (cursor *RDbHeightRangeCursor) Close() {
+	cursor.rows.Close()
+}
@@ -1,8 +1,12 @@
 package event
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
 
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	entity_event "github.com/crypto-com/chain-indexing/entity/event"
@@ -20,13 +24,18 @@ const DEFAULT_TABLE = "events"
 // | payload | JSONB     | NOT NULL    |
 
 var _ entity_event.Store = &RDbStore{}
+var _ entity_event.HeightNotifier = &RDbStore{}
 
 // EventStore implemented using relational database
 type RDbStore struct {
 	rdbHandle *rdb.Handle
 	Registry  *entity_event.Registry
 
-	table string
+	table       string
+	coldTable   string
+	digestStore *RDbDigestStore
+	// notificationChannel is notified with the height of events on insertion
+	notificationChannel string
 }
 
 func NewRDbStore(handle *rdb.Handle, registry *entity_event.Registry) *RDbStore {
@@ -34,7 +43,10 @@ func NewRDbStore(handle *rdb.Handle, registry *entity_event.Registry) *RDbStore
 		rdbHandle: handle,
 		Registry:  registry,
 
-		table: DEFAULT_TABLE,
+		table:               DEFAULT_TABLE,
+		coldTable:           DEFAULT_COLD_TABLE,
+		digestStore:         NewRDbDigestStore(handle),
+		notificationChannel: DEFAULT_TABLE + "_inserted",
 	}
 }
 
@@ -62,10 +74,8 @@ func (store *RDbStore) GetLatestHeight() (*int64, error) {
 }
 
 func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error) {
-	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+	sql, args, err := store.selectEvents(
 		"uuid", "height", "name", "version", "payload",
-	).From(
-		store.table,
 	).Where(
 		"height = ?", height,
 	).OrderBy("id").ToSql()
@@ -85,15 +95,21 @@ func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error
 			name    string
 			version int
 			payload string
+
+			maybeArchivedPayload []byte
 		)
 
-		if err := rows.Scan(&uuid, &height, &name, &version, &payload); err != nil {
+		if err := rows.Scan(&uuid, &height, &name, &version, &payload, &maybeArchivedPayload); err != nil {
 			if errors.Is(err, rdb.ErrNoRows) {
 				return nil, nil
 			} else {
 				return nil, fmt.Errorf("error executing get each event by height selection SQL: %v", err)
 			}
 		}
+		payload, err := rehydratePayload(payload, maybeArchivedPayload)
+		if err != nil {
+			return nil, fmt.Errorf("error rehydrating event %s: %v", uuid, err)
+		}
 
 		event, err := store.Registry.DecodeByType(name, version, []byte(payload))
 		if err != nil {
@@ -106,43 +122,100 @@ func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error
 	return events, nil
 }
 
-func (store *RDbStore) Insert(event entity_event.Event) error {
-	encodedEvent, err := event.ToJSON()
+// GetAllByHeightRange returns a cursor streaming the events from `fromHeight` to `toHeight` inclusively
+// in a single query. The cursor must be closed after use.
+func (store *RDbStore) GetAllByHeightRange(
+	fromHeight int64, toHeight int64,
+) (entity_event.HeightRangeCursor, error) {
+	sql, args, err := store.selectEvents(
+		"height", "name", "version", "payload",
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).OrderBy("height", "id").ToSql()
 	if err != nil {
-		return fmt.Errorf("error encoding event to json: %v", err)
+		return nil, fmt.Errorf("error building get all events by height range selection SQL: %v", err)
 	}
-	sql, args, err := store.rdbHandle.StmtBuilder.Insert(
-		store.table,
-	).Columns(
-		"uuid", "height", "name", "version", "payload",
-	).Values(
-		event.UUID(),
-		event.Height(),
-		event.Name(),
-		event.Version(),
-		encodedEvent,
-	).ToSql()
+
+	rows, err := store.rdbHandle.Query(sql, args...)
 	if err != nil {
-		return fmt.Errorf("error building event insertion SQL: %v", err)
+		return nil, fmt.Errorf("error executing get all events by height range selection SQL: %v", err)
 	}
 
-	execResult, err := store.rdbHandle.Exec(sql, args...)
+	return newRDbHeightRangeCursor(rows, store.Registry, fromHeight, toHeight), nil
+}
+
+// SubscribeHeights sends the height of newly inserted events to heightCh until the context is
+// cancelled. It requires the store handle to support listening to notifications.
+func (store *RDbStore) SubscribeHeights(ctx context.Context, heightCh chan<- int64) error {
+	listener, ok := store.rdbHandle.Runner.(rdb.Listener)
+	if !ok {
+		return errors.New("error subscribing event heights: RDb handle does not support notifications")
+	}
+
+	payloadCh := make(chan string)
+	listenErrCh := make(chan error, 1)
+	go func() {
+		listenErrCh <- listener.Listen(ctx, store.notificationChannel, payloadCh)
+	}()
+
+	for {
+		select {
+		case payload := <-payloadCh:
+			height, err := strconv.ParseInt(payload, 10, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing notified event height %q: %v", payload, err)
+			}
+			select {
+			case heightCh <- height:
+			case <-ctx.Done():
+				return nil
+			}
+		case err := <-listenErrCh:
+			return err
+		}
+	}
+}
+
+func (store *RDbStore) notifyInsertedHeight(rdbHandle *rdb.Handle, height int64) error {
+	sql, args, err := rdbHandle.StmtBuilder.Select().Column(
+		sq.Expr("pg_notify(?, ?)", store.notificationChannel, strconv.FormatInt(height, 10)),
+	).ToSql()
 	if err != nil {
-		return fmt.Errorf("error exectuing event insertion SQL: %v", err)
+		return fmt.Errorf("error building event insertion notification SQL: %v", err)
 	}
-	if execResult.RowsAffected() == 0 {
-		return errors.New("error executing event insertion SQL: no rows inserted")
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing event insertion notification SQL: %v", err)
 	}
 
 	return nil
 }
 
-// InsertAll insert all events into store. It will rollback when the insert fails at any point.
+func latestEventHeight(events []entity_event.Event) int64 {
+	height := events[0].Height()
+	for _, event := range events[1:] {
+		if event.Height() > height {
+			height = event.Height()
+		}
+	}
+
+	return height
+}
+
+// Insert inserts the event into store. The event is skipped when an event of the same UUID is already
+// stored.
+func (store *RDbStore) Insert(event entity_event.Event) error {
+	return store.InsertAll([]entity_event.Event{event})
+}
+
+// InsertAll insert all events into store. It will rollback when the insert fails at any point. Events
+// are identified by their deterministic UUIDs, the events already stored are skipped so that re-inserting
+// the events of a re-synced block is idempotent.
 func (store *RDbStore) InsertAll(events []entity_event.Event) error {
 	return store.InsertAllWithRDbHandle(store.rdbHandle, events)
 }
 
-// InsertAll insert all events into store. It will rollback when the insert fails at any point.
+// InsertAllWithRDbHandle insert all events into store with the provided handle, see InsertAll
 func (store *RDbStore) InsertAllWithRDbHandle(rdbHandle *rdb.Handle, events []entity_event.Event) error {
 	if len(events) == 0 {
 		return nil
@@ -166,17 +239,63 @@ func (store *RDbStore) InsertAllWithRDbHandle(rdbHandle *rdb.Handle, events []en
 			encodedEvent,
 		)
 	}
+	stmtBuilder = stmtBuilder.Suffix("ON CONFLICT (uuid) DO NOTHING")
 	sql, args, err := stmtBuilder.ToSql()
 	if err != nil {
 		return fmt.Errorf("error building event insertion SQL: %v", err)
 	}
 
-	execResult, err := rdbHandle.Exec(sql, args...)
-	if err != nil {
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
 		return fmt.Errorf("error exectuing event insertion SQL: %v", err)
 	}
-	if execResult.RowsAffected() != int64(len(events)) {
-		return errors.New("error executing event insertion SQL: mismatched number of rows inserted")
+
+	// the notification is delivered when the transaction is committed
+	if err := store.notifyInsertedHeight(rdbHandle, latestEventHeight(events)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAllAfterHeightWithRDbHandle deletes all events and digests after the provided height. It is
+// used to roll back the event store on chain reorganisation.
+func (store *RDbStore) DeleteAllAfterHeightWithRDbHandle(rdbHandle *rdb.Handle, height int64) error {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		store.table,
+	).Where(
+		"height > ?", height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building events deletion SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing events deletion SQL: %v", err)
+	}
+	if err = store.digestStore.deleteWithRDbHandle(rdbHandle, "height > ?", height); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAllByHeightWithRDbHandle deletes all events and the digest at the provided height. It is used
+// to replace the events of a re-synchronized block.
+func (store *RDbStore) DeleteAllByHeightWithRDbHandle(rdbHandle *rdb.Handle, height int64) error {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		store.table,
+	).Where(
+		"height = ?", height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building events deletion SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing events deletion SQL: %v", err)
+	}
+	if err = store.digestStore.deleteWithRDbHandle(rdbHandle, "height = ?", height); err != nil {
+		return err
 	}
 
 	return nil
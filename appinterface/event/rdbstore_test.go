@@ -1,6 +1,13 @@
 package event_test
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+
 	"github.com/crypto-com/chain-indexing/entity/event/test"
 	. "github.com/crypto-com/chain-indexing/test"
 	. "github.com/onsi/ginkgo"
@@ -43,6 +50,20 @@ var _ = Describe("RdbEventStore", func() {
 				err := store.InsertAll(events)
 				Expect(err).To(BeNil())
 			})
+
+			It("should skip the events already inserted", func() {
+				registry := event.NewRegistry()
+				registry.Register("HeightEvent", 1, decodeHeightEvent)
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				insertedEvent := newHeightEvent(1)
+				Expect(store.InsertAll([]event.Event{insertedEvent})).To(BeNil())
+
+				anotherEvent := newHeightEvent(1)
+				Expect(store.InsertAll([]event.Event{insertedEvent, anotherEvent})).To(BeNil())
+
+				Expect(store.GetAllByHeight(1)).To(ConsistOf(insertedEvent, anotherEvent))
+			})
 		})
 
 		Describe("GetLatestHeight", func() {
@@ -74,5 +95,191 @@ var _ = Describe("RdbEventStore", func() {
 				Expect(latestHeight).To(Equal(primptr.Int64(1)))
 			})
 		})
+
+		Describe("GetAllByHeightRange", func() {
+			It("should stream the events of every height in the range", func() {
+				registry := event.NewRegistry()
+				registry.Register("HeightEvent", 1, decodeHeightEvent)
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				Expect(store.InsertAll([]event.Event{
+					newHeightEvent(1), newHeightEvent(2), newHeightEvent(2), newHeightEvent(4), newHeightEvent(5),
+				})).To(BeNil())
+
+				cursor, err := store.GetAllByHeightRange(2, 4)
+				Expect(err).To(BeNil())
+				defer cursor.Close()
+
+				eventCounts := make(map[int64]int)
+				for cursor.Next() {
+					eventCounts[cursor.Height()] = len(cursor.Events())
+				}
+				Expect(cursor.Err()).To(BeNil())
+				Expect(eventCounts).To(Equal(map[int64]int{2: 2, 3: 0, 4: 1}))
+			})
+		})
+
+		Describe("InsertDigestWithRDbHandle and VerifyDigests", func() {
+			It("should detect the events altered after the digests are stored", func() {
+				registry := event.NewRegistry()
+				registry.Register("HeightEvent", 1, decodeHeightEvent)
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				for height := int64(1); height <= 3; height++ {
+					events := []event.Event{newHeightEvent(height), newHeightEvent(height)}
+					Expect(store.InsertAll(events)).To(BeNil())
+					Expect(store.InsertDigestWithRDbHandle(pgxConn.ToHandle(), height, events)).To(BeNil())
+				}
+				Expect(store.GetDigest(2)).NotTo(BeNil())
+
+				mismatches, err := store.VerifyDigests(1, 3)
+				Expect(err).To(BeNil())
+				Expect(mismatches).To(BeEmpty())
+
+				_, err = pgxConn.Exec("UPDATE events SET payload = payload || '{\"extra\":1}' WHERE height = 2")
+				Expect(err).To(BeNil())
+
+				mismatches, err = store.VerifyDigests(1, 3)
+				Expect(err).To(BeNil())
+				Expect(mismatches).To(HaveLen(1))
+				Expect(mismatches[0].Height).To(Equal(int64(2)))
+			})
+		})
+
+		Describe("ArchivePayloads", func() {
+			It("should rehydrate the archived payloads transparently", func() {
+				registry := event.NewRegistry()
+				registry.Register("HeightEvent", 1, decodeHeightEvent)
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				events := []event.Event{newHeightEvent(1), newHeightEvent(2), newHeightEvent(3)}
+				for _, anyEvent := range events {
+					Expect(store.InsertAll([]event.Event{anyEvent})).To(BeNil())
+					Expect(store.InsertDigestWithRDbHandle(
+						pgxConn.ToHandle(), anyEvent.Height(), []event.Event{anyEvent},
+					)).To(BeNil())
+				}
+
+				count, err := store.ArchivePayloads(pgxConn, "HeightEvent", 3, 1)
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(2)))
+				count, err = store.ArchivePayloads(pgxConn, "HeightEvent", 3, 1)
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(0)))
+
+				Expect(store.GetAllByHeight(1)).To(Equal([]event.Event{events[0]}))
+				cursor, err := store.GetAllByHeightRange(1, 3)
+				Expect(err).To(BeNil())
+				defer cursor.Close()
+				for cursor.Next() {
+					Expect(cursor.Events()).To(Equal([]event.Event{events[cursor.Height()-1]}))
+				}
+				Expect(cursor.Err()).To(BeNil())
+
+				mismatches, err := store.VerifyDigests(1, 3)
+				Expect(err).To(BeNil())
+				Expect(mismatches).To(BeEmpty())
+			})
+		})
+
+		Describe("ExportNDJSON and ImportNDJSON", func() {
+			It("should import the exported events idempotently", func() {
+				registry := event.NewRegistry()
+				registry.Register("HeightEvent", 1, decodeHeightEvent)
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				events := []event.Event{newHeightEvent(1), newHeightEvent(2), newHeightEvent(3)}
+				Expect(store.InsertAll(events)).To(BeNil())
+
+				var exported bytes.Buffer
+				count, err := store.ExportNDJSON(&exported, 2, 3)
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(2)))
+				Expect(strings.Count(exported.String(), "\n")).To(Equal(2))
+
+				_ = pgMigrate.Reset()
+				pgMigrate.MustUp()
+
+				count, err = store.ImportNDJSON(pgxConn, bytes.NewReader(exported.Bytes()), 1)
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(2)))
+				count, err = store.ImportNDJSON(pgxConn, bytes.NewReader(exported.Bytes()), 1)
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(0)))
+
+				importedEvents, err := store.GetAllByHeight(2)
+				Expect(err).To(BeNil())
+				Expect(importedEvents).To(Equal([]event.Event{events[1]}))
+				latestHeight, err := store.GetLatestHeight()
+				Expect(err).To(BeNil())
+				Expect(latestHeight).To(Equal(primptr.Int64(3)))
+
+				mismatches, err := store.VerifyDigests(2, 3)
+				Expect(err).To(BeNil())
+				Expect(mismatches).To(BeEmpty())
+			})
+
+			It("should import the records as is without upcasting", func() {
+				registry := event.NewRegistry()
+				registry.Register("HeightEvent", 2, decodeHeightEvent)
+				registry.RegisterUpcaster("HeightEvent", 1, func(encoded []byte) ([]byte, error) {
+					return encoded, nil
+				})
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				record := `{"uuid":"any-uuid","height":1,"name":"HeightEvent","version":1,` +
+					`"payload":{"height":1,"uuid":"any-uuid"}}` + "\n"
+				count, err := store.ImportNDJSON(pgxConn, strings.NewReader(record), 1)
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(int64(1)))
+
+				var exported bytes.Buffer
+				_, err = store.ExportNDJSON(&exported, 1, 1)
+				Expect(err).To(BeNil())
+				var exportedRecord appinterface_event.Record
+				Expect(jsoniter.Unmarshal(exported.Bytes(), &exportedRecord)).To(BeNil())
+				Expect(exportedRecord.Version).To(Equal(1))
+				Expect(exportedRecord.Payload).To(MatchJSON(`{"height":1,"uuid":"any-uuid"}`))
+			})
+
+			It("should reject the records which cannot be decoded", func() {
+				registry := event.NewRegistry()
+				store := appinterface_event.NewRDbStore(pgxConn.ToHandle(), registry)
+
+				_, err := store.ImportNDJSON(pgxConn, strings.NewReader(
+					`{"uuid":"any-uuid","height":1,"name":"HeightEvent","version":1,"payload":{}}`+"\n",
+				), 1)
+				Expect(err).To(MatchError("error validating record at line 1: unrecognized event type `HeightEventV1`"))
+			})
+		})
 	})
 })
+
+type heightEvent struct {
+	EventHeight int64  `json:"height"`
+	EventUUID   string `json:"uuid"`
+}
+
+func newHeightEvent(height int64) *heightEvent {
+	return &heightEvent{
+		EventHeight: height,
+		EventUUID:   uuid.New().String(),
+	}
+}
+
+func decodeHeightEvent(encoded []byte) (event.Event, error) {
+	var evt heightEvent
+	if err := jsoniter.Unmarshal(encoded, &evt); err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+func (evt *heightEvent) Height() int64  { return evt.EventHeight }
+func (evt *heightEvent) Name() string   { return "HeightEvent" }
+func (evt *heightEvent) Version() int   { return 1 }
+func (evt *heightEvent) UUID() string   { return evt.EventUUID }
+func (evt *heightEvent) String() string { return fmt.Sprintf("HeightEvent(%d)", evt.EventHeight) }
+func (evt *heightEvent) ToJSON() (string, error) {
+	return jsoniter.MarshalToString(evt)
+}
@@ -0,0 +1,575 @@
+package eventhandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+const DEFAULT_FAN_OUT_QUEUE_SIZE = 1000
+
+// errHeightQuarantined is returned while the projection waits at a quarantined height
+var errHeightQuarantined = errors.New("height is quarantined")
+
+var _ RollbackableHandler = &FanOutHandler{}
+var _ projection_entity.Resetter = &FanOutHandler{}
+
+// BlockEventsFetcher fetches and parses the block at height to events
+type BlockEventsFetcher = func(height int64) ([]event.Event, error)
+
+// FanOutHandler dispatches the events of a single sync pipeline to multiple projections. Each
+// projection handles events in its own goroutine from a buffered queue and advances independently.
+// When the queue of a slow projection is full, events are dropped for that projection and it catches
+// up later by fetching the missed blocks itself, so that it never blocks the others.
+type FanOutHandler struct {
+	logger applogger.Logger
+
+	workers []*fanOutWorker
+
+	mutex                sync.Mutex
+	lastDispatchedHeight *int64
+}
+
+func NewFanOutHandler(logger applogger.Logger, projectionHandlers []*ProjectionHandler) *FanOutHandler {
+	logger = logger.WithFields(applogger.LogFields{
+		"module": "FanOutHandler",
+	})
+
+	workers := make([]*fanOutWorker, 0, len(projectionHandlers))
+	for _, projectionHandler := range projectionHandlers {
+		workers = append(workers, &fanOutWorker{
+			logger: logger.WithFields(applogger.LogFields{
+				"projection": projectionHandler.projection.Id(),
+			}),
+			handler: projectionHandler,
+
+			queueCh: make(chan fanOutItem, DEFAULT_FAN_OUT_QUEUE_SIZE),
+		})
+	}
+
+	for _, worker := range workers {
+		versionBuilder, ok := worker.handler.projection.(projection_entity.VersionBuilder)
+		if !ok {
+			continue
+		}
+		for _, liveWorker := range workers {
+			if liveWorker.handler.projection.Id() == versionBuilder.LiveProjectionId() {
+				worker.liveWorker = liveWorker
+			}
+		}
+	}
+
+	return &FanOutHandler{
+		logger: logger,
+
+		workers: workers,
+	}
+}
+
+// WithStartHeight makes projections without any handled event start from the height instead of
+// genesis. It is used when the chain is indexed from a height after genesis.
+func (handler *FanOutHandler) WithStartHeight(height int64) *FanOutHandler {
+	for _, worker := range handler.workers {
+		worker.startHeight = height
+	}
+	return handler
+}
+
+// WithDeadLetterQueue quarantines a height to the dead letter queue after a projection fails to handle
+// it `maxHandleRetries` times. The projection is degraded and waits at the height until an operator
+// requests to retry or skip it. Non-positive `maxHandleRetries` retries forever. Projections halted
+// after chain reorganisation are recorded to the queue as well, so that they stay halted after restart
+// until they are reset.
+func (handler *FanOutHandler) WithDeadLetterQueue(
+	queue projection_entity.DeadLetterQueue, maxHandleRetries int,
+) *FanOutHandler {
+	for _, worker := range handler.workers {
+		worker.deadLetterQueue = queue
+		worker.maxHandleRetries = maxHandleRetries
+	}
+	return handler
+}
+
+// RunInBackground starts handling events for each projection. The fetcher is used by projections to
+// catch up missed blocks.
+func (handler *FanOutHandler) RunInBackground(fetcher BlockEventsFetcher) {
+	go handler.Run(context.Background(), fetcher)
+}
+
+// Run handles events for each projection until the context is cancelled. It returns after every
+// projection has finished handling its current height. Queued events are discarded and will be caught
+// up on next start.
+func (handler *FanOutHandler) Run(ctx context.Context, fetcher BlockEventsFetcher) {
+	var wg sync.WaitGroup
+	for _, worker := range handler.workers {
+		wg.Add(1)
+		go func(worker *fanOutWorker) {
+			defer wg.Done()
+			worker.run(ctx, fetcher)
+		}(worker)
+	}
+	wg.Wait()
+}
+
+// GetLastHandledEventHeight returns the last height dispatched to projections. On start, it is the
+// lowest last handled event height among all projections. Projection versions being built are not
+// taken into account since they catch up by fetching the blocks themselves.
+func (handler *FanOutHandler) GetLastHandledEventHeight() (*int64, error) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.lastDispatchedHeight != nil {
+		return handler.lastDispatchedHeight, nil
+	}
+
+	var minHeight *int64
+	for _, worker := range handler.workers {
+		if _, ok := worker.handler.projection.(projection_entity.VersionBuilder); ok {
+			continue
+		}
+		height, err := worker.handler.GetLastHandledEventHeight()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error getting last handled event height of projection `%s`: %v", worker.handler.projection.Id(), err,
+			)
+		}
+		if height == nil {
+			return nil, nil
+		}
+		if minHeight == nil || *height < *minHeight {
+			minHeight = height
+		}
+	}
+
+	return minHeight, nil
+}
+
+func (handler *FanOutHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	for _, worker := range handler.workers {
+		worker.enqueue(blockHeight, events)
+	}
+
+	handler.mutex.Lock()
+	handler.lastDispatchedHeight = &blockHeight
+	handler.mutex.Unlock()
+
+	return nil
+}
+
+// GetHandledBlock returns the block handled by the Block projection at height. It returns nil when
+// the Block projection is not enabled, so that reorganisation is not verified against the projections.
+func (handler *FanOutHandler) GetHandledBlock(height int64) (*HandledBlock, error) {
+	for _, worker := range handler.workers {
+		if worker.handler.projection.Id() != BLOCK_PROJECTION_ID {
+			continue
+		}
+		return worker.handler.GetHandledBlock(height)
+	}
+
+	return nil, nil
+}
+
+// RollbackTo reverts all projections to `height`. Projections not supporting rollback are reset and
+// replay the blocks from the start. Events queued before rollback are discarded.
+func (handler *FanOutHandler) RollbackTo(height int64) error {
+	errs := make([]string, 0)
+	for _, worker := range handler.workers {
+		if err := worker.rollbackTo(height); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	handler.mutex.Lock()
+	handler.lastDispatchedHeight = &height
+	handler.mutex.Unlock()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("error rolling back projections: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ResetProjection resets the projection so that it replays the blocks from the start. The blocks are
+// fetched again when the next block is dispatched. Other projections are not affected.
+func (handler *FanOutHandler) ResetProjection(projectionId string) error {
+	for _, worker := range handler.workers {
+		if worker.handler.projection.Id() == projectionId {
+			return worker.reset()
+		}
+	}
+
+	return fmt.Errorf("projection `%s` is not registered", projectionId)
+}
+
+type fanOutItem struct {
+	height     int64
+	events     []event.Event
+	generation int64
+}
+
+type fanOutWorker struct {
+	logger  applogger.Logger
+	handler *ProjectionHandler
+
+	queueCh chan fanOutItem
+	// startHeight is the next height when the projection has not handled any event
+	startHeight int64
+
+	// mutex guards the states below and is held while handling events
+	mutex sync.Mutex
+	// nextHeight is nil until it is loaded from the projection
+	nextHeight *int64
+	// generation is incremented on every rollback and reset so that queued events are discarded
+	generation int64
+	// halted is set when the projection fails to rollback and has to be rebuilt
+	halted bool
+	// haltLoaded is set once the halt recorded in the dead letter queue before restart is loaded
+	haltLoaded bool
+
+	// liveWorker is the worker of the live projection replaced when the projection is a version builder
+	liveWorker *fanOutWorker
+	// swappedIn is set when the version builder has replaced the live projection
+	swappedIn bool
+
+	// deadLetterQueue receives the heights still failing after maxHandleRetries attempts, and the halt
+	// of the projection. Failing heights are retried forever when it is nil or maxHandleRetries is
+	// non-positive.
+	deadLetterQueue  projection_entity.DeadLetterQueue
+	maxHandleRetries int
+	// failedHeight and failureCount track the consecutive failures of handling the same height
+	failedHeight int64
+	failureCount int
+	// quarantinedHeight is set while the projection is degraded and waits for the height to be retried
+	// or skipped
+	quarantinedHeight *int64
+	// retriedHeight is the quarantined height requested to retry, its dead letter is resolved once it
+	// is handled
+	retriedHeight *int64
+}
+
+func (worker *fanOutWorker) enqueue(height int64, events []event.Event) {
+	worker.mutex.Lock()
+	generation := worker.generation
+	worker.mutex.Unlock()
+
+	select {
+	case worker.queueCh <- fanOutItem{height, events, generation}:
+	default:
+		worker.logger.Infof("projection queue is full, block %d will be caught up later", height)
+	}
+}
+
+func (worker *fanOutWorker) run(ctx context.Context, fetcher BlockEventsFetcher) {
+	for {
+		select {
+		case item := <-worker.queueCh:
+			worker.handle(ctx, item, fetcher)
+		case <-ctx.Done():
+			worker.logger.Infof("projection stopped")
+			return
+		}
+	}
+}
+
+// handle handles the queued item, catching up any missed heights before it. It retries until the
+// item is handled or discarded, or the context is cancelled.
+func (worker *fanOutWorker) handle(ctx context.Context, item fanOutItem, fetcher BlockEventsFetcher) {
+	for ctx.Err() == nil {
+		done, err := worker.handleNextHeight(item, fetcher)
+		if done {
+			return
+		}
+		if err == nil {
+			continue
+		}
+
+		wait := time.Second
+		if errors.Is(err, errHeightQuarantined) {
+			wait = 5 * time.Second
+		} else {
+			worker.logger.Errorf("error handling events, retrying: %v", err)
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (worker *fanOutWorker) handleNextHeight(item fanOutItem, fetcher BlockEventsFetcher) (bool, error) {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	if err := worker.loadHalt(); err != nil {
+		return false, err
+	}
+	if item.generation != worker.generation || worker.halted || worker.swappedIn {
+		return true, nil
+	}
+	if err := worker.loadNextHeight(); err != nil {
+		return false, err
+	}
+	if worker.quarantinedHeight != nil {
+		if err := worker.checkQuarantinedHeight(); err != nil {
+			return false, err
+		}
+	}
+
+	nextHeight := *worker.nextHeight
+	if item.height < nextHeight {
+		return true, nil
+	}
+
+	events := item.events
+	if item.height > nextHeight {
+		var err error
+		if events, err = fetcher(nextHeight); err != nil {
+			return false, fmt.Errorf("error fetching block %d to catch up: %v", nextHeight, err)
+		}
+	}
+	if err := worker.handler.HandleEvents(nextHeight, events); err != nil {
+		return false, worker.recordFailure(nextHeight, events, err)
+	}
+	if worker.retriedHeight != nil && *worker.retriedHeight == nextHeight {
+		if err := worker.deadLetterQueue.UpdateStatus(
+			worker.handler.projection.Id(), nextHeight, projection_entity.DEAD_LETTER_STATUS_RESOLVED,
+		); err != nil {
+			worker.logger.Errorf("error resolving dead letter of retried height %d: %v", nextHeight, err)
+		}
+		worker.retriedHeight = nil
+	}
+
+	nextHeight += 1
+	worker.nextHeight = &nextHeight
+	if item.height < nextHeight {
+		if err := worker.swapInIfCaughtUp(); err != nil {
+			worker.logger.Errorf("%v", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// recordFailure counts the failure of handling the height and quarantines the height once the retry
+// budget is used up. Caller must hold the mutex.
+func (worker *fanOutWorker) recordFailure(height int64, events []event.Event, handleErr error) error {
+	if worker.failedHeight != height {
+		worker.failedHeight = height
+		worker.failureCount = 0
+	}
+	worker.failureCount += 1
+	if worker.deadLetterQueue == nil || worker.maxHandleRetries <= 0 ||
+		worker.failureCount < worker.maxHandleRetries {
+		return handleErr
+	}
+
+	if err := worker.deadLetterQueue.Quarantine(
+		worker.handler.projection.Id(), height, events, handleErr,
+	); err != nil {
+		return fmt.Errorf("error quarantining height %d to dead letter queue: %v", height, err)
+	}
+	worker.failureCount = 0
+	worker.quarantinedHeight = &height
+
+	worker.logger.WithFields(applogger.LogFields{
+		"height": height,
+	}).Errorf(
+		"projection is degraded, height is quarantined after %d failed attempts: %v",
+		worker.maxHandleRetries, handleErr,
+	)
+	return errHeightQuarantined
+}
+
+// checkQuarantinedHeight resumes the projection when an operator has requested to retry or skip the
+// quarantined height. It returns errHeightQuarantined otherwise. Caller must hold the mutex.
+func (worker *fanOutWorker) checkQuarantinedHeight() error {
+	height := *worker.quarantinedHeight
+	logger := worker.logger.WithFields(applogger.LogFields{
+		"height": height,
+	})
+
+	status, err := worker.deadLetterQueue.GetStatus(worker.handler.projection.Id(), height)
+	if err != nil {
+		return fmt.Errorf("error getting status of quarantined height %d: %v", height, err)
+	}
+	switch status {
+	case projection_entity.DEAD_LETTER_STATUS_RETRY:
+		worker.quarantinedHeight = nil
+		worker.retriedHeight = &height
+		logger.Infof("retrying quarantined height")
+		return nil
+	case projection_entity.DEAD_LETTER_STATUS_SKIP:
+		if err := worker.handler.HandleEvents(height, []event.Event{}); err != nil {
+			return fmt.Errorf("error skipping quarantined height %d: %v", height, err)
+		}
+		nextHeight := height + 1
+		worker.nextHeight = &nextHeight
+		worker.quarantinedHeight = nil
+		if err := worker.deadLetterQueue.UpdateStatus(
+			worker.handler.projection.Id(), height, projection_entity.DEAD_LETTER_STATUS_SKIPPED,
+		); err != nil {
+			logger.Errorf("error updating status of skipped height: %v", err)
+		}
+		logger.Infof("quarantined height skipped")
+		return nil
+	}
+	return errHeightQuarantined
+}
+
+// swapInIfCaughtUp swaps in the version builder when it has handled all the heights handled by the
+// live projection. The live projection reloads its next height afterwards. Caller must hold the mutex.
+func (worker *fanOutWorker) swapInIfCaughtUp() error {
+	versionBuilder, ok := worker.handler.projection.(projection_entity.VersionBuilder)
+	if !ok {
+		return nil
+	}
+
+	handledHeight := *worker.nextHeight - 1
+	if worker.liveWorker != nil {
+		worker.liveWorker.mutex.Lock()
+		defer worker.liveWorker.mutex.Unlock()
+
+		if err := worker.liveWorker.loadNextHeight(); err != nil {
+			return fmt.Errorf("error loading next height of live projection: %v", err)
+		}
+		if *worker.liveWorker.nextHeight-1 > handledHeight {
+			return nil
+		}
+	}
+
+	if err := versionBuilder.SwapIn(); err != nil {
+		return fmt.Errorf("error swapping in projection version: %v", err)
+	}
+	worker.swappedIn = true
+	if worker.liveWorker != nil {
+		worker.liveWorker.nextHeight = nil
+	}
+
+	worker.logger.WithFields(applogger.LogFields{
+		"height": handledHeight,
+	}).Infof("projection version swapped in, replacing `%s`", versionBuilder.LiveProjectionId())
+	return nil
+}
+
+func (worker *fanOutWorker) rollbackTo(height int64) error {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	worker.generation += 1
+	worker.releaseQuarantinedHeight()
+	if err := worker.loadNextHeight(); err != nil {
+		return err
+	}
+	if *worker.nextHeight <= height+1 {
+		return nil
+	}
+
+	_, isRollbackable := worker.handler.projection.(projection_entity.Rollbackable)
+	_, isResettable := worker.handler.projection.(projection_entity.Resettable)
+	if !isRollbackable && isResettable {
+		if err := worker.resetLocked(); err != nil {
+			return err
+		}
+		worker.logger.Infof("projection does not support rollback, it is reset and replayed from the start")
+		return nil
+	}
+
+	if err := worker.handler.RollbackTo(height); err != nil {
+		worker.halted = true
+		worker.logger.Errorf("projection is halted after chain reorganisation, it has to be rebuilt")
+		if worker.deadLetterQueue != nil {
+			if haltErr := worker.deadLetterQueue.Halt(worker.handler.projection.Id(), height, err); haltErr != nil {
+				return fmt.Errorf("%v; error recording halt of projection: %v", err, haltErr)
+			}
+		}
+		return err
+	}
+	// reload from projection on next handling
+	worker.nextHeight = nil
+	return nil
+}
+
+func (worker *fanOutWorker) reset() error {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	worker.generation += 1
+	if err := worker.resetLocked(); err != nil {
+		return err
+	}
+
+	worker.logger.Infof("projection reset")
+	return nil
+}
+
+// resetLocked resets the projection and resumes it if it is halted. Caller must hold the mutex and bump
+// the generation.
+func (worker *fanOutWorker) resetLocked() error {
+	if err := worker.handler.Reset(); err != nil {
+		return err
+	}
+	worker.halted = false
+	if worker.deadLetterQueue != nil {
+		if err := worker.deadLetterQueue.ResolveHalt(worker.handler.projection.Id()); err != nil {
+			return fmt.Errorf("error resolving halt of reset projection: %v", err)
+		}
+	}
+	worker.releaseQuarantinedHeight()
+	// reload from projection on next handling
+	worker.nextHeight = nil
+	return nil
+}
+
+// releaseQuarantinedHeight stops waiting at the quarantined height after rollback or reset. The dead
+// letter is resolved if the height is handled when replayed. Caller must hold the mutex.
+func (worker *fanOutWorker) releaseQuarantinedHeight() {
+	if worker.quarantinedHeight == nil {
+		return
+	}
+	worker.retriedHeight = worker.quarantinedHeight
+	worker.quarantinedHeight = nil
+}
+
+// loadHalt marks the projection halted when the halt is recorded in the dead letter queue before
+// restart. Caller must hold the mutex.
+func (worker *fanOutWorker) loadHalt() error {
+	if worker.haltLoaded || worker.deadLetterQueue == nil {
+		return nil
+	}
+
+	maybeHaltedHeight, err := worker.deadLetterQueue.FindHaltedHeight(worker.handler.projection.Id())
+	if err != nil {
+		return fmt.Errorf("error finding halted height of projection: %v", err)
+	}
+	if maybeHaltedHeight != nil {
+		worker.halted = true
+		worker.logger.Errorf("projection was halted after chain reorganisation, it has to be rebuilt")
+	}
+	worker.haltLoaded = true
+	return nil
+}
+
+// loadNextHeight loads next height from the projection when it is unknown. Caller must hold the mutex.
+func (worker *fanOutWorker) loadNextHeight() error {
+	if worker.nextHeight != nil {
+		return nil
+	}
+
+	lastHandledEventHeight, err := worker.handler.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height: %v", err)
+	}
+	nextHeight := worker.startHeight
+	if lastHandledEventHeight != nil {
+		nextHeight = *lastHandledEventHeight + 1
+	}
+	worker.nextHeight = &nextHeight
+	return nil
+}
@@ -0,0 +1,340 @@
+package eventhandler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	. "github.com/crypto-com/chain-indexing/entity/event/test"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	. "github.com/crypto-com/chain-indexing/entity/projection/test"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+var _ = Describe("FanOutHandler", func() {
+	newMockProjection := func(id string, lastHandledEventHeight *int64) *MockProjection {
+		mockProjection := NewMockProjection()
+		mockProjection.On("Id").Return(id)
+		mockProjection.On("GetEventsToListen").Return([]string{"ANY_EVENT"})
+		mockProjection.On("GetLastHandledEventHeight").Return(lastHandledEventHeight, nil)
+
+		return mockProjection
+	}
+	newFanOutHandler := func(projections ...*MockProjection) *eventhandler.FanOutHandler {
+		projectionHandlers := make([]*eventhandler.ProjectionHandler, 0, len(projections))
+		for _, projection := range projections {
+			projectionHandlers = append(
+				projectionHandlers, eventhandler.NewProjectionHandler(NewFakeLogger(), nil, projection),
+			)
+		}
+		return eventhandler.NewFanOutHandler(NewFakeLogger(), projectionHandlers)
+	}
+	unexpectedFetcher := func(height int64) ([]entity_event.Event, error) {
+		Fail("unexpected fetch")
+		return nil, nil
+	}
+
+	It("should return the lowest last handled event height of projections", func() {
+		handler := newFanOutHandler(
+			newMockProjection("ANY_PROJECTION", primptr.Int64(10)),
+			newMockProjection("ANY_OTHER_PROJECTION", primptr.Int64(5)),
+		)
+
+		Expect(handler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(5)))
+	})
+
+	It("should dispatch events to all projections", func() {
+		anyEvent := NewMockEvent()
+		anyEvent.On("Name").Return("ANY_EVENT")
+
+		anyProjection := newMockProjection("ANY_PROJECTION", primptr.Int64(0))
+		anyProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Once().Return(nil)
+		anyOtherProjection := newMockProjection("ANY_OTHER_PROJECTION", primptr.Int64(0))
+		anyOtherProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Once().Return(nil)
+
+		handler := newFanOutHandler(anyProjection, anyOtherProjection)
+		handler.RunInBackground(unexpectedFetcher)
+
+		Expect(handler.HandleEvents(1, []entity_event.Event{anyEvent})).To(BeNil())
+		Expect(handler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(1)))
+
+		Eventually(func() bool {
+			return len(anyProjection.Calls) > 0 && len(anyOtherProjection.Calls) > 0
+		}).Should(BeTrue())
+		<-time.After(100 * time.Millisecond)
+		anyProjection.AssertExpectations(GinkgoT())
+		anyOtherProjection.AssertExpectations(GinkgoT())
+	})
+
+	It("should catch up missed heights by fetching the blocks", func() {
+		anyProjection := newMockProjection("ANY_PROJECTION", primptr.Int64(5))
+		handledHeights := make([]int64, 0)
+		var mutex sync.Mutex
+		anyProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			handledHeights = append(handledHeights, args.Get(0).(int64))
+		})
+
+		fetchedHeights := make([]int64, 0)
+		fetcher := func(height int64) ([]entity_event.Event, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			fetchedHeights = append(fetchedHeights, height)
+			return []entity_event.Event{}, nil
+		}
+
+		handler := newFanOutHandler(anyProjection)
+		handler.RunInBackground(fetcher)
+
+		Expect(handler.HandleEvents(8, []entity_event.Event{})).To(BeNil())
+
+		Eventually(func() []int64 {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return append([]int64{}, handledHeights...)
+		}).Should(Equal([]int64{6, 7, 8}))
+		mutex.Lock()
+		defer mutex.Unlock()
+		Expect(fetchedHeights).To(Equal([]int64{6, 7}))
+	})
+
+	It("should start projections without handled event from the start height", func() {
+		anyEvent := NewMockEvent()
+		anyEvent.On("Name").Return("ANY_EVENT")
+
+		anyProjection := newMockProjection("ANY_PROJECTION", nil)
+		anyProjection.On("HandleEvents", int64(99), []entity_event.Event{anyEvent}).Once().Return(nil)
+
+		handler := newFanOutHandler(anyProjection).WithStartHeight(99)
+		handler.RunInBackground(unexpectedFetcher)
+
+		Expect(handler.HandleEvents(99, []entity_event.Event{anyEvent})).To(BeNil())
+
+		Eventually(func() int {
+			return len(anyProjection.Calls)
+		}).Should(BeNumerically(">", 2))
+		<-time.After(100 * time.Millisecond)
+		anyProjection.AssertExpectations(GinkgoT())
+	})
+
+	It("should replay a reset projection from the start height", func() {
+		anyProjection := NewMockResettableProjection()
+		anyProjection.On("Id").Return("ANY_PROJECTION")
+		anyProjection.On("GetLastHandledEventHeight").Once().Return(primptr.Int64(5), nil)
+		anyProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+		anyProjection.On("Reset").Once().Return(nil)
+		handledHeights := make([]int64, 0)
+		var mutex sync.Mutex
+		anyProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			handledHeights = append(handledHeights, args.Get(0).(int64))
+		})
+		fetcher := func(height int64) ([]entity_event.Event, error) {
+			return []entity_event.Event{}, nil
+		}
+
+		handler := eventhandler.NewFanOutHandler(NewFakeLogger(), []*eventhandler.ProjectionHandler{
+			eventhandler.NewProjectionHandler(NewFakeLogger(), nil, anyProjection),
+		}).WithStartHeight(2)
+		handler.RunInBackground(fetcher)
+
+		Expect(handler.HandleEvents(6, []entity_event.Event{})).To(BeNil())
+		Eventually(func() []int64 {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return append([]int64{}, handledHeights...)
+		}).Should(Equal([]int64{6}))
+
+		Expect(handler.ResetProjection("ANY_PROJECTION")).To(BeNil())
+		Expect(handler.HandleEvents(7, []entity_event.Event{})).To(BeNil())
+		Eventually(func() []int64 {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return append([]int64{}, handledHeights...)
+		}).Should(Equal([]int64{6, 2, 3, 4, 5, 6, 7}))
+		anyProjection.AssertExpectations(GinkgoT())
+	})
+
+	It("should replay a projection not supporting rollback from the start after chain reorganisation", func() {
+		anyProjection := NewMockResettableProjection()
+		anyProjection.On("Id").Return("ANY_PROJECTION")
+		anyProjection.On("GetLastHandledEventHeight").Once().Return(primptr.Int64(5), nil)
+		anyProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+		anyProjection.On("Reset").Once().Return(nil)
+		handledHeights := make([]int64, 0)
+		var mutex sync.Mutex
+		anyProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			handledHeights = append(handledHeights, args.Get(0).(int64))
+		})
+		fetcher := func(height int64) ([]entity_event.Event, error) {
+			return []entity_event.Event{}, nil
+		}
+
+		handler := eventhandler.NewFanOutHandler(NewFakeLogger(), []*eventhandler.ProjectionHandler{
+			eventhandler.NewProjectionHandler(NewFakeLogger(), nil, anyProjection),
+		}).WithStartHeight(2)
+		handler.RunInBackground(fetcher)
+
+		Expect(handler.HandleEvents(6, []entity_event.Event{})).To(BeNil())
+		Eventually(func() []int64 {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return append([]int64{}, handledHeights...)
+		}).Should(Equal([]int64{6}))
+
+		Expect(handler.RollbackTo(4)).To(BeNil())
+		Expect(handler.HandleEvents(5, []entity_event.Event{})).To(BeNil())
+		Eventually(func() []int64 {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return append([]int64{}, handledHeights...)
+		}).Should(Equal([]int64{6, 2, 3, 4, 5}))
+		anyProjection.AssertExpectations(GinkgoT())
+	})
+
+	It("should return Error when resetting a projection not registered", func() {
+		handler := newFanOutHandler(newMockProjection("ANY_PROJECTION", primptr.Int64(0)))
+
+		Expect(handler.ResetProjection("ANY_OTHER_PROJECTION")).To(MatchError(
+			"projection `ANY_OTHER_PROJECTION` is not registered",
+		))
+	})
+
+	It("should return nil handled block when the Block projection is not enabled", func() {
+		handler := newFanOutHandler(
+			newMockProjection("Transaction", primptr.Int64(10)),
+			newMockProjection("Validator", primptr.Int64(10)),
+		)
+
+		Expect(handler.GetHandledBlock(10)).To(BeNil())
+	})
+
+	It("should swap in the version builder once it has caught up with the live projection", func() {
+		liveProjection := newMockProjection("ANY_PROJECTION", primptr.Int64(5))
+		liveProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil)
+		versionBuilder := NewMockVersionBuilder()
+		versionBuilder.On("Id").Return("ANY_PROJECTION_v2")
+		versionBuilder.On("LiveProjectionId").Return("ANY_PROJECTION")
+		versionBuilder.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+		versionBuilder.On("HandleEvents", mock.Anything, mock.Anything).Return(nil)
+		swappedInCh := make(chan struct{})
+		versionBuilder.On("SwapIn").Once().Return(nil).Run(func(_ mock.Arguments) {
+			close(swappedInCh)
+		})
+		fetcher := func(height int64) ([]entity_event.Event, error) {
+			return []entity_event.Event{}, nil
+		}
+
+		handler := eventhandler.NewFanOutHandler(NewFakeLogger(), []*eventhandler.ProjectionHandler{
+			eventhandler.NewProjectionHandler(NewFakeLogger(), nil, liveProjection),
+			eventhandler.NewProjectionHandler(NewFakeLogger(), nil, versionBuilder),
+		})
+		// the version builder catches up by itself and does not hold back the sync
+		Expect(handler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(5)))
+
+		handler.RunInBackground(fetcher)
+		Expect(handler.HandleEvents(6, []entity_event.Event{})).To(BeNil())
+
+		Eventually(swappedInCh).Should(BeClosed())
+		versionBuilder.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 7)
+	})
+
+	It("should quarantine the height after the retry budget and resolve it once retried", func() {
+		anyEvent := NewMockEvent()
+		anyEvent.On("Name").Return("ANY_EVENT")
+
+		handleErr := errors.New("malformed event")
+		anyProjection := newMockProjection("ANY_PROJECTION", primptr.Int64(0))
+		anyProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Twice().Return(handleErr)
+		anyProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Once().Return(nil)
+
+		mockDeadLetterQueue := NewMockDeadLetterQueue()
+		mockDeadLetterQueue.On("FindHaltedHeight", "ANY_PROJECTION").Return((*int64)(nil), nil)
+		mockDeadLetterQueue.On(
+			"Quarantine", "ANY_PROJECTION", int64(1), []entity_event.Event{anyEvent}, mock.Anything,
+		).Once().Return(nil)
+		mockDeadLetterQueue.On("GetStatus", "ANY_PROJECTION", int64(1)).Return(
+			projection_entity.DEAD_LETTER_STATUS_RETRY, nil,
+		)
+		resolvedCh := make(chan struct{})
+		mockDeadLetterQueue.On(
+			"UpdateStatus", "ANY_PROJECTION", int64(1), projection_entity.DEAD_LETTER_STATUS_RESOLVED,
+		).Once().Return(nil).Run(func(_ mock.Arguments) {
+			close(resolvedCh)
+		})
+
+		handler := newFanOutHandler(anyProjection).WithDeadLetterQueue(mockDeadLetterQueue, 2)
+		handler.RunInBackground(unexpectedFetcher)
+		Expect(handler.HandleEvents(1, []entity_event.Event{anyEvent})).To(BeNil())
+
+		Eventually(resolvedCh, 10*time.Second).Should(BeClosed())
+		anyProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 3)
+		mockDeadLetterQueue.AssertExpectations(GinkgoT())
+	})
+
+	It("should keep the projection halted after restart until it is reset", func() {
+		anyEvent := NewMockEvent()
+		anyEvent.On("Name").Return("ANY_EVENT")
+
+		anyProjection := NewMockResettableProjection()
+		anyProjection.On("Id").Return("ANY_PROJECTION")
+		anyProjection.On("GetEventsToListen").Return([]string{"ANY_EVENT"})
+		anyProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+		anyProjection.On("Reset").Once().Return(nil)
+		handledCh := make(chan struct{})
+		anyProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Once().Return(nil).Run(
+			func(_ mock.Arguments) {
+				close(handledCh)
+			},
+		)
+
+		mockDeadLetterQueue := NewMockDeadLetterQueue()
+		mockDeadLetterQueue.On("FindHaltedHeight", "ANY_PROJECTION").Once().Return(primptr.Int64(0), nil)
+		mockDeadLetterQueue.On("ResolveHalt", "ANY_PROJECTION").Once().Return(nil)
+
+		handler := eventhandler.NewFanOutHandler(NewFakeLogger(), []*eventhandler.ProjectionHandler{
+			eventhandler.NewProjectionHandler(NewFakeLogger(), nil, anyProjection),
+		}).WithDeadLetterQueue(mockDeadLetterQueue, 0)
+		handler.RunInBackground(unexpectedFetcher)
+
+		Expect(handler.HandleEvents(1, []entity_event.Event{anyEvent})).To(BeNil())
+		Consistently(handledCh, time.Second).ShouldNot(BeClosed())
+
+		Expect(handler.ResetProjection("ANY_PROJECTION")).To(BeNil())
+		Expect(handler.HandleEvents(1, []entity_event.Event{anyEvent})).To(BeNil())
+		Eventually(handledCh, 10*time.Second).Should(BeClosed())
+		mockDeadLetterQueue.AssertExpectations(GinkgoT())
+	})
+
+	It("should stop running projections when context is cancelled", func() {
+		anyProjection := newMockProjection("ANY_PROJECTION", primptr.Int64(0))
+		handler := newFanOutHandler(anyProjection)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		doneCh := make(chan struct{})
+		go func() {
+			handler.Run(ctx, unexpectedFetcher)
+			close(doneCh)
+		}()
+
+		cancel()
+		Eventually(doneCh).Should(BeClosed())
+
+		// events queued after stop are never handled
+		Expect(handler.HandleEvents(1, []entity_event.Event{})).To(BeNil())
+		<-time.After(100 * time.Millisecond)
+		anyProjection.AssertNotCalled(GinkgoT(), "HandleEvents", mock.Anything, mock.Anything)
+	})
+})
@@ -7,3 +7,23 @@ type Handler interface {
 
 	HandleEvents(blockHeight int64, events []event.Event) error
 }
+
+// RollbackableHandler is a Handler which keeps track of the blocks it has handled. It allows the
+// sync manager to verify chain continuity and revert handled results on chain reorganisation.
+type RollbackableHandler interface {
+	Handler
+
+	// GetHandledBlock returns the handled block at height, nil if the block is unknown to the handler
+	GetHandledBlock(height int64) (*HandledBlock, error)
+
+	// RollbackTo reverts all handled results after `height`
+	RollbackTo(height int64) error
+}
+
+type HandledBlock struct {
+	Height  int64
+	Hash    string
+	AppHash string
+	// ParentHash is the hash of previous block. It is empty when unknown.
+	ParentHash string
+}
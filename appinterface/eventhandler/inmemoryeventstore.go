@@ -0,0 +1,81 @@
+package eventhandler
+
+import (
+	"fmt"
+	"sync"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+var _ RollbackableHandler = &InMemoryEventStoreHandler{}
+
+// InMemoryEventStoreHandler is an event handler which persist the events to an in-memory event store.
+// It keeps the last indexed block height in memory as well, so that no database is required.
+type InMemoryEventStoreHandler struct {
+	logger applogger.Logger
+
+	eventStore *event_interface.InMemoryStore
+
+	mutex                  sync.RWMutex
+	maybeLastIndexedHeight *int64
+}
+
+func NewInMemoryEventStoreHandler(
+	logger applogger.Logger,
+	eventStore *event_interface.InMemoryStore,
+) *InMemoryEventStoreHandler {
+	return &InMemoryEventStoreHandler{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "InMemoryEventStoreHandler",
+		}),
+
+		eventStore: eventStore,
+	}
+}
+
+func (handler *InMemoryEventStoreHandler) GetLastHandledEventHeight() (*int64, error) {
+	handler.mutex.RLock()
+	defer handler.mutex.RUnlock()
+
+	if handler.maybeLastIndexedHeight == nil {
+		return nil, nil
+	}
+	lastIndexedHeight := *handler.maybeLastIndexedHeight
+	return &lastIndexedHeight, nil
+}
+
+func (handler *InMemoryEventStoreHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if err := handler.eventStore.InsertAll(events); err != nil {
+		return fmt.Errorf("error storing all events for height %d: %v", blockHeight, err)
+	}
+	handler.maybeLastIndexedHeight = &blockHeight
+
+	return nil
+}
+
+// GetHandledBlock returns the block stored in the event store at height, nil if there is none
+func (handler *InMemoryEventStoreHandler) GetHandledBlock(height int64) (*HandledBlock, error) {
+	events, err := handler.eventStore.GetAllByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("error getting events at height %d: %v", height, err)
+	}
+
+	return findHandledBlock(events), nil
+}
+
+// RollbackTo deletes all events after `height` and resets the last indexed block height
+func (handler *InMemoryEventStoreHandler) RollbackTo(height int64) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.eventStore.DeleteAllAfterHeight(height)
+	handler.maybeLastIndexedHeight = &height
+
+	handler.logger.Infof("rolled back event store to height %d", height)
+	return nil
+}
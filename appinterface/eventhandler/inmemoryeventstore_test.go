@@ -0,0 +1,88 @@
+package eventhandler_test
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/inmemoryprojectionbase"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	. "github.com/crypto-com/chain-indexing/entity/event/test"
+	"github.com/crypto-com/chain-indexing/entity/projection"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+var _ = Describe("InMemoryEventStoreHandler", func() {
+	It("should keep track of the last handled event height", func() {
+		handler := eventhandler.NewInMemoryEventStoreHandler(NewFakeLogger(), event_interface.NewInMemoryStore())
+		Expect(handler.GetLastHandledEventHeight()).To(BeNil())
+
+		Expect(handler.HandleEvents(1, []entity_event.Event{NewFakeEvent()})).To(BeNil())
+		Expect(handler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(1)))
+
+		Expect(handler.RollbackTo(0)).To(BeNil())
+		Expect(handler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(0)))
+	})
+
+	It("should feed the handled events to in-memory projections without database", func() {
+		eventStore := event_interface.NewInMemoryStore()
+		handler := eventhandler.NewInMemoryEventStoreHandler(NewFakeLogger(), eventStore)
+
+		countingProjection := newEventCountingProjection()
+		manager := projection.NewStoreBasedManager(NewFakeLogger(), eventStore)
+		Expect(manager.RegisterProjection(countingProjection)).To(BeNil())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go manager.Run(ctx)
+
+		Expect(handler.HandleEvents(0, []entity_event.Event{NewFakeEvent()})).To(BeNil())
+		Expect(handler.HandleEvents(1, []entity_event.Event{NewFakeEvent(), NewFakeEvent()})).To(BeNil())
+
+		Eventually(countingProjection.GetLastHandledEventHeight).Should(Equal(primptr.Int64(1)))
+		Expect(countingProjection.Count()).To(Equal(3))
+	})
+})
+
+// eventCountingProjection counts the handled events in memory
+type eventCountingProjection struct {
+	*inmemoryprojectionbase.Base
+
+	mutex sync.Mutex
+	count int
+}
+
+func newEventCountingProjection() *eventCountingProjection {
+	return &eventCountingProjection{
+		Base: inmemoryprojectionbase.NewInMemoryBase("EventCounting"),
+	}
+}
+
+func (projection *eventCountingProjection) GetEventsToListen() []string {
+	return []string{NewFakeEvent().Name()}
+}
+
+func (projection *eventCountingProjection) OnInit() error {
+	return nil
+}
+
+func (projection *eventCountingProjection) HandleEvents(height int64, events []entity_event.Event) error {
+	projection.mutex.Lock()
+	defer projection.mutex.Unlock()
+
+	projection.count += len(events)
+	projection.UpdateLastHandledEventHeight(height)
+	return nil
+}
+
+func (projection *eventCountingProjection) Count() int {
+	projection.mutex.Lock()
+	defer projection.mutex.Unlock()
+
+	return projection.count
+}
@@ -1,24 +1,38 @@
 package eventhandler
 
 import (
+	"errors"
 	"fmt"
 
+	block_view "github.com/crypto-com/chain-indexing/appinterface/projection/block/view"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/entity/event"
 	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
 )
 
-var _ Handler = &ProjectionHandler{}
+var _ RollbackableHandler = &ProjectionHandler{}
+
+// BLOCK_PROJECTION_ID is the id of the projection maintaining view_blocks
+const BLOCK_PROJECTION_ID = "Block"
 
 type ProjectionHandler struct {
 	logger     applogger.Logger
 	projection projection_entity.Projection
+
+	blocksView *block_view.Blocks
 }
 
-func NewProjectionHandler(logger applogger.Logger, projection projection_entity.Projection) *ProjectionHandler {
+func NewProjectionHandler(
+	logger applogger.Logger,
+	rdbHandle *rdb.Handle,
+	projection projection_entity.Projection,
+) *ProjectionHandler {
 	return &ProjectionHandler{
 		logger,
 		projection,
+
+		block_view.NewBlocks(rdbHandle),
 	}
 }
 
@@ -56,6 +70,61 @@ func (handler *ProjectionHandler) HandleEvents(blockHeight int64, events []event
 	return nil
 }
 
+// GetHandledBlock returns the block projected in view_blocks at height. Since view_blocks is
+// maintained by the Block projection, nil is returned when the projection is not the Block projection
+// or it has not reached the height yet.
+func (handler *ProjectionHandler) GetHandledBlock(height int64) (*HandledBlock, error) {
+	if handler.projection.Id() != BLOCK_PROJECTION_ID {
+		return nil, nil
+	}
+
+	block, err := handler.blocksView.FindBy(&block_view.BlockIdentity{
+		MaybeHeight: &height,
+	})
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding block at height %d: %v", height, err)
+	}
+
+	return &HandledBlock{
+		Height:  block.Height,
+		Hash:    block.Hash,
+		AppHash: block.AppHash,
+	}, nil
+}
+
+// RollbackTo reverts the projection to `height`. It returns error when the projection does not
+// support rollback.
+func (handler *ProjectionHandler) RollbackTo(height int64) error {
+	rollbackableProjection, ok := handler.projection.(projection_entity.Rollbackable)
+	if !ok {
+		return fmt.Errorf(
+			"projection `%s` does not support rollback, it has to be rebuilt", handler.projection.Id(),
+		)
+	}
+
+	if err := rollbackableProjection.RollbackTo(height); err != nil {
+		return fmt.Errorf("error rolling back projection `%s` to height %d: %v", handler.projection.Id(), height, err)
+	}
+	return nil
+}
+
+// Reset resets the projection so that it handles events from the start again. It returns error when
+// the projection does not support reset.
+func (handler *ProjectionHandler) Reset() error {
+	resettableProjection, ok := handler.projection.(projection_entity.Resettable)
+	if !ok {
+		return fmt.Errorf("projection `%s` does not support reset", handler.projection.Id())
+	}
+
+	if err := resettableProjection.Reset(); err != nil {
+		return fmt.Errorf("error resetting projection `%s`: %v", handler.projection.Id(), err)
+	}
+	return nil
+}
+
 func isListeningEvent(event event.Event, eventsToListen []string) bool {
 	targetEventName := event.Name()
 	for _, eventName := range eventsToListen {
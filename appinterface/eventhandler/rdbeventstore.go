@@ -8,11 +8,13 @@ import (
 	"github.com/crypto-com/chain-indexing/appinterface/rdbstatusstore"
 	"github.com/crypto-com/chain-indexing/entity/event"
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 )
 
-var _ Handler = &RDbEventStoreHandler{}
+var _ RollbackableHandler = &RDbEventStoreHandler{}
 
-// RDbEventStoreHandler is an event handler which persist the event to event store
+// RDbEventStoreHandler is an event handler which persist the event to event store. The digest of the
+// events at each height is stored in the same transaction, see event_interface.RDbDigestStore.
 type RDbEventStoreHandler struct {
 	logger  applogger.Logger
 	rdbConn rdb.Conn
@@ -53,6 +55,9 @@ func (handler *RDbEventStoreHandler) HandleEvents(blockHeight int64, events []ev
 	if err := handler.eventStore.InsertAllWithRDbHandle(txHandle, events); err != nil {
 		return fmt.Errorf("error storing all events for height %d: %v", blockHeight, err)
 	}
+	if err := handler.eventStore.InsertDigestWithRDbHandle(txHandle, blockHeight, events); err != nil {
+		return fmt.Errorf("error storing events digest for height %d: %v", blockHeight, err)
+	}
 
 	if err := handler.statusStore.UpdateLastIndexedBlockHeightWithRDbHandle(txHandle, blockHeight); err != nil {
 		return fmt.Errorf("error updating last indexed block height to %d: %v", blockHeight, err)
@@ -64,6 +69,114 @@ func (handler *RDbEventStoreHandler) HandleEvents(blockHeight int64, events []ev
 	return nil
 }
 
+// ReplaceEvents replaces the persisted events at blockHeight with the provided events. The last indexed
+// block height is only advanced when blockHeight is the next block to index, so that no block is
+// skipped by the indexing service.
+func (handler *RDbEventStoreHandler) ReplaceEvents(blockHeight int64, events []event.Event) error {
+	tx, err := handler.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error when beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	txHandle := tx.ToHandle()
+
+	if err := handler.eventStore.DeleteAllByHeightWithRDbHandle(txHandle, blockHeight); err != nil {
+		return fmt.Errorf("error deleting events at height %d: %v", blockHeight, err)
+	}
+	if err := handler.eventStore.InsertAllWithRDbHandle(txHandle, events); err != nil {
+		return fmt.Errorf("error storing all events for height %d: %v", blockHeight, err)
+	}
+	if err := handler.eventStore.InsertDigestWithRDbHandle(txHandle, blockHeight, events); err != nil {
+		return fmt.Errorf("error storing events digest for height %d: %v", blockHeight, err)
+	}
+
+	maybeLastIndexedHeight, err := handler.statusStore.GetLastIndexedBlockHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last indexed block height: %v", err)
+	}
+	if isNextBlockHeight(maybeLastIndexedHeight, blockHeight) {
+		if err := handler.statusStore.UpdateLastIndexedBlockHeightWithRDbHandle(txHandle, blockHeight); err != nil {
+			return fmt.Errorf("error updating last indexed block height to %d: %v", blockHeight, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing events replacement: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+func isNextBlockHeight(maybeLastIndexedHeight *int64, blockHeight int64) bool {
+	if maybeLastIndexedHeight == nil {
+		return blockHeight == int64(0)
+	}
+
+	return blockHeight == *maybeLastIndexedHeight+1
+}
+
+// GetHandledBlock returns the block persisted in the event store at height, nil if there is none
+func (handler *RDbEventStoreHandler) GetHandledBlock(height int64) (*HandledBlock, error) {
+	events, err := handler.eventStore.GetAllByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("error getting events at height %d: %v", height, err)
+	}
+
+	return findHandledBlock(events), nil
+}
+
+// findHandledBlock returns the block from the BlockCreated event, nil if there is none
+func findHandledBlock(events []event.Event) *HandledBlock {
+	for _, evt := range events {
+		if blockCreatedEvent, ok := evt.(*event_usecase.BlockCreated); ok {
+			return &HandledBlock{
+				Height:  blockCreatedEvent.Block.Height,
+				Hash:    blockCreatedEvent.Block.Hash,
+				AppHash: blockCreatedEvent.Block.AppHash,
+			}
+		}
+	}
+
+	return nil
+}
+
+// RollbackTo deletes all events after `height` and resets the last indexed block height
+func (handler *RDbEventStoreHandler) RollbackTo(height int64) error {
+	tx, err := handler.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error when beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	txHandle := tx.ToHandle()
+
+	if err := handler.eventStore.DeleteAllAfterHeightWithRDbHandle(txHandle, height); err != nil {
+		return fmt.Errorf("error deleting events after height %d: %v", height, err)
+	}
+
+	if err := handler.statusStore.UpdateLastIndexedBlockHeightWithRDbHandle(txHandle, height); err != nil {
+		return fmt.Errorf("error updating last indexed block height to %d: %v", height, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing event store rollback: %v", err)
+	}
+	committed = true
+
+	handler.logger.Infof("rolled back event store to height %d", height)
+	return nil
+}
+
 func initEventStore(rdbHandle *rdb.Handle, registry *event.Registry) *event_interface.RDbStore {
 	return event_interface.NewRDbStore(rdbHandle, registry)
 }
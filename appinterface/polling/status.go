@@ -6,6 +6,9 @@ import (
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 )
 
+// PARTIAL_HISTORY_START_HEIGHT is the first indexed block height when the indexing starts after genesis
+const PARTIAL_HISTORY_START_HEIGHT = "PartialHistoryStartHeight"
+
 type Status struct {
 	rdb *rdb.Handle
 }
@@ -44,11 +44,17 @@ func NewAccount(logger applogger.Logger, rdbConn rdb.Conn, httpclient *cosmosapp
 }
 
 func (_ *Account) GetEventsToListen() []string {
-	return []string{event_usecase.ACCOUNT_TRANSFERRED}
+	return []string{event_usecase.ACCOUNT_TRANSFERRED, event_usecase.ACCOUNT_BOOTSTRAPPED}
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *Account) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *Account) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, "view_accounts")
 }
 
 func (projection *Account) HandleEvents(height int64, events []event_entity.Event) error {
@@ -73,6 +79,10 @@ func (projection *Account) HandleEvents(height int64, events []event_entity.Even
 			if handleErr := projection.handleAccountCreatedEvent(accountsView, accountCreatedEvent); handleErr != nil {
 				return fmt.Errorf("error handling AccountCreatedEvent: %v", handleErr)
 			}
+		} else if accountBootstrappedEvent, ok := event.(*event_usecase.AccountBootstrapped); ok {
+			if handleErr := projection.handleAccountBootstrappedEvent(accountsView, accountBootstrappedEvent); handleErr != nil {
+				return fmt.Errorf("error handling AccountBootstrappedEvent: %v", handleErr)
+			}
 		}
 	}
 
@@ -103,6 +113,25 @@ func (projection *Account) handleAccountCreatedEvent(accountsView *account_view.
 	return nil
 }
 
+// handleAccountBootstrappedEvent writes the account state at the bootstrap height without requesting
+// the latest state. It returns error when the balance does not fit in the balance column.
+func (projection *Account) handleAccountBootstrappedEvent(accountsView *account_view.Accounts, event *event_usecase.AccountBootstrapped) error {
+	balance := event.Balance.ToBigInt()
+	if !balance.IsInt64() {
+		return fmt.Errorf("balance %s of account %s overflows int64", balance.String(), event.AccountAddress)
+	}
+
+	return accountsView.Upsert(&account_view.Account{
+		AccountType:    event.AccountType,
+		AccountAddress: event.AccountAddress,
+		Pubkey:         event.Pubkey,
+		AccountNumber:  ConvertToInt64(event.AccountNumber),
+		SequenceNumber: ConvertToInt64(event.SequenceNumber),
+		AccountBalance: balance.Int64(),
+		AccountDenom:   projection.baseDenom,
+	})
+}
+
 func (projection *Account) getAccountInfo(address string) (*cosmosapp_interface.Account, error) {
 
 	var accountInfo, accountInfoError = projection.httpclinet.Account(address)
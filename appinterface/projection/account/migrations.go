@@ -0,0 +1,23 @@
+package account
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept.
+var migrations = map[string]string{
+	"1_view_accounts.up.sql": `
+CREATE TABLE IF NOT EXISTS view_accounts (
+    id BIGSERIAL,
+    account_address VARCHAR NOT NULL,
+    account_type VARCHAR ,
+    pubkey VARCHAR ,
+    account_number BIGINT DEFAULT -1,
+    sequence_number BIGINT DEFAULT -1,
+    account_balance BIGINT DEFAULT 0,
+    account_denom VARCHAR  DEFAULT 'basecro',
+    PRIMARY KEY(id),
+    UNIQUE(account_address)
+);
+`,
+	"1_view_accounts.down.sql": `
+DROP TABLE IF EXISTS view_accounts;
+`,
+}
@@ -16,6 +16,9 @@ import (
 )
 
 var _ projection_entity.Projection = &AccountMessage{}
+var _ projection_entity.Resettable = &AccountMessage{}
+var _ projection_entity.Versioned = &AccountMessage{}
+var _ projection_entity.BatchHandler = &AccountMessage{}
 
 type AccountMessage struct {
 	*rdbprojectionbase.Base
@@ -39,8 +42,14 @@ func (_ *AccountMessage) GetEventsToListen() []string {
 	}, event_usecase.MSG_EVENTS...)
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *AccountMessage) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *AccountMessage) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, projection.ownedTableNames()...)
 }
 
 func (projection *AccountMessage) HandleEvents(height int64, events []event_entity.Event) error {
@@ -49,6 +58,18 @@ func (projection *AccountMessage) HandleEvents(height int64, events []event_enti
 		return nil
 	}
 
+	return projection.HandleEventsBatch([]projection_entity.HeightEvents{{
+		Height: height,
+		Events: events,
+	}})
+}
+
+// HandleEventsBatch implements projection.BatchHandler and projects all the heights in one transaction
+func (projection *AccountMessage) HandleEventsBatch(batch []projection_entity.HeightEvents) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %v", err)
@@ -62,9 +83,43 @@ func (projection *AccountMessage) HandleEvents(height int64, events []event_enti
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
-	accountMessagesView := view.NewAccountMessages(rdbTxHandle)
-	accountMessagesTotalView := view.NewAccountMessagesTotal(rdbTxHandle)
+	accountMessagesView := view.NewAccountMessagesWithTableName(
+		rdbTxHandle, projection.TableName("view_account_messages"),
+	)
+	accountMessagesTotalView := view.NewAccountMessagesTotalWithTableName(
+		rdbTxHandle, projection.TableName("view_account_messages_total"),
+	)
+
+	for _, heightEvents := range batch {
+		// TODO: Handle genesis transaction
+		if heightEvents.Height == int64(0) {
+			continue
+		}
+
+		if err := projection.handleHeightEvents(
+			accountMessagesView, accountMessagesTotalView, heightEvents.Height, heightEvents.Events,
+		); err != nil {
+			return fmt.Errorf("error handling events at height %d: %v", heightEvents.Height, err)
+		}
+	}
+
+	if err := projection.UpdateLastHandledEventHeight(rdbTxHandle, batch[len(batch)-1].Height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
 
+func (projection *AccountMessage) handleHeightEvents(
+	accountMessagesView *view.AccountMessages,
+	accountMessagesTotalView *view.AccountMessagesTotal,
+	height int64,
+	events []event_entity.Event,
+) error {
 	var blockTime utctime.UTCTime
 	var blockHash string
 	accountMessages := make([]view.AccountMessageRecord, 0)
@@ -388,13 +443,5 @@ func (projection *AccountMessage) HandleEvents(height int64, events []event_enti
 		}
 	}
 
-	if err := projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
-		return fmt.Errorf("error updating last handled event height: %v", err)
-	}
-
-	if err := rdbTx.Commit(); err != nil {
-		return fmt.Errorf("error committing changes: %v", err)
-	}
-	committed = true
 	return nil
 }
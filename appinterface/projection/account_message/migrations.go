@@ -0,0 +1,32 @@
+package account_message
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept.
+var migrations = map[string]string{
+	"1_view_account_messages.up.sql": `
+CREATE TABLE IF NOT EXISTS view_account_messages (
+    id BIGSERIAL,
+    block_height BIGINT,
+    block_hash VARCHAR NOT NULL,
+    block_time BIGINT NOT NULL,
+    account VARCHAR NOT NULL,
+    transaction_hash VARCHAR NOT NULL,
+    success BOOLEAN NOT NULL,
+    message_index INT NOT NULL,
+    message_type VARCHAR NOT NULL,
+    data JSONB NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (account, transaction_hash, message_index)
+);
+
+CREATE TABLE IF NOT EXISTS view_account_messages_total (
+     identity VARCHAR,
+     total BIGINT NOT NULL,
+     PRIMARY KEY (identity)
+);
+`,
+	"1_view_account_messages.down.sql": `
+DROP TABLE IF EXISTS view_account_messages_total;
+DROP TABLE IF EXISTS view_account_messages;
+`,
+}
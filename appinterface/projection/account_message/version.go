@@ -0,0 +1,62 @@
+package account_message
+
+import (
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+)
+
+var _ projection_entity.VersionBuilder = &accountMessageVersionBuilder{}
+
+// VERSION of the projected tables. Bump it when the projected rows change, the tables of the new
+// version are then built alongside the live ones and swapped in once they have caught up.
+const VERSION = 1
+
+var ownedTables = []string{"view_account_messages", "view_account_messages_total"}
+
+func (projection *AccountMessage) ownedTableNames() []string {
+	tableNames := make([]string, 0, len(ownedTables))
+	for _, table := range ownedTables {
+		tableNames = append(tableNames, projection.TableName(table))
+	}
+	return tableNames
+}
+
+// NewVersionBuilder implements projection.Versioned
+func (projection *AccountMessage) NewVersionBuilder() (projection_entity.VersionBuilder, error) {
+	liveVersion, err := projection.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting live version of projection: %v", err)
+	}
+	if liveVersion >= VERSION {
+		return nil, nil
+	}
+
+	builder := &accountMessageVersionBuilder{
+		&AccountMessage{
+			rdbprojectionbase.NewRDbVersionBuilderBase(projection.rdbConn.ToHandle(), projection.Id(), VERSION),
+
+			projection.rdbConn,
+			projection.logger,
+		},
+	}
+	if err := builder.CreateVersionedTables(projection.rdbConn, ownedTables...); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+type accountMessageVersionBuilder struct {
+	*AccountMessage
+}
+
+// LiveProjectionId implements projection.VersionBuilder
+func (builder *accountMessageVersionBuilder) LiveProjectionId() string {
+	return "AccountMessage"
+}
+
+// SwapIn implements projection.VersionBuilder
+func (builder *accountMessageVersionBuilder) SwapIn() error {
+	return builder.SwapInVersionedTables(builder.rdbConn, ownedTables...)
+}
@@ -18,11 +18,21 @@ import (
 // BlockTransactions projection view implemented by relational database
 type AccountMessages struct {
 	rdb *rdb.Handle
+
+	tableName string
 }
 
 func NewAccountMessages(handle *rdb.Handle) *AccountMessages {
+	return NewAccountMessagesWithTableName(handle, "view_account_messages")
+}
+
+// NewAccountMessagesWithTableName creates the view on a table other than view_account_messages, e.g.
+// the table of a projection version being built
+func NewAccountMessagesWithTableName(handle *rdb.Handle, tableName string) *AccountMessages {
 	return &AccountMessages{
 		handle,
+
+		tableName,
 	}
 }
 
@@ -33,7 +43,7 @@ func (accountMessagesView *AccountMessages) Insert(messageRow *AccountMessageRow
 	}
 
 	stmtBuilder := accountMessagesView.rdb.StmtBuilder.Insert(
-		"view_account_messages",
+		accountMessagesView.tableName,
 	).Columns(
 		"block_height",
 		"block_hash",
@@ -81,20 +91,21 @@ func (accountMessagesView *AccountMessages) List(
 	order AccountMessagesListOrder,
 	pagination *pagination_interface.Pagination,
 ) ([]AccountMessageRow, *pagination_interface.PaginationResult, error) {
+	tableName := accountMessagesView.tableName
 	stmtBuilder := accountMessagesView.rdb.StmtBuilder.Select(
-		"view_account_messages.account",
-		"view_account_messages.block_height",
-		"view_account_messages.block_hash",
-		"view_account_messages.block_time",
-		"view_account_messages.transaction_hash",
-		"view_account_messages.success",
-		"view_account_messages.message_index",
-		"view_account_messages.message_type",
-		"view_account_messages.data",
+		tableName+".account",
+		tableName+".block_height",
+		tableName+".block_hash",
+		tableName+".block_time",
+		tableName+".transaction_hash",
+		tableName+".success",
+		tableName+".message_index",
+		tableName+".message_type",
+		tableName+".data",
 	).From(
-		"view_account_messages",
+		tableName,
 	).Where(
-		"view_account_messages.account = ?", filter.Account,
+		tableName+".account = ?", filter.Account,
 	)
 
 	var totalIdentities []string
@@ -102,7 +113,7 @@ func (accountMessagesView *AccountMessages) List(
 		totalIdentities = []string{fmt.Sprintf("%s:-", filter.Account)}
 	} else {
 		totalIdentities = make([]string, 0)
-		stmtBuilder = stmtBuilder.Where(sq.Eq{"view_account_messages.message_type": filter.MaybeMsgTypes})
+		stmtBuilder = stmtBuilder.Where(sq.Eq{tableName + ".message_type": filter.MaybeMsgTypes})
 		for _, msgType := range filter.MaybeMsgTypes {
 			totalIdentities = append(totalIdentities, fmt.Sprintf("%s:%s", filter.Account, msgType))
 		}
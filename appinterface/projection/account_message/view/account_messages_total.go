@@ -10,7 +10,13 @@ type AccountMessagesTotal struct {
 }
 
 func NewAccountMessagesTotal(rdbHandle *rdb.Handle) *AccountMessagesTotal {
+	return NewAccountMessagesTotalWithTableName(rdbHandle, "view_account_messages_total")
+}
+
+// NewAccountMessagesTotalWithTableName creates the view on a table other than
+// view_account_messages_total, e.g. the table of a projection version being built
+func NewAccountMessagesTotalWithTableName(rdbHandle *rdb.Handle, tableName string) *AccountMessagesTotal {
 	return &AccountMessagesTotal{
-		view.NewTotal(rdbHandle, "view_account_messages_total"),
+		view.NewTotal(rdbHandle, tableName),
 	}
 }
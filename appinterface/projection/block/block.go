@@ -15,6 +15,9 @@ import (
 )
 
 var _ entity_projection.Projection = &Block{}
+var _ entity_projection.Rollbackable = &Block{}
+var _ entity_projection.Resettable = &Block{}
+var _ entity_projection.BatchHandler = &Block{}
 
 // TODO: Listen to council node related events and project council node
 type Block struct {
@@ -37,11 +40,29 @@ func (_ *Block) GetEventsToListen() []string {
 	return []string{event_usecase.BLOCK_CREATED}
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *Block) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *Block) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, "view_blocks")
 }
 
 func (projection *Block) HandleEvents(height int64, events []event_entity.Event) error {
+	return projection.HandleEventsBatch([]entity_projection.HeightEvents{{
+		Height: height,
+		Events: events,
+	}})
+}
+
+// HandleEventsBatch implements projection.BatchHandler and projects all the heights in one transaction
+func (projection *Block) HandleEventsBatch(batch []entity_projection.HeightEvents) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %v", err)
@@ -57,15 +78,57 @@ func (projection *Block) HandleEvents(height int64, events []event_entity.Event)
 	rdbTxHandle := rdbTx.ToHandle()
 	blocksView := view2.NewBlocks(rdbTxHandle)
 
-	for _, event := range events {
-		if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
-			if handleErr := projection.handleBlockCreatedEvent(blocksView, blockCreatedEvent); handleErr != nil {
-				return fmt.Errorf("error handling BlockCreatedEvent: %v", handleErr)
+	for _, heightEvents := range batch {
+		for _, event := range heightEvents.Events {
+			if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
+				if handleErr := projection.handleBlockCreatedEvent(blocksView, blockCreatedEvent); handleErr != nil {
+					return fmt.Errorf("error handling BlockCreatedEvent: %v", handleErr)
+				}
+			} else {
+				return fmt.Errorf("received unexpected event %sV%d(%s)", event.Name(), event.Version(), event.UUID())
 			}
-		} else {
-			return fmt.Errorf("received unexpected event %sV%d(%s)", event.Name(), event.Version(), event.UUID())
 		}
 	}
+	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, batch[len(batch)-1].Height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+// RollbackTo implements projection.Rollbackable and removes all projected rows after `height`
+func (projection *Block) RollbackTo(height int64) error {
+	lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height: %v", err)
+	}
+	if lastHandledEventHeight == nil || *lastHandledEventHeight <= height {
+		return nil
+	}
+
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	blocksView := view2.NewBlocks(rdbTxHandle)
+
+	if err = blocksView.DeleteAfterHeight(height); err != nil {
+		return fmt.Errorf("error deleting blocks after height %d: %v", height, err)
+	}
+
 	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
 		return fmt.Errorf("error updating last handled event height: %v", err)
 	}
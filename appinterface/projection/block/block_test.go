@@ -30,6 +30,7 @@ var _ = Describe("Block", func() {
 		BeforeEach(func() {
 			_ = pgMigrate.Reset()
 			pgMigrate.MustUp()
+			Expect(block.NewBlock(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
 		})
 
 		AfterEach(func() {
@@ -130,5 +131,27 @@ var _ = Describe("Block", func() {
 			Expect(err).NotTo(BeNil())
 			Expect(blocksView.Count()).To(Equal(int64(0)))
 		})
+
+		It("should not persist any height of the batch when a height fails", func() {
+			blocksView := view2.NewBlocks(pgConn.ToHandle())
+
+			fakeLogger := NewFakeLogger()
+			projection := block.NewBlock(fakeLogger, pgConn)
+
+			err := projection.HandleEventsBatch([]entity_projection.HeightEvents{
+				{Height: 1, Events: []event_entity.Event{}},
+				{Height: 2, Events: []event_entity.Event{NewFakeEvent()}},
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(blocksView.Count()).To(Equal(int64(0)))
+			Expect(projection.GetLastHandledEventHeight()).To(BeNil())
+
+			err = projection.HandleEventsBatch([]entity_projection.HeightEvents{
+				{Height: 1, Events: []event_entity.Event{}},
+				{Height: 2, Events: []event_entity.Event{}},
+			})
+			Expect(err).To(BeNil())
+			Expect(projection.GetLastHandledEventHeight()).To(Equal(primptr.Int64(2)))
+		})
 	})
 })
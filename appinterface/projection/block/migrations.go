@@ -0,0 +1,23 @@
+package block
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept.
+var migrations = map[string]string{
+	"1_view_blocks.up.sql": `
+CREATE TABLE IF NOT EXISTS view_blocks (
+    height BIGINT,
+    hash VARCHAR NOT NULL,
+    time BIGINT NOT NULL,
+    app_hash VARCHAR NOT NULL,
+    committed_council_nodes JSONB NOT NULL,
+    transaction_count INT NOT NULL,
+    UNIQUE(hash),
+    PRIMARY KEY(height)
+);
+CREATE INDEX IF NOT EXISTS view_blocks_height_brin_index ON view_blocks USING brin (height);
+CREATE INDEX IF NOT EXISTS view_blocks_hash_btree_index ON view_blocks USING btree (hash);
+`,
+	"1_view_blocks.down.sql": `
+DROP TABLE IF EXISTS view_blocks;
+`,
+}
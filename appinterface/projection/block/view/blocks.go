@@ -70,6 +70,24 @@ func (blocksView *Blocks) Insert(block *Block) error {
 	return nil
 }
 
+// DeleteAfterHeight deletes all blocks after the provided height
+func (blocksView *Blocks) DeleteAfterHeight(height int64) error {
+	sql, sqlArgs, err := blocksView.rdb.StmtBuilder.Delete(
+		"view_blocks",
+	).Where(
+		"height > ?", height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building blocks deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = blocksView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting blocks from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
 func (blocksView *Blocks) List(order BlocksListOrder, pagination *pagination.Pagination) ([]Block, *pagination.PaginationResult, error) {
 	stmtBuilder := blocksView.rdb.StmtBuilder.Select(
 		"height",
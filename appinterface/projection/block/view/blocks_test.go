@@ -2,7 +2,9 @@ package view_test
 
 import (
 	random "github.com/brianvoe/gofakeit/v5"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/block"
 	"github.com/crypto-com/chain-indexing/appinterface/projection/block/view"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -15,6 +17,7 @@ var _ = Describe("Blocks", func() {
 		BeforeEach(func() {
 			_ = migrate.Reset()
 			migrate.MustUp()
+			Expect(block.NewBlock(NewFakeLogger(), conn).OnInit()).To(BeNil())
 		})
 
 		AfterEach(func() {
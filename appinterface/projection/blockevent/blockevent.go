@@ -8,11 +8,18 @@ import (
 	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	entity_projection "github.com/crypto-com/chain-indexing/entity/projection"
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
 	"github.com/crypto-com/chain-indexing/internal/utctime"
 	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
 )
 
+var _ entity_projection.Projection = &BlockEvent{}
+var _ entity_projection.Rollbackable = &BlockEvent{}
+var _ entity_projection.Resettable = &BlockEvent{}
+var _ entity_projection.BatchHandler = &BlockEvent{}
+
 type BlockEvent struct {
 	*rdbprojectionbase.Base
 
@@ -44,11 +51,29 @@ func (_ *BlockEvent) GetEventsToListen() []string {
 	}
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *BlockEvent) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *BlockEvent) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, "view_block_events", "view_block_events_total")
 }
 
 func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.Event) error {
+	return projection.HandleEventsBatch([]entity_projection.HeightEvents{{
+		Height: height,
+		Events: events,
+	}})
+}
+
+// HandleEventsBatch implements projection.BatchHandler and projects all the heights in one transaction
+func (projection *BlockEvent) HandleEventsBatch(batch []entity_projection.HeightEvents) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
 	var err error
 
 	var rdbTx rdb.Tx
@@ -68,6 +93,33 @@ func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.E
 	eventsView := view.NewBlockEvents(rdbTxHandle)
 	totalView := view.NewBlockEventsTotal(rdbTxHandle)
 
+	for _, heightEvents := range batch {
+		if err = projection.handleHeightEvents(
+			eventsView, totalView, heightEvents.Height, heightEvents.Events,
+		); err != nil {
+			return fmt.Errorf("error handling events at height %d: %v", heightEvents.Height, err)
+		}
+	}
+
+	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, batch[len(batch)-1].Height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+func (projection *BlockEvent) handleHeightEvents(
+	eventsView *view.BlockEvents,
+	totalView *view.BlockEventsTotal,
+	height int64,
+	events []event_entity.Event,
+) error {
+	var err error
+
 	totalMap := make(map[string]int64)
 
 	var blockTime utctime.UTCTime
@@ -79,9 +131,10 @@ func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.E
 			blockHash = blockCreatedEvent.Block.Hash
 		} else {
 			eventRows = append(eventRows, view.BlockEventRow{
-				BlockHeight: height,
-				BlockHash:   "",
-				BlockTime:   utctime.UTCTime{},
+				BlockHeight:    height,
+				BlockHash:      "",
+				BlockTime:      utctime.UTCTime{},
+				MaybeEventUUID: primptr.String(event.UUID()),
 				Data: view.BlockEventRowData{
 					Type:    event.Name(),
 					Content: event,
@@ -120,6 +173,53 @@ func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.E
 		return fmt.Errorf("error batch inserting events into view: %v", err)
 	}
 
+	return nil
+}
+
+// RollbackTo implements projection.Rollbackable and removes all projected rows after `height`
+func (projection *BlockEvent) RollbackTo(height int64) error {
+	lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height: %v", err)
+	}
+	if lastHandledEventHeight == nil || *lastHandledEventHeight <= height {
+		return nil
+	}
+
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	eventsView := view.NewBlockEvents(rdbTxHandle)
+	totalView := view.NewBlockEventsTotal(rdbTxHandle)
+
+	deletedCounts, err := eventsView.DeleteAfterHeight(height)
+	if err != nil {
+		return fmt.Errorf("error deleting events after height %d: %v", height, err)
+	}
+	totalDeleted := int64(0)
+	for eventType, count := range deletedCounts {
+		if err = totalView.Increment(fmt.Sprintf("-:%s", eventType), -count); err != nil {
+			return fmt.Errorf("error decrementing block event type total: %v", err)
+		}
+		totalDeleted += count
+	}
+	if err = totalView.Increment("-", -totalDeleted); err != nil {
+		return fmt.Errorf("error decrementing block event total: %v", err)
+	}
+	if err = totalView.DeleteByHeightAfter(height); err != nil {
+		return fmt.Errorf("error deleting block event totals after height %d: %v", height, err)
+	}
+
 	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
 		return fmt.Errorf("error updating last handled event height: %v", err)
 	}
@@ -35,6 +35,8 @@ var _ = Describe("Block Events", func() {
 		BeforeEach(func() {
 			_ = pgMigrate.Reset()
 			pgMigrate.MustUp()
+			Expect(block.NewBlock(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
+			Expect(blockevent.NewBlockEvent(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
 		})
 
 		AfterEach(func() {
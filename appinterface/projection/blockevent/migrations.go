@@ -0,0 +1,36 @@
+package blockevent
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept, and brought up to date by the later versions.
+var migrations = map[string]string{
+	"1_view_block_events.up.sql": `
+CREATE TABLE IF NOT EXISTS view_block_events (
+   id BIGSERIAL,
+   block_height BIGINT,
+   block_hash VARCHAR NOT NULL,
+   block_time BIGINT NOT NULL,
+   data JSONB NOT NULL,
+   PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS view_block_events_block_height_brin_index ON view_block_events USING brin (block_height);
+CREATE INDEX IF NOT EXISTS view_block_events_block_height_id_brin_index ON view_block_events USING brin (block_height, id);
+CREATE INDEX IF NOT EXISTS view_block_events_block_height_btree_index ON view_block_events USING btree (block_height);
+CREATE INDEX IF NOT EXISTS view_block_events_block_height_id_btree_index ON view_block_events USING btree (block_height, id);
+
+CREATE TABLE IF NOT EXISTS view_block_events_total (
+    identity VARCHAR,
+    total BIGINT NOT NULL,
+    PRIMARY KEY (identity)
+);
+`,
+	"1_view_block_events.down.sql": `
+DROP TABLE IF EXISTS view_block_events_total;
+DROP TABLE IF EXISTS view_block_events;
+`,
+	"2_view_block_events_event_uuid.up.sql": `
+ALTER TABLE view_block_events ADD COLUMN IF NOT EXISTS event_uuid VARCHAR NULL;
+`,
+	"2_view_block_events_event_uuid.down.sql": `
+ALTER TABLE view_block_events DROP COLUMN IF EXISTS event_uuid;
+`,
+}
@@ -35,8 +35,9 @@ func (eventsView *BlockEvents) Insert(blockEvent *BlockEventRow) error {
 		"block_height",
 		"block_hash",
 		"block_time",
+		"event_uuid",
 		"data",
-	).Values("?", "?", "?", "?").ToSql()
+	).Values("?", "?", "?", "?", "?").ToSql()
 	if err != nil {
 		return fmt.Errorf("error building events insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
 	}
@@ -50,6 +51,7 @@ func (eventsView *BlockEvents) Insert(blockEvent *BlockEventRow) error {
 		blockEvent.BlockHeight,
 		blockEvent.BlockHash,
 		eventsView.rdb.Tton(&blockEvent.BlockTime),
+		blockEvent.MaybeEventUUID,
 		blockEventDataJSON,
 	)
 	if err != nil {
@@ -73,6 +75,7 @@ func (eventsView *BlockEvents) InsertAll(blockEvents []BlockEventRow) error {
 		"block_height",
 		"block_hash",
 		"block_time",
+		"event_uuid",
 		"data",
 	)
 	for _, blockEvent := range blockEvents {
@@ -88,6 +91,7 @@ func (eventsView *BlockEvents) InsertAll(blockEvents []BlockEventRow) error {
 			blockEvent.BlockHeight,
 			blockEvent.BlockHash,
 			eventsView.rdb.Tton(&blockEvent.BlockTime),
+			blockEvent.MaybeEventUUID,
 			blockEventDataJSON,
 		)
 	}
@@ -111,6 +115,52 @@ func (eventsView *BlockEvents) InsertAll(blockEvents []BlockEventRow) error {
 	return nil
 }
 
+// DeleteAfterHeight deletes all events after the provided block height and returns the number of
+// events deleted by event type
+func (eventsView *BlockEvents) DeleteAfterHeight(height int64) (map[string]int64, error) {
+	sql, sqlArgs, err := eventsView.rdb.StmtBuilder.Select(
+		"data->>'type'", "COUNT(*)",
+	).From(
+		"view_block_events",
+	).Where(
+		"block_height > ?", height,
+	).GroupBy("data->>'type'").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events count selection sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := eventsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing events count selection sql: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	deletedCounts := make(map[string]int64)
+	for rowsResult.Next() {
+		var eventType string
+		var count int64
+		if err = rowsResult.Scan(&eventType, &count); err != nil {
+			return nil, fmt.Errorf("error scanning events count row: %v: %w", err, rdb.ErrQuery)
+		}
+		deletedCounts[eventType] = count
+	}
+	rowsResult.Close()
+
+	sql, sqlArgs, err = eventsView.rdb.StmtBuilder.Delete(
+		"view_block_events",
+	).Where(
+		"block_height > ?", height,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+	if _, err = eventsView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return nil, fmt.Errorf("error deleting events from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return deletedCounts, nil
+}
+
 func (eventsView *BlockEvents) FindById(id int64) (*BlockEventRow, error) {
 	var err error
 
@@ -119,6 +169,7 @@ func (eventsView *BlockEvents) FindById(id int64) (*BlockEventRow, error) {
 		"block_height",
 		"block_hash",
 		"block_time",
+		"event_uuid",
 		"data",
 	).From(
 		"view_block_events",
@@ -140,6 +191,7 @@ func (eventsView *BlockEvents) FindById(id int64) (*BlockEventRow, error) {
 		&blockEvent.BlockHeight,
 		&blockEvent.BlockHash,
 		blockTimeReader.ScannableArg(),
+		&blockEvent.MaybeEventUUID,
 		&blockEventDataJSON,
 	); err != nil {
 		if errors.Is(err, rdb.ErrNoRows) {
@@ -172,6 +224,7 @@ func (eventsView *BlockEvents) List(
 		"block_height",
 		"block_hash",
 		"block_time",
+		"event_uuid",
 		"data",
 	).From(
 		"view_block_events",
@@ -225,6 +278,7 @@ func (eventsView *BlockEvents) List(
 			&blockEvent.BlockHeight,
 			&blockEvent.BlockHash,
 			blockTimeReader.ScannableArg(),
+			&blockEvent.MaybeEventUUID,
 			&blockEventDataJSON,
 		); err != nil {
 			if errors.Is(err, rdb.ErrNoRows) {
@@ -264,11 +318,12 @@ type BlockEventsListOrder struct {
 }
 
 type BlockEventRow struct {
-	MaybeId     *int64            `json:"id"`
-	BlockHeight int64             `json:"blockHeight"`
-	BlockHash   string            `json:"blockHash"`
-	BlockTime   utctime.UTCTime   `json:"blockTime"`
-	Data        BlockEventRowData `json:"data"`
+	MaybeId        *int64            `json:"id"`
+	BlockHeight    int64             `json:"blockHeight"`
+	BlockHash      string            `json:"blockHash"`
+	BlockTime      utctime.UTCTime   `json:"blockTime"`
+	MaybeEventUUID *string           `json:"eventUuid"`
+	Data           BlockEventRowData `json:"data"`
 }
 
 type BlockEventRowData struct {
@@ -0,0 +1,13 @@
+package deadletter_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeadLetter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dead Letter Suite")
+}
@@ -0,0 +1,292 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+)
+
+const DEFAULT_TABLE = "projection_dead_letters"
+
+// Table should have the following schema
+// | Field          | Data Type | Constraint  |
+// | -------------- | --------- | ----------- |
+// | projection_id  | VARCHAR   | PRIMARY KEY |
+// | height         | INT64     | PRIMARY KEY |
+// | events         | JSONB     | NOT NULL    |
+// | error          | VARCHAR   | NOT NULL    |
+// | status         | VARCHAR   | NOT NULL    |
+// | quarantined_at | INT64     | NOT NULL    |
+
+var _ projection_entity.DeadLetterQueue = &RDbQueue{}
+
+// RDbQueue is a dead letter queue implemented by relational database
+type RDbQueue struct {
+	rdbHandle *rdb.Handle
+
+	table string
+}
+
+func NewRDbQueue(rdbHandle *rdb.Handle) *RDbQueue {
+	return &RDbQueue{
+		rdbHandle,
+
+		DEFAULT_TABLE,
+	}
+}
+
+// DeadLetter is a height quarantined for a projection
+type DeadLetter struct {
+	ProjectionId  string            `json:"projectionId"`
+	Height        int64             `json:"height"`
+	Events        []DeadLetterEvent `json:"events"`
+	Error         string            `json:"error"`
+	Status        string            `json:"status"`
+	QuarantinedAt utctime.UTCTime   `json:"quarantinedAt"`
+}
+
+type DeadLetterEvent struct {
+	UUID    string          `json:"uuid"`
+	Name    string          `json:"name"`
+	Version int             `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func (queue *RDbQueue) Quarantine(
+	projectionId string, height int64, events []entity_event.Event, handleErr error,
+) error {
+	deadLetterEvents := make([]DeadLetterEvent, 0, len(events))
+	for _, event := range events {
+		payload, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("error encoding event %s to JSON: %v", event.UUID(), err)
+		}
+		deadLetterEvents = append(deadLetterEvents, DeadLetterEvent{
+			UUID:    event.UUID(),
+			Name:    event.Name(),
+			Version: event.Version(),
+			Payload: json.RawMessage(payload),
+		})
+	}
+	encodedEvents, err := json.Marshal(deadLetterEvents)
+	if err != nil {
+		return fmt.Errorf("error encoding dead letter events: %v", err)
+	}
+
+	return queue.upsert(
+		projectionId, height, string(encodedEvents), handleErr, projection_entity.DEAD_LETTER_STATUS_QUARANTINED,
+	)
+}
+
+// Halt records the projection is halted after failing to roll back to the height. It is listed as
+// unresolved until the projection is reset, and cannot be retried or skipped.
+func (queue *RDbQueue) Halt(projectionId string, height int64, reason error) error {
+	return queue.upsert(projectionId, height, "[]", reason, projection_entity.DEAD_LETTER_STATUS_HALTED)
+}
+
+func (queue *RDbQueue) upsert(
+	projectionId string, height int64, encodedEvents string, reason error, status string,
+) error {
+	quarantinedAt := utctime.Now()
+	sql, args, err := queue.rdbHandle.StmtBuilder.Insert(
+		queue.table,
+	).Columns(
+		"projection_id", "height", "events", "error", "status", "quarantined_at",
+	).Values(
+		projectionId,
+		height,
+		encodedEvents,
+		reason.Error(),
+		status,
+		queue.rdbHandle.Tton(&quarantinedAt),
+	).Suffix(
+		"ON CONFLICT (projection_id, height) DO UPDATE SET " +
+			"events = EXCLUDED.events, error = EXCLUDED.error, status = EXCLUDED.status, " +
+			"quarantined_at = EXCLUDED.quarantined_at",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building dead letter insertion SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = queue.rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error inserting dead letter: %v: %w", err, rdb.ErrWrite)
+	}
+	return nil
+}
+
+func (queue *RDbQueue) FindHaltedHeight(projectionId string) (*int64, error) {
+	sql, args, err := queue.rdbHandle.StmtBuilder.Select(
+		"height",
+	).From(
+		queue.table,
+	).Where(
+		"projection_id = ? AND status = ?", projectionId, projection_entity.DEAD_LETTER_STATUS_HALTED,
+	).OrderBy(
+		"height",
+	).Limit(1).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building halted height selection SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	var height int64
+	if err = queue.rdbHandle.QueryRow(sql, args...).Scan(&height); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error scanning halted height: %v: %w", err, rdb.ErrQuery)
+	}
+	return &height, nil
+}
+
+func (queue *RDbQueue) ResolveHalt(projectionId string) error {
+	sql, args, err := queue.rdbHandle.StmtBuilder.Update(
+		queue.table,
+	).Set(
+		"status", projection_entity.DEAD_LETTER_STATUS_RESOLVED,
+	).Where(
+		"projection_id = ? AND status = ?", projectionId, projection_entity.DEAD_LETTER_STATUS_HALTED,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building halt resolution SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = queue.rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error resolving halt: %v: %w", err, rdb.ErrWrite)
+	}
+	return nil
+}
+
+func (queue *RDbQueue) GetStatus(projectionId string, height int64) (string, error) {
+	sql, args, err := queue.rdbHandle.StmtBuilder.Select(
+		"status",
+	).From(
+		queue.table,
+	).Where(
+		"projection_id = ? AND height = ?", projectionId, height,
+	).ToSql()
+	if err != nil {
+		return "", fmt.Errorf("error building dead letter status selection SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	var status string
+	if err = queue.rdbHandle.QueryRow(sql, args...).Scan(&status); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return "", rdb.ErrNoRows
+		}
+		return "", fmt.Errorf("error scanning dead letter status: %v: %w", err, rdb.ErrQuery)
+	}
+	return status, nil
+}
+
+func (queue *RDbQueue) UpdateStatus(projectionId string, height int64, status string) error {
+	sql, args, err := queue.rdbHandle.StmtBuilder.Update(
+		queue.table,
+	).Set(
+		"status", status,
+	).Where(
+		"projection_id = ? AND height = ?", projectionId, height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building dead letter status update SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := queue.rdbHandle.Exec(sql, args...)
+	if err != nil {
+		return fmt.Errorf("error updating dead letter status: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() == 0 {
+		return rdb.ErrNoRows
+	}
+	return nil
+}
+
+// RequestAction requests the projection to retry or skip the quarantined height, with status
+// DEAD_LETTER_STATUS_RETRY or DEAD_LETTER_STATUS_SKIP. It returns error when the height is not
+// quarantined.
+func (queue *RDbQueue) RequestAction(projectionId string, height int64, status string) error {
+	if status != projection_entity.DEAD_LETTER_STATUS_RETRY && status != projection_entity.DEAD_LETTER_STATUS_SKIP {
+		return fmt.Errorf("unsupported dead letter action: %s", status)
+	}
+
+	sql, args, err := queue.rdbHandle.StmtBuilder.Update(
+		queue.table,
+	).Set(
+		"status", status,
+	).Where(
+		"projection_id = ? AND height = ? AND status = ?",
+		projectionId, height, projection_entity.DEAD_LETTER_STATUS_QUARANTINED,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building dead letter action SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := queue.rdbHandle.Exec(sql, args...)
+	if err != nil {
+		return fmt.Errorf("error requesting dead letter action: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("height %d of projection `%s` is not quarantined", height, projectionId)
+	}
+	return nil
+}
+
+// ListUnresolved returns the dead letters neither resolved nor skipped, ordered by projection and
+// height
+func (queue *RDbQueue) ListUnresolved() ([]DeadLetter, error) {
+	sql, args, err := queue.rdbHandle.StmtBuilder.Select(
+		"projection_id", "height", "events", "error", "status", "quarantined_at",
+	).From(
+		queue.table,
+	).Where(
+		"status NOT IN (?, ?)",
+		projection_entity.DEAD_LETTER_STATUS_RESOLVED, projection_entity.DEAD_LETTER_STATUS_SKIPPED,
+	).OrderBy(
+		"projection_id", "height",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building dead letters selection SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := queue.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing dead letters selection SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	deadLetters := make([]DeadLetter, 0)
+	for rowsResult.Next() {
+		var deadLetter DeadLetter
+		var encodedEvents string
+		quarantinedAtReader := queue.rdbHandle.NtotReader()
+		if err = rowsResult.Scan(
+			&deadLetter.ProjectionId,
+			&deadLetter.Height,
+			&encodedEvents,
+			&deadLetter.Error,
+			&deadLetter.Status,
+			quarantinedAtReader.ScannableArg(),
+		); err != nil {
+			return nil, fmt.Errorf("error scanning dead letter row: %v: %w", err, rdb.ErrQuery)
+		}
+		if err = json.Unmarshal([]byte(encodedEvents), &deadLetter.Events); err != nil {
+			return nil, fmt.Errorf("error decoding dead letter events: %v", err)
+		}
+		quarantinedAt, err := quarantinedAtReader.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing dead letter quarantined time: %v: %w", err, rdb.ErrQuery)
+		}
+		deadLetter.QuarantinedAt = *quarantinedAt
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	if err = rowsResult.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dead letter rows: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return deadLetters, nil
+}
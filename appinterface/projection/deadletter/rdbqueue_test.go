@@ -0,0 +1,94 @@
+package deadletter_test
+
+import (
+	"errors"
+
+	. "github.com/crypto-com/chain-indexing/entity/event/test"
+	. "github.com/crypto-com/chain-indexing/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/deadletter"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+var _ = Describe("RDbQueue", func() {
+	WithTestPgxConn(func(pgxConn *pg.PgxConn, pgMigrate *pg.Migrate) {
+		BeforeEach(func() {
+			_ = pgMigrate.Reset()
+			pgMigrate.MustUp()
+		})
+
+		AfterEach(func() {
+			_ = pgMigrate.Reset()
+		})
+
+		It("should keep the quarantined height until it is resolved", func() {
+			queue := deadletter.NewRDbQueue(pgxConn.ToHandle())
+
+			Expect(queue.Quarantine(
+				"AnyProjection", 10, []entity_event.Event{NewFakeEvent()}, errors.New("malformed event"),
+			)).To(BeNil())
+			Expect(queue.GetStatus("AnyProjection", 10)).To(Equal(projection_entity.DEAD_LETTER_STATUS_QUARANTINED))
+
+			deadLetters, err := queue.ListUnresolved()
+			Expect(err).To(BeNil())
+			Expect(deadLetters).To(HaveLen(1))
+			Expect(deadLetters[0].ProjectionId).To(Equal("AnyProjection"))
+			Expect(deadLetters[0].Height).To(Equal(int64(10)))
+			Expect(deadLetters[0].Error).To(Equal("malformed event"))
+			Expect(deadLetters[0].Events).To(HaveLen(1))
+			Expect(deadLetters[0].Events[0].Name).To(Equal("FakeEvent"))
+
+			Expect(queue.RequestAction(
+				"AnyProjection", 10, projection_entity.DEAD_LETTER_STATUS_SKIP,
+			)).To(BeNil())
+			Expect(queue.GetStatus("AnyProjection", 10)).To(Equal(projection_entity.DEAD_LETTER_STATUS_SKIP))
+			// the action is already requested
+			Expect(queue.RequestAction(
+				"AnyProjection", 10, projection_entity.DEAD_LETTER_STATUS_RETRY,
+			)).NotTo(BeNil())
+
+			Expect(queue.UpdateStatus(
+				"AnyProjection", 10, projection_entity.DEAD_LETTER_STATUS_SKIPPED,
+			)).To(BeNil())
+			Expect(queue.ListUnresolved()).To(BeEmpty())
+		})
+
+		It("should return Error when requesting action on a height not quarantined", func() {
+			queue := deadletter.NewRDbQueue(pgxConn.ToHandle())
+
+			Expect(queue.RequestAction(
+				"AnyProjection", 10, projection_entity.DEAD_LETTER_STATUS_RETRY,
+			)).To(MatchError("height 10 of projection `AnyProjection` is not quarantined"))
+		})
+
+		It("should keep the projection halted until the halt is resolved", func() {
+			queue := deadletter.NewRDbQueue(pgxConn.ToHandle())
+
+			Expect(queue.FindHaltedHeight("AnyProjection")).To(BeNil())
+
+			Expect(queue.Halt("AnyProjection", 10, errors.New("rollback not supported"))).To(BeNil())
+			Expect(queue.FindHaltedHeight("AnyProjection")).To(Equal(primptr.Int64(10)))
+			Expect(queue.FindHaltedHeight("AnyOtherProjection")).To(BeNil())
+			// a halted projection can only be reset
+			Expect(queue.RequestAction(
+				"AnyProjection", 10, projection_entity.DEAD_LETTER_STATUS_SKIP,
+			)).To(MatchError("height 10 of projection `AnyProjection` is not quarantined"))
+
+			deadLetters, err := queue.ListUnresolved()
+			Expect(err).To(BeNil())
+			Expect(deadLetters).To(HaveLen(1))
+			Expect(deadLetters[0].Status).To(Equal(projection_entity.DEAD_LETTER_STATUS_HALTED))
+			Expect(deadLetters[0].Error).To(Equal("rollback not supported"))
+			Expect(deadLetters[0].Events).To(BeEmpty())
+
+			Expect(queue.ResolveHalt("AnyProjection")).To(BeNil())
+			Expect(queue.FindHaltedHeight("AnyProjection")).To(BeNil())
+			Expect(queue.ListUnresolved()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+package inmemoryblock
+
+import (
+	"fmt"
+	"sync"
+
+	block_view "github.com/crypto-com/chain-indexing/appinterface/projection/block/view"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/inmemoryprojectionbase"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	entity_projection "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+var _ entity_projection.Projection = &InMemoryBlock{}
+var _ entity_projection.Rollbackable = &InMemoryBlock{}
+
+// InMemoryBlock projects the blocks like the Block projection but keeps them in memory, so that it
+// runs without database. The blocks are lost when the process exits.
+type InMemoryBlock struct {
+	*inmemoryprojectionbase.Base
+
+	logger applogger.Logger
+
+	mutex  sync.RWMutex
+	blocks map[int64]block_view.Block
+}
+
+func NewInMemoryBlock(logger applogger.Logger) *InMemoryBlock {
+	return &InMemoryBlock{
+		Base: inmemoryprojectionbase.NewInMemoryBase("InMemoryBlock"),
+
+		logger: logger,
+
+		blocks: make(map[int64]block_view.Block),
+	}
+}
+
+func (_ *InMemoryBlock) GetEventsToListen() []string {
+	return []string{event_usecase.BLOCK_CREATED}
+}
+
+// OnInit implements projection.Projection, there is nothing to initialize in memory
+func (_ *InMemoryBlock) OnInit() error {
+	return nil
+}
+
+func (projection *InMemoryBlock) HandleEvents(height int64, events []event_entity.Event) error {
+	projection.mutex.Lock()
+	defer projection.mutex.Unlock()
+
+	for _, event := range events {
+		blockCreatedEvent, ok := event.(*event_usecase.BlockCreated)
+		if !ok {
+			return fmt.Errorf("received unexpected event %sV%d(%s)", event.Name(), event.Version(), event.UUID())
+		}
+
+		committedCouncilNodes := make([]block_view.BlockCommittedCouncilNode, 0)
+		for _, signature := range blockCreatedEvent.Block.Signatures {
+			committedCouncilNodes = append(committedCouncilNodes, block_view.BlockCommittedCouncilNode{
+				Address:    signature.ValidatorAddress,
+				Time:       signature.Timestamp,
+				Signature:  signature.Signature,
+				IsProposer: blockCreatedEvent.Block.ProposerAddress == signature.ValidatorAddress,
+			})
+		}
+		projection.blocks[blockCreatedEvent.Block.Height] = block_view.Block{
+			Height:                blockCreatedEvent.Block.Height,
+			Hash:                  blockCreatedEvent.Block.Hash,
+			Time:                  blockCreatedEvent.Block.Time,
+			AppHash:               blockCreatedEvent.Block.AppHash,
+			TransactionCount:      len(blockCreatedEvent.Block.Txs),
+			CommittedCouncilNodes: committedCouncilNodes,
+		}
+	}
+	projection.UpdateLastHandledEventHeight(height)
+
+	return nil
+}
+
+// RollbackTo implements projection.Rollbackable and removes all projected blocks after `height`
+func (projection *InMemoryBlock) RollbackTo(height int64) error {
+	projection.mutex.Lock()
+	defer projection.mutex.Unlock()
+
+	lastHandledEventHeight, _ := projection.GetLastHandledEventHeight()
+	if lastHandledEventHeight == nil || *lastHandledEventHeight <= height {
+		return nil
+	}
+
+	for blockHeight := range projection.blocks {
+		if blockHeight > height {
+			delete(projection.blocks, blockHeight)
+		}
+	}
+	projection.UpdateLastHandledEventHeight(height)
+
+	return nil
+}
+
+// FindByHeight returns the projected block at height, nil if there is none
+func (projection *InMemoryBlock) FindByHeight(height int64) *block_view.Block {
+	projection.mutex.RLock()
+	defer projection.mutex.RUnlock()
+
+	block, ok := projection.blocks[height]
+	if !ok {
+		return nil
+	}
+	return &block
+}
+
+// Count returns the number of projected blocks
+func (projection *InMemoryBlock) Count() int64 {
+	projection.mutex.RLock()
+	defer projection.mutex.RUnlock()
+
+	return int64(len(projection.blocks))
+}
@@ -0,0 +1,13 @@
+package inmemoryblock_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestInMemoryBlock(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "In-memory Block Suite")
+}
@@ -0,0 +1,69 @@
+package inmemoryblock_test
+
+import (
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/inmemoryblock"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	entity_projection "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+var _ = Describe("InMemoryBlock", func() {
+	newBlockCreated := func(height int64) *event_usecase.BlockCreated {
+		return event_usecase.NewBlockCreated(&usecase_model.Block{
+			Height:          height,
+			Hash:            "B69554A020537DA8E7C7610A318180C09BFEB91229BB85D4A78DDA2FACF68A48",
+			Time:            utctime.FromUnixNano(int64(1000000)),
+			AppHash:         "24474D86CBFA7E6328D473C17A9E46CD5A80FFE82A348A74844BF3E2BA2B3AF1",
+			ProposerAddress: "F9E6FFB9B536956201AA138224FD888D03775AB4",
+			Txs:             []string{"AAAMZqICtpjLrA3uEe3Rkg6cqDgQl0iBwG1Wm8ORZRzKL9EB"},
+			Signatures: []usecase_model.BlockSignature{
+				{
+					BlockIdFlag:      2,
+					ValidatorAddress: "F9E6FFB9B536956201AA138224FD888D03775AB4",
+					Timestamp:        utctime.FromUnixNano(int64(1000000)),
+					Signature:        "ZW2pUcKFN/oPQCmdCouchXmgpPyd/Ddo45dhHEMwsBeHTBuSJh15zUMmfl5FZsPHeKC8citFvOm/52bgl5XHCw==",
+				},
+			},
+		})
+	}
+
+	It("should implement projection", func() {
+		var _ entity_projection.Projection = inmemoryblock.NewInMemoryBlock(NewFakeLogger())
+	})
+
+	It("should project the blocks in memory when event is BlockCreated", func() {
+		projection := inmemoryblock.NewInMemoryBlock(NewFakeLogger())
+
+		Expect(projection.GetLastHandledEventHeight()).To(BeNil())
+		Expect(projection.HandleEvents(1, []event_entity.Event{newBlockCreated(1)})).To(BeNil())
+
+		Expect(projection.GetLastHandledEventHeight()).To(Equal(primptr.Int64(1)))
+		Expect(projection.Count()).To(Equal(int64(1)))
+		block := projection.FindByHeight(1)
+		Expect(block).NotTo(BeNil())
+		Expect(block.Hash).To(Equal("B69554A020537DA8E7C7610A318180C09BFEB91229BB85D4A78DDA2FACF68A48"))
+		Expect(block.TransactionCount).To(Equal(1))
+		Expect(block.CommittedCouncilNodes).To(HaveLen(1))
+		Expect(block.CommittedCouncilNodes[0].IsProposer).To(BeTrue())
+	})
+
+	It("should remove the blocks after the height when rolled back", func() {
+		projection := inmemoryblock.NewInMemoryBlock(NewFakeLogger())
+
+		for height := int64(1); height <= 3; height += 1 {
+			Expect(projection.HandleEvents(height, []event_entity.Event{newBlockCreated(height)})).To(BeNil())
+		}
+		Expect(projection.RollbackTo(1)).To(BeNil())
+
+		Expect(projection.GetLastHandledEventHeight()).To(Equal(primptr.Int64(1)))
+		Expect(projection.Count()).To(Equal(int64(1)))
+		Expect(projection.FindByHeight(2)).To(BeNil())
+	})
+})
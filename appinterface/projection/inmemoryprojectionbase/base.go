@@ -0,0 +1,42 @@
+package inmemoryprojectionbase
+
+// Base is a base for projection which keeps track of last handled event height in memory. It
+// implements Id() and GetLastHandledEventHeight() of projection interface. The height is lost when the
+// process exits, so the projection has to keep its projected states in memory as well.
+type Base struct {
+	store *Store
+
+	projectionId string
+}
+
+// Create a new Base with its own Store
+func NewInMemoryBase(projectionId string) *Base {
+	return &Base{
+		store: NewStore(),
+
+		projectionId: projectionId,
+	}
+}
+
+// Create a new Base keeping the height in a Store shared with other projections
+func NewInMemoryBaseWithStore(store *Store, projectionId string) *Base {
+	return &Base{
+		store: store,
+
+		projectionId: projectionId,
+	}
+}
+
+// Implements projection.Id()
+func (base *Base) Id() string {
+	return base.projectionId
+}
+
+func (base *Base) UpdateLastHandledEventHeight(height int64) {
+	base.store.UpdateLastHandledEventHeight(base.projectionId, height)
+}
+
+// Implements projection.GetLastHandledEventHeight()
+func (base *Base) GetLastHandledEventHeight() (*int64, error) {
+	return base.store.GetLastHandledEventHeight(base.projectionId), nil
+}
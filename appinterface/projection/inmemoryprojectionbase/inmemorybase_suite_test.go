@@ -0,0 +1,13 @@
+package inmemoryprojectionbase_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestInMemoryBase(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "In-memory Base Suite")
+}
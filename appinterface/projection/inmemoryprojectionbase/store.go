@@ -0,0 +1,37 @@
+package inmemoryprojectionbase
+
+import "sync"
+
+// Store keeps track of the last handled event height of projections in memory
+type Store struct {
+	mutex                   sync.RWMutex
+	lastHandledEventHeights map[string]int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		lastHandledEventHeights: make(map[string]int64),
+	}
+}
+
+// UpdateLastHandledEventHeight update last handled event height of projection id to provided
+// height
+func (impl *Store) UpdateLastHandledEventHeight(projectionId string, height int64) {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+
+	impl.lastHandledEventHeights[projectionId] = height
+}
+
+// GetLastHandledEventHeight returns the last handled event height, nil if no event has been
+// handled
+func (impl *Store) GetLastHandledEventHeight(projectionId string) *int64 {
+	impl.mutex.RLock()
+	defer impl.mutex.RUnlock()
+
+	height, exist := impl.lastHandledEventHeights[projectionId]
+	if !exist {
+		return nil
+	}
+	return &height
+}
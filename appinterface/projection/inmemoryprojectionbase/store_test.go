@@ -0,0 +1,39 @@
+package inmemoryprojectionbase_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/inmemoryprojectionbase"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+var _ = Describe("Store", func() {
+	It("should return nil when the projection has not handled any event", func() {
+		store := inmemoryprojectionbase.NewStore()
+
+		Expect(store.GetLastHandledEventHeight("Projection")).To(BeNil())
+	})
+
+	It("should keep track of the last handled event height of each projection", func() {
+		store := inmemoryprojectionbase.NewStore()
+
+		store.UpdateLastHandledEventHeight("Projection", 1)
+		store.UpdateLastHandledEventHeight("Projection", 2)
+		store.UpdateLastHandledEventHeight("AnotherProjection", 5)
+
+		Expect(store.GetLastHandledEventHeight("Projection")).To(Equal(primptr.Int64(2)))
+		Expect(store.GetLastHandledEventHeight("AnotherProjection")).To(Equal(primptr.Int64(5)))
+	})
+
+	It("should share the store between bases", func() {
+		store := inmemoryprojectionbase.NewStore()
+		base := inmemoryprojectionbase.NewInMemoryBaseWithStore(store, "Projection")
+
+		base.UpdateLastHandledEventHeight(3)
+
+		Expect(base.Id()).To(Equal("Projection"))
+		Expect(store.GetLastHandledEventHeight("Projection")).To(Equal(primptr.Int64(3)))
+		Expect(base.GetLastHandledEventHeight()).To(Equal(primptr.Int64(3)))
+	})
+})
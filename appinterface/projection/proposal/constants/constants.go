@@ -0,0 +1,17 @@
+package constants
+
+const DEPOSIT_PERIOD Status = "DEPOSIT_PERIOD"
+const VOTING_PERIOD Status = "VOTING_PERIOD"
+const PASSED Status = "PASSED"
+const REJECTED Status = "REJECTED"
+const FAILED Status = "FAILED"
+const DROPPED Status = "DROPPED"
+
+type Status = string
+
+const VOTE_OPTION_YES VoteOption = "VOTE_OPTION_YES"
+const VOTE_OPTION_ABSTAIN VoteOption = "VOTE_OPTION_ABSTAIN"
+const VOTE_OPTION_NO VoteOption = "VOTE_OPTION_NO"
+const VOTE_OPTION_NO_WITH_VETO VoteOption = "VOTE_OPTION_NO_WITH_VETO"
+
+type VoteOption = string
@@ -0,0 +1,64 @@
+package proposal
+
+// migrations of the tables owned by the projection
+var migrations = map[string]string{
+	"1_view_proposals.up.sql": `
+CREATE TABLE IF NOT EXISTS view_proposals (
+    proposal_id BIGINT NOT NULL,
+    type VARCHAR NOT NULL,
+    title VARCHAR NOT NULL,
+    description VARCHAR NOT NULL,
+    content JSONB NOT NULL,
+    status VARCHAR NOT NULL,
+    proposer_address VARCHAR NOT NULL,
+    initial_deposit VARCHAR NOT NULL,
+    total_deposit VARCHAR NOT NULL,
+    vote_count_yes BIGINT NOT NULL,
+    vote_count_abstain BIGINT NOT NULL,
+    vote_count_no BIGINT NOT NULL,
+    vote_count_no_with_veto BIGINT NOT NULL,
+    transaction_hash VARCHAR NOT NULL,
+    submit_block_height BIGINT NOT NULL,
+    submit_time BIGINT NOT NULL,
+    deposit_end_time BIGINT NULL,
+    voting_start_time BIGINT NULL,
+    voting_end_time BIGINT NULL,
+    PRIMARY KEY (proposal_id)
+);
+CREATE INDEX IF NOT EXISTS view_proposals_status_btree_index ON view_proposals USING btree (status);
+
+CREATE TABLE IF NOT EXISTS view_proposal_deposits (
+    id BIGSERIAL,
+    proposal_id BIGINT NOT NULL,
+    depositor VARCHAR NOT NULL,
+    amount VARCHAR NOT NULL,
+    transaction_hash VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS view_proposal_deposits_proposal_id_btree_index ON view_proposal_deposits USING btree (proposal_id);
+
+CREATE TABLE IF NOT EXISTS view_proposal_votes (
+    proposal_id BIGINT NOT NULL,
+    voter VARCHAR NOT NULL,
+    option VARCHAR NOT NULL,
+    transaction_hash VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT NOT NULL,
+    PRIMARY KEY (proposal_id, voter)
+);
+
+CREATE TABLE IF NOT EXISTS view_proposal_params (
+    key VARCHAR NOT NULL,
+    value VARCHAR NOT NULL,
+    PRIMARY KEY (key)
+);
+`,
+	"1_view_proposals.down.sql": `
+DROP TABLE IF EXISTS view_proposal_params;
+DROP TABLE IF EXISTS view_proposal_votes;
+DROP TABLE IF EXISTS view_proposal_deposits;
+DROP TABLE IF EXISTS view_proposals;
+`,
+}
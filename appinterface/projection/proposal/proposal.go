@@ -0,0 +1,689 @@
+package proposal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/proposal/constants"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/proposal/view"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+var _ projection_entity.Projection = &Proposal{}
+var _ projection_entity.Resettable = &Proposal{}
+
+// PARAM_CHANGE_PROPOSAL_TYPE is the type of the proposals changing params, of which the governance
+// params changes are applied once passed
+const PARAM_CHANGE_PROPOSAL_TYPE = "/cosmos.params.v1beta1.ParameterChangeProposal"
+
+// Subspace and keys of the governance params in param changes
+const (
+	GOV_PARAMS_SUBSPACE           = "gov"
+	GOV_PARAMS_KEY_DEPOSIT_PARAMS = "depositparams"
+	GOV_PARAMS_KEY_VOTING_PARAMS  = "votingparams"
+)
+
+var ownedTables = []string{"view_proposals", "view_proposal_deposits", "view_proposal_votes", "view_proposal_params"}
+
+// endedProposalStatuses maps the result of an ended proposal to its final status
+var endedProposalStatuses = map[string]constants.Status{
+	"proposal_passed":   constants.PASSED,
+	"proposal_rejected": constants.REJECTED,
+	"proposal_failed":   constants.FAILED,
+}
+
+// Proposal projects the lifecycle of the governance proposals together with their deposits and votes.
+// The deposit and voting periods are derived from the governance params in genesis, updated by the
+// passed param change proposals. When they are unknown, e.g. indexing did not start from genesis, a
+// proposal stays in deposit period until it ends.
+type Proposal struct {
+	*rdbprojectionbase.Base
+
+	rdbConn rdb.Conn
+	logger  applogger.Logger
+
+	baseDenom string
+}
+
+func NewProposal(logger applogger.Logger, rdbConn rdb.Conn, baseDenom string) *Proposal {
+	return &Proposal{
+		rdbprojectionbase.NewRDbBase(rdbConn.ToHandle(), "Proposal"),
+
+		rdbConn,
+		logger,
+
+		baseDenom,
+	}
+}
+
+func (_ *Proposal) GetEventsToListen() []string {
+	return []string{
+		event_usecase.BLOCK_CREATED,
+		event_usecase.GENESIS_CREATED,
+		event_usecase.MSG_SUBMIT_TEXT_PROPOSAL_CREATED,
+		event_usecase.MSG_SUBMIT_PARAM_CHANGE_PROPOSAL_CREATED,
+		event_usecase.MSG_SUBMIT_COMMUNITY_POOL_SPEND_PROPOSAL_CREATED,
+		event_usecase.MSG_SUBMIT_SOFTWARE_UPGRADE_PROPOSAL_CREATED,
+		event_usecase.MSG_SUBMIT_CANCEL_SOFTWARE_UPGRADE_PROPOSAL_CREATED,
+		event_usecase.MSG_DEPOSIT_CREATED,
+		event_usecase.MSG_VOTE_CREATED,
+		event_usecase.PROPOSAL_ENDED,
+		event_usecase.PROPOSAL_INACTIVED,
+	}
+}
+
+// OnInit implements projection.Projection and applies the migrations of the owned tables
+func (projection *Proposal) OnInit() error {
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *Proposal) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, ownedTables...)
+}
+
+func (projection *Proposal) HandleEvents(height int64, events []event_entity.Event) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	views := proposalViews{
+		proposals: view.NewProposals(rdbTxHandle),
+		deposits:  view.NewProposalDeposits(rdbTxHandle),
+		votes:     view.NewProposalVotes(rdbTxHandle),
+		params:    view.NewProposalParams(rdbTxHandle),
+	}
+
+	var blockTime utctime.UTCTime
+	for _, event := range events {
+		if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
+			blockTime = blockCreatedEvent.Block.Time
+		} else if genesisCreatedEvent, ok := event.(*event_usecase.GenesisCreated); ok {
+			projection.logger.Debug("handling GenesisCreated event")
+			if err := projection.handleGenesisCreated(views.params, genesisCreatedEvent); err != nil {
+				return fmt.Errorf("error handling GenesisCreated event: %v", err)
+			}
+		}
+	}
+
+	// most blocks have no proposal submitted or deposited to
+	var params govParams
+	if needsGovParams(events) {
+		if params, err = loadGovParams(views.params); err != nil {
+			return fmt.Errorf("error loading governance params: %v", err)
+		}
+	}
+
+	// proposals are ended at the end of the block, after the messages of the block are handled
+	for _, event := range events {
+		if submittedProposal := newSubmittedProposal(event); submittedProposal != nil {
+			projection.logger.Debug("handling MsgSubmitProposal event")
+			if err := projection.handleProposalSubmitted(
+				views, params, height, blockTime, submittedProposal,
+			); err != nil {
+				return fmt.Errorf("error handling MsgSubmitProposal event: %v", err)
+			}
+		} else if msgDepositEvent, ok := event.(*event_usecase.MsgDeposit); ok {
+			projection.logger.Debug("handling MsgDeposit event")
+			if err := projection.handleMsgDeposit(views, params, height, blockTime, msgDepositEvent); err != nil {
+				return fmt.Errorf("error handling MsgDeposit event: %v", err)
+			}
+		} else if msgVoteEvent, ok := event.(*event_usecase.MsgVote); ok {
+			projection.logger.Debug("handling MsgVote event")
+			if err := projection.handleMsgVote(views, height, blockTime, msgVoteEvent); err != nil {
+				return fmt.Errorf("error handling MsgVote event: %v", err)
+			}
+		}
+	}
+	for _, event := range events {
+		if proposalEndedEvent, ok := event.(*event_usecase.ProposalEnded); ok {
+			projection.logger.Debug("handling ProposalEnded event")
+			if err := projection.handleProposalEnded(views, blockTime, proposalEndedEvent); err != nil {
+				return fmt.Errorf("error handling ProposalEnded event: %v", err)
+			}
+		} else if proposalInactivedEvent, ok := event.(*event_usecase.ProposalInactived); ok {
+			projection.logger.Debug("handling ProposalInactived event")
+			if err := projection.handleProposalInactived(views, blockTime, proposalInactivedEvent); err != nil {
+				return fmt.Errorf("error handling ProposalInactived event: %v", err)
+			}
+		}
+	}
+
+	if err := projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+type proposalViews struct {
+	proposals *view.Proposals
+	deposits  *view.ProposalDeposits
+	votes     *view.ProposalVotes
+	params    *view.ProposalParams
+}
+
+func (projection *Proposal) handleGenesisCreated(
+	paramsView *view.ProposalParams,
+	event *event_usecase.GenesisCreated,
+) error {
+	govGenesis := event.Genesis.AppState.Gov
+	for _, minDeposit := range govGenesis.DepositParams.MinDeposit {
+		if minDeposit.Denom != projection.baseDenom {
+			continue
+		}
+		if err := paramsView.Set(view.PARAM_MIN_DEPOSIT, minDeposit.Amount); err != nil {
+			return fmt.Errorf("error setting min deposit: %v", err)
+		}
+	}
+	if govGenesis.DepositParams.MaxDepositPeriod != "" {
+		if err := paramsView.Set(view.PARAM_MAX_DEPOSIT_PERIOD, govGenesis.DepositParams.MaxDepositPeriod); err != nil {
+			return fmt.Errorf("error setting max deposit period: %v", err)
+		}
+	}
+	if govGenesis.VotingParams.VotingPeriod != "" {
+		if err := paramsView.Set(view.PARAM_VOTING_PERIOD, govGenesis.VotingParams.VotingPeriod); err != nil {
+			return fmt.Errorf("error setting voting period: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (projection *Proposal) handleProposalSubmitted(
+	views proposalViews,
+	params govParams,
+	height int64,
+	blockTime utctime.UTCTime,
+	submittedProposal *submittedProposal,
+) error {
+	if submittedProposal.maybeProposalId == nil {
+		projection.logger.Infof(
+			"skipping proposal submitted in transaction %s without proposal id", submittedProposal.transactionHash,
+		)
+		return nil
+	}
+	proposalId := *submittedProposal.maybeProposalId
+
+	proposal := view.ProposalRow{
+		ProposalId:           proposalId,
+		Type:                 submittedProposal.proposalType,
+		Title:                submittedProposal.title,
+		Description:          submittedProposal.description,
+		Content:              submittedProposal.content,
+		Status:               constants.DEPOSIT_PERIOD,
+		ProposerAddress:      submittedProposal.proposerAddress,
+		InitialDeposit:       submittedProposal.initialDeposit.String(),
+		TotalDeposit:         submittedProposal.initialDeposit.String(),
+		VoteCounts:           view.ProposalVoteCounts{},
+		TransactionHash:      submittedProposal.transactionHash,
+		SubmitBlockHeight:    height,
+		SubmitTime:           blockTime,
+		MaybeDepositEndTime:  params.endTimeAfter(blockTime, params.maybeMaxDepositPeriod),
+		MaybeVotingStartTime: nil,
+		MaybeVotingEndTime:   nil,
+	}
+	params.startVotingPeriodIfDeposited(&proposal, blockTime)
+	if err := views.proposals.Insert(&proposal); err != nil {
+		return fmt.Errorf("error inserting proposal: %v", err)
+	}
+
+	if submittedProposal.initialDeposit.ToBigInt().Sign() > 0 {
+		if err := views.deposits.Insert(&view.ProposalDepositRow{
+			ProposalId:      proposalId,
+			Depositor:       submittedProposal.proposerAddress,
+			Amount:          submittedProposal.initialDeposit.String(),
+			TransactionHash: submittedProposal.transactionHash,
+			BlockHeight:     height,
+			BlockTime:       blockTime,
+		}); err != nil {
+			return fmt.Errorf("error inserting proposal initial deposit: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (projection *Proposal) handleMsgDeposit(
+	views proposalViews,
+	params govParams,
+	height int64,
+	blockTime utctime.UTCTime,
+	event *event_usecase.MsgDeposit,
+) error {
+	if !event.TxSuccess() {
+		return nil
+	}
+
+	proposal, err := projection.findProposal(views.proposals, event.ProposalId)
+	if err != nil {
+		return err
+	}
+	if proposal == nil {
+		return nil
+	}
+
+	if err := views.deposits.Insert(&view.ProposalDepositRow{
+		ProposalId:      event.ProposalId,
+		Depositor:       event.Depositor,
+		Amount:          event.Amount.String(),
+		TransactionHash: event.TxHash(),
+		BlockHeight:     height,
+		BlockTime:       blockTime,
+	}); err != nil {
+		return fmt.Errorf("error inserting proposal deposit: %v", err)
+	}
+
+	totalDeposit, err := coin.NewCoinFromString(proposal.TotalDeposit)
+	if err != nil {
+		return fmt.Errorf("error parsing proposal total deposit: %v", err)
+	}
+	if totalDeposit, err = totalDeposit.Add(event.Amount); err != nil {
+		return fmt.Errorf("error adding deposit to proposal total deposit: %v", err)
+	}
+	proposal.TotalDeposit = totalDeposit.String()
+	params.startVotingPeriodIfDeposited(proposal, blockTime)
+
+	if err := views.proposals.Update(proposal); err != nil {
+		return fmt.Errorf("error updating proposal: %v", err)
+	}
+	return nil
+}
+
+func (projection *Proposal) handleMsgVote(
+	views proposalViews,
+	height int64,
+	blockTime utctime.UTCTime,
+	event *event_usecase.MsgVote,
+) error {
+	if !event.TxSuccess() {
+		return nil
+	}
+
+	proposal, err := projection.findProposal(views.proposals, event.ProposalId)
+	if err != nil {
+		return err
+	}
+	if proposal == nil {
+		return nil
+	}
+
+	previousVote, err := views.votes.FindBy(event.ProposalId, event.Voter)
+	if err != nil && !errors.Is(err, rdb.ErrNoRows) {
+		return fmt.Errorf("error finding previous vote of voter: %v", err)
+	}
+	if previousVote != nil {
+		countVote(&proposal.VoteCounts, previousVote.Option, -1)
+	}
+	countVote(&proposal.VoteCounts, event.Option, 1)
+
+	if err := views.votes.Upsert(&view.ProposalVoteRow{
+		ProposalId:      event.ProposalId,
+		Voter:           event.Voter,
+		Option:          event.Option,
+		TransactionHash: event.TxHash(),
+		BlockHeight:     height,
+		BlockTime:       blockTime,
+	}); err != nil {
+		return fmt.Errorf("error upserting proposal vote: %v", err)
+	}
+
+	if err := views.proposals.Update(proposal); err != nil {
+		return fmt.Errorf("error updating proposal: %v", err)
+	}
+	return nil
+}
+
+func (projection *Proposal) handleProposalEnded(
+	views proposalViews,
+	blockTime utctime.UTCTime,
+	event *event_usecase.ProposalEnded,
+) error {
+	status, ok := endedProposalStatuses[event.Result]
+	if !ok {
+		return fmt.Errorf("unknown result `%s` of ended proposal %s", event.Result, event.ProposalId)
+	}
+
+	proposal, err := projection.findProposal(views.proposals, event.ProposalId)
+	if err != nil {
+		return err
+	}
+	if proposal == nil {
+		return nil
+	}
+
+	proposal.Status = status
+	if proposal.MaybeVotingEndTime == nil {
+		proposal.MaybeVotingEndTime = &blockTime
+	}
+
+	if err := views.proposals.Update(proposal); err != nil {
+		return fmt.Errorf("error updating proposal: %v", err)
+	}
+
+	if status == constants.PASSED && proposal.Type == PARAM_CHANGE_PROPOSAL_TYPE {
+		if err := projection.applyGovParamChanges(views.params, proposal); err != nil {
+			return fmt.Errorf("error applying governance param changes of proposal %s: %v", proposal.ProposalId, err)
+		}
+	}
+	return nil
+}
+
+// applyGovParamChanges updates the governance params changed by the passed param change proposal
+func (projection *Proposal) applyGovParamChanges(paramsView *view.ProposalParams, proposal *view.ProposalRow) error {
+	encodedContent, err := jsoniter.Marshal(proposal.Content)
+	if err != nil {
+		return fmt.Errorf("error encoding proposal content: %v", err)
+	}
+	var content model.MsgSubmitParamChangeProposalContent
+	if err = jsoniter.Unmarshal(encodedContent, &content); err != nil {
+		return fmt.Errorf("error decoding param change proposal content: %v", err)
+	}
+
+	for _, change := range content.Changes {
+		if change.Subspace != GOV_PARAMS_SUBSPACE {
+			continue
+		}
+
+		var params govParamsChange
+		if err := decodeParamChangeValue(change.Value, &params); err != nil {
+			return fmt.Errorf("error decoding param change `%s`: %v", change.Key, err)
+		}
+
+		switch change.Key {
+		case GOV_PARAMS_KEY_DEPOSIT_PARAMS:
+			for _, minDeposit := range params.MinDeposit {
+				if minDeposit.Denom != projection.baseDenom {
+					continue
+				}
+				if err := paramsView.Set(view.PARAM_MIN_DEPOSIT, minDeposit.Amount); err != nil {
+					return fmt.Errorf("error setting min deposit: %v", err)
+				}
+			}
+			if params.MaxDepositPeriod != "" {
+				if err := setDurationParam(paramsView, view.PARAM_MAX_DEPOSIT_PERIOD, params.MaxDepositPeriod); err != nil {
+					return err
+				}
+			}
+		case GOV_PARAMS_KEY_VOTING_PARAMS:
+			if params.VotingPeriod != "" {
+				if err := setDurationParam(paramsView, view.PARAM_VOTING_PERIOD, params.VotingPeriod); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (projection *Proposal) handleProposalInactived(
+	views proposalViews,
+	blockTime utctime.UTCTime,
+	event *event_usecase.ProposalInactived,
+) error {
+	proposal, err := projection.findProposal(views.proposals, event.ProposalId)
+	if err != nil {
+		return err
+	}
+	if proposal == nil {
+		return nil
+	}
+
+	proposal.Status = constants.DROPPED
+	if proposal.MaybeDepositEndTime == nil {
+		proposal.MaybeDepositEndTime = &blockTime
+	}
+
+	if err := views.proposals.Update(proposal); err != nil {
+		return fmt.Errorf("error updating proposal: %v", err)
+	}
+	return nil
+}
+
+// findProposal returns the proposal, or nil when it is submitted before the first indexed height
+func (projection *Proposal) findProposal(proposalsView *view.Proposals, proposalId string) (*view.ProposalRow, error) {
+	proposal, err := proposalsView.FindById(proposalId)
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			projection.logger.Infof("skipping event of proposal %s which is not indexed", proposalId)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding proposal: %v", err)
+	}
+	return proposal, nil
+}
+
+// countVote adds delta to the number of voters of the vote option
+func countVote(voteCounts *view.ProposalVoteCounts, option string, delta int64) {
+	switch option {
+	case constants.VOTE_OPTION_YES:
+		voteCounts.Yes += delta
+	case constants.VOTE_OPTION_ABSTAIN:
+		voteCounts.Abstain += delta
+	case constants.VOTE_OPTION_NO:
+		voteCounts.No += delta
+	case constants.VOTE_OPTION_NO_WITH_VETO:
+		voteCounts.NoWithVeto += delta
+	}
+}
+
+// submittedProposal is the content common to the messages submitting a proposal of any type
+type submittedProposal struct {
+	maybeProposalId *string
+	proposalType    string
+	title           string
+	description     string
+	content         interface{}
+	proposerAddress string
+	initialDeposit  coin.Coin
+	transactionHash string
+}
+
+// newSubmittedProposal returns the submitted proposal, or nil when the event does not submit a proposal
+func newSubmittedProposal(event event_entity.Event) *submittedProposal {
+	if typedEvent, ok := event.(*event_usecase.MsgSubmitTextProposal); ok {
+		return &submittedProposal{
+			typedEvent.MaybeProposalId,
+			typedEvent.Content.Type,
+			typedEvent.Content.Title,
+			typedEvent.Content.Description,
+			typedEvent.Content,
+			typedEvent.ProposerAddress,
+			typedEvent.InitialDeposit,
+			typedEvent.TxHash(),
+		}
+	} else if typedEvent, ok := event.(*event_usecase.MsgSubmitParamChangeProposal); ok {
+		return &submittedProposal{
+			typedEvent.MaybeProposalId,
+			typedEvent.Content.Type,
+			typedEvent.Content.Title,
+			typedEvent.Content.Description,
+			typedEvent.Content,
+			typedEvent.ProposerAddress,
+			typedEvent.InitialDeposit,
+			typedEvent.TxHash(),
+		}
+	} else if typedEvent, ok := event.(*event_usecase.MsgSubmitCommunityPoolSpendProposal); ok {
+		return &submittedProposal{
+			typedEvent.MaybeProposalId,
+			typedEvent.Content.Type,
+			typedEvent.Content.Title,
+			typedEvent.Content.Description,
+			typedEvent.Content,
+			typedEvent.ProposerAddress,
+			typedEvent.InitialDeposit,
+			typedEvent.TxHash(),
+		}
+	} else if typedEvent, ok := event.(*event_usecase.MsgSubmitSoftwareUpgradeProposal); ok {
+		return &submittedProposal{
+			typedEvent.MaybeProposalId,
+			typedEvent.Content.Type,
+			typedEvent.Content.Title,
+			typedEvent.Content.Description,
+			typedEvent.Content,
+			typedEvent.ProposerAddress,
+			typedEvent.InitialDeposit,
+			typedEvent.TxHash(),
+		}
+	} else if typedEvent, ok := event.(*event_usecase.MsgSubmitCancelSoftwareUpgradeProposal); ok {
+		return &submittedProposal{
+			typedEvent.MaybeProposalId,
+			typedEvent.Content.Type,
+			typedEvent.Content.Title,
+			typedEvent.Content.Description,
+			typedEvent.Content,
+			typedEvent.ProposerAddress,
+			typedEvent.InitialDeposit,
+			typedEvent.TxHash(),
+		}
+	}
+
+	return nil
+}
+
+// govParamsChange is the value of a governance params change, of which the fields not changed are empty
+type govParamsChange struct {
+	MinDeposit []struct {
+		Denom  string `json:"denom"`
+		Amount string `json:"amount"`
+	} `json:"min_deposit"`
+	MaxDepositPeriod string `json:"max_deposit_period"`
+	VotingPeriod     string `json:"voting_period"`
+}
+
+// decodeParamChangeValue decodes the value of a param change, which is usually JSON encoded in a string
+func decodeParamChangeValue(value []byte, decoded interface{}) error {
+	var encoded string
+	if err := jsoniter.Unmarshal(value, &encoded); err == nil {
+		value = []byte(encoded)
+	}
+	return jsoniter.Unmarshal(value, decoded)
+}
+
+// setDurationParam sets the duration param, of which the value in a param change is in nanoseconds
+func setDurationParam(paramsView *view.ProposalParams, key string, value string) error {
+	if nanoseconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		value = time.Duration(nanoseconds).String()
+	} else if _, err := time.ParseDuration(value); err != nil {
+		return fmt.Errorf("error parsing %s: %v", key, err)
+	}
+
+	if err := paramsView.Set(key, value); err != nil {
+		return fmt.Errorf("error setting %s: %v", key, err)
+	}
+	return nil
+}
+
+// govParams are the governance params deciding the deposit and voting periods, nil when unknown
+type govParams struct {
+	maybeMinDeposit       *big.Int
+	maybeMaxDepositPeriod *time.Duration
+	maybeVotingPeriod     *time.Duration
+}
+
+// needsGovParams returns true when any of the events may start a deposit or voting period
+func needsGovParams(events []event_entity.Event) bool {
+	for _, event := range events {
+		if newSubmittedProposal(event) != nil {
+			return true
+		}
+		if _, ok := event.(*event_usecase.MsgDeposit); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func loadGovParams(paramsView *view.ProposalParams) (govParams, error) {
+	var params govParams
+
+	maybeMinDeposit, err := paramsView.Get(view.PARAM_MIN_DEPOSIT)
+	if err != nil {
+		return params, err
+	}
+	if maybeMinDeposit != nil {
+		minDeposit, ok := new(big.Int).SetString(*maybeMinDeposit, 10)
+		if !ok {
+			return params, fmt.Errorf("error parsing min deposit `%s`", *maybeMinDeposit)
+		}
+		params.maybeMinDeposit = minDeposit
+	}
+
+	if params.maybeMaxDepositPeriod, err = loadDurationParam(paramsView, view.PARAM_MAX_DEPOSIT_PERIOD); err != nil {
+		return params, err
+	}
+	if params.maybeVotingPeriod, err = loadDurationParam(paramsView, view.PARAM_VOTING_PERIOD); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+func loadDurationParam(paramsView *view.ProposalParams, key string) (*time.Duration, error) {
+	maybeValue, err := paramsView.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if maybeValue == nil {
+		return nil, nil
+	}
+
+	duration, err := time.ParseDuration(*maybeValue)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", key, err)
+	}
+	return &duration, nil
+}
+
+// endTimeAfter returns the end of the period starting at the time, or nil when the period is unknown
+func (params govParams) endTimeAfter(start utctime.UTCTime, maybePeriod *time.Duration) *utctime.UTCTime {
+	if maybePeriod == nil {
+		return nil
+	}
+	endTime := utctime.FromUnixNano(start.UnixNano() + maybePeriod.Nanoseconds())
+	return &endTime
+}
+
+// startVotingPeriodIfDeposited starts the voting period of the proposal in deposit period once its total
+// deposit reaches the min deposit
+func (params govParams) startVotingPeriodIfDeposited(proposal *view.ProposalRow, blockTime utctime.UTCTime) {
+	if proposal.Status != constants.DEPOSIT_PERIOD || params.maybeMinDeposit == nil {
+		return
+	}
+	totalDeposit, ok := new(big.Int).SetString(proposal.TotalDeposit, 10)
+	if !ok || totalDeposit.Cmp(params.maybeMinDeposit) < 0 {
+		return
+	}
+
+	proposal.Status = constants.VOTING_PERIOD
+	votingStartTime := blockTime
+	proposal.MaybeVotingStartTime = &votingStartTime
+	proposal.MaybeVotingEndTime = params.endTimeAfter(blockTime, params.maybeVotingPeriod)
+}
@@ -0,0 +1,13 @@
+package proposal_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestProposal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Proposal Suite")
+}
@@ -0,0 +1,311 @@
+package proposal_test
+
+import (
+	"encoding/json"
+
+	. "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	. "github.com/crypto-com/chain-indexing/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/proposal"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/proposal/constants"
+	proposal_view "github.com/crypto-com/chain-indexing/appinterface/projection/proposal/view"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	entity_projection "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+const baseDenom = "basetcro"
+
+var _ = Describe("Proposal", func() {
+	anyProposerAddress := "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn"
+	anyDepositorAddress := "tcro1feqh6ad9ytjkr79kjk5nhnl4un3wez0ynurrwv"
+	anyVoterAddress := "tcro1rf3m2yzqj3p7gvhpfm6sdy6j9ntgz9gc3ck4ea"
+
+	newBlockCreated := func(height int64, unixNano int64) *event_usecase.BlockCreated {
+		return event_usecase.NewBlockCreated(&model.Block{
+			Height: height,
+			Hash:   "B69554A020537DA8E7C7610A318180C09BFEB91229BB85D4A78DDA2FACF68A48",
+			Time:   utctime.FromUnixNano(unixNano),
+		})
+	}
+	newMsgCommonParams := func(height int64) event_usecase.MsgCommonParams {
+		return event_usecase.MsgCommonParams{
+			BlockHeight: height,
+			TxHash:      "4936522F7391D425F2A93AD47576F8AEC3947DC907113BE8A2FBCFF8E9F2A416",
+			TxSuccess:   true,
+			MsgIndex:    0,
+		}
+	}
+	newGenesisCreated := func() *event_usecase.GenesisCreated {
+		return event_usecase.NewGenesisCreated(genesis.Genesis{
+			AppState: genesis.AppState{
+				Gov: genesis.Gov{
+					DepositParams: genesis.DepositParams{
+						MaxDepositPeriod: "100s",
+						MinDeposit: []genesis.MinDeposit{{
+							Denom:  baseDenom,
+							Amount: "1000",
+						}},
+					},
+					VotingParams: genesis.VotingParams{
+						VotingPeriod: "200s",
+					},
+				},
+			},
+		})
+	}
+	newMsgSubmitTextProposal := func(height int64, initialDeposit string) *event_usecase.MsgSubmitTextProposal {
+		return event_usecase.NewMsgSubmitTextProposal(newMsgCommonParams(height), model.MsgSubmitTextProposalParams{
+			MaybeProposalId: primptr.String("1"),
+			Content: model.MsgSubmitTextProposalContent{
+				Type:        "/cosmos.gov.v1beta1.TextProposal",
+				Title:       "any title",
+				Description: "any description",
+			},
+			ProposerAddress: anyProposerAddress,
+			InitialDeposit:  coin.MustNewCoinFromString(initialDeposit),
+		})
+	}
+
+	It("should implement projection", func() {
+		fakeLogger := NewFakeLogger()
+		fakeRdbConn := NewFakeRDbConn()
+		var _ entity_projection.Projection = proposal.NewProposal(fakeLogger, fakeRdbConn, baseDenom)
+	})
+
+	WithTestPgxConn(func(pgConn *pg.PgxConn, pgMigrate *pg.Migrate) {
+		BeforeEach(func() {
+			_ = pgMigrate.Reset()
+			pgMigrate.MustUp()
+			Expect(proposal.NewProposal(NewFakeLogger(), pgConn, baseDenom).OnInit()).To(BeNil())
+		})
+
+		AfterEach(func() {
+			_ = pgMigrate.Reset()
+		})
+
+		It("should start the voting period once the deposit reaches the min deposit in genesis", func() {
+			projection := proposal.NewProposal(NewFakeLogger(), pgConn, baseDenom)
+			proposalsView := proposal_view.NewProposals(pgConn.ToHandle())
+			depositsView := proposal_view.NewProposalDeposits(pgConn.ToHandle())
+
+			Expect(projection.HandleEvents(0, []event_entity.Event{newGenesisCreated()})).To(BeNil())
+
+			Expect(projection.HandleEvents(1, []event_entity.Event{
+				newBlockCreated(1, int64(1000000000)),
+				newMsgSubmitTextProposal(1, "400"),
+			})).To(BeNil())
+
+			actual, err := proposalsView.FindById("1")
+			Expect(err).To(BeNil())
+			Expect(actual.Status).To(Equal(constants.DEPOSIT_PERIOD))
+			Expect(actual.Title).To(Equal("any title"))
+			Expect(actual.InitialDeposit).To(Equal("400"))
+			Expect(actual.TotalDeposit).To(Equal("400"))
+			Expect(actual.SubmitBlockHeight).To(Equal(int64(1)))
+			Expect(*actual.MaybeDepositEndTime).To(Equal(utctime.FromUnixNano(int64(101000000000))))
+			Expect(actual.MaybeVotingStartTime).To(BeNil())
+
+			Expect(projection.HandleEvents(2, []event_entity.Event{
+				newBlockCreated(2, int64(2000000000)),
+				event_usecase.NewMsgDeposit(newMsgCommonParams(2), model.MsgDepositParams{
+					ProposalId: "1",
+					Depositor:  anyDepositorAddress,
+					Amount:     coin.MustNewCoinFromString("600"),
+				}),
+			})).To(BeNil())
+
+			actual, err = proposalsView.FindById("1")
+			Expect(err).To(BeNil())
+			Expect(actual.Status).To(Equal(constants.VOTING_PERIOD))
+			Expect(actual.TotalDeposit).To(Equal("1000"))
+			Expect(*actual.MaybeVotingStartTime).To(Equal(utctime.FromUnixNano(int64(2000000000))))
+			Expect(*actual.MaybeVotingEndTime).To(Equal(utctime.FromUnixNano(int64(202000000000))))
+
+			deposits, _, err := depositsView.ListByProposal(
+				"1", proposal_view.ProposalDepositsListOrder{}, pagination.NewOffsetPagination(1, 10),
+			)
+			Expect(err).To(BeNil())
+			Expect(deposits).To(HaveLen(2))
+			Expect(deposits[0].Depositor).To(Equal(anyProposerAddress))
+			Expect(deposits[0].Amount).To(Equal("400"))
+			Expect(deposits[1].Depositor).To(Equal(anyDepositorAddress))
+			Expect(deposits[1].Amount).To(Equal("600"))
+
+			Expect(projection.GetLastHandledEventHeight()).To(Equal(primptr.Int64(2)))
+		})
+
+		It("should count the latest vote of each voter", func() {
+			projection := proposal.NewProposal(NewFakeLogger(), pgConn, baseDenom)
+			proposalsView := proposal_view.NewProposals(pgConn.ToHandle())
+			votesView := proposal_view.NewProposalVotes(pgConn.ToHandle())
+
+			Expect(projection.HandleEvents(0, []event_entity.Event{newGenesisCreated()})).To(BeNil())
+			Expect(projection.HandleEvents(1, []event_entity.Event{
+				newBlockCreated(1, int64(1000000000)),
+				newMsgSubmitTextProposal(1, "1000"),
+			})).To(BeNil())
+
+			Expect(projection.HandleEvents(2, []event_entity.Event{
+				newBlockCreated(2, int64(2000000000)),
+				event_usecase.NewMsgVote(newMsgCommonParams(2), model.MsgVoteParams{
+					ProposalId: "1",
+					Voter:      anyVoterAddress,
+					Option:     constants.VOTE_OPTION_YES,
+				}),
+				event_usecase.NewMsgVote(newMsgCommonParams(2), model.MsgVoteParams{
+					ProposalId: "1",
+					Voter:      anyProposerAddress,
+					Option:     constants.VOTE_OPTION_YES,
+				}),
+			})).To(BeNil())
+			Expect(projection.HandleEvents(3, []event_entity.Event{
+				newBlockCreated(3, int64(3000000000)),
+				event_usecase.NewMsgVote(newMsgCommonParams(3), model.MsgVoteParams{
+					ProposalId: "1",
+					Voter:      anyVoterAddress,
+					Option:     constants.VOTE_OPTION_NO_WITH_VETO,
+				}),
+				event_usecase.NewMsgVote(event_usecase.MsgCommonParams{
+					BlockHeight: 3,
+					TxHash:      "C1CDE2A8A1A4F9F3CE5D4E5D39E3FFB1C0A6F4A77D4E1E2B0D2A6A4E3B1C0D9E",
+					TxSuccess:   false,
+					MsgIndex:    0,
+				}, model.MsgVoteParams{
+					ProposalId: "1",
+					Voter:      anyDepositorAddress,
+					Option:     constants.VOTE_OPTION_NO,
+				}),
+			})).To(BeNil())
+
+			actual, err := proposalsView.FindById("1")
+			Expect(err).To(BeNil())
+			Expect(actual.Status).To(Equal(constants.VOTING_PERIOD))
+			Expect(actual.VoteCounts).To(Equal(proposal_view.ProposalVoteCounts{
+				Yes:        1,
+				Abstain:    0,
+				No:         0,
+				NoWithVeto: 1,
+			}))
+
+			vote, err := votesView.FindBy("1", anyVoterAddress)
+			Expect(err).To(BeNil())
+			Expect(vote.Option).To(Equal(constants.VOTE_OPTION_NO_WITH_VETO))
+			Expect(vote.BlockHeight).To(Equal(int64(3)))
+			_, err = votesView.FindBy("1", anyDepositorAddress)
+			Expect(err).To(MatchError(rdb.ErrNoRows))
+
+			Expect(projection.HandleEvents(4, []event_entity.Event{
+				newBlockCreated(4, int64(202000000000)),
+				event_usecase.NewProposalEnded(4, "1", "proposal_rejected"),
+			})).To(BeNil())
+
+			actual, err = proposalsView.FindById("1")
+			Expect(err).To(BeNil())
+			Expect(actual.Status).To(Equal(constants.REJECTED))
+		})
+
+		It("should apply the governance params changed by the passed param change proposal", func() {
+			projection := proposal.NewProposal(NewFakeLogger(), pgConn, baseDenom)
+			proposalsView := proposal_view.NewProposals(pgConn.ToHandle())
+			paramsView := proposal_view.NewProposalParams(pgConn.ToHandle())
+
+			Expect(projection.HandleEvents(0, []event_entity.Event{newGenesisCreated()})).To(BeNil())
+			Expect(projection.HandleEvents(1, []event_entity.Event{
+				newBlockCreated(1, int64(1000000000)),
+				event_usecase.NewMsgSubmitParamChangeProposal(
+					newMsgCommonParams(1), model.MsgSubmitParamChangeProposalParams{
+						MaybeProposalId: primptr.String("1"),
+						Content: model.MsgSubmitParamChangeProposalContent{
+							Type:        proposal.PARAM_CHANGE_PROPOSAL_TYPE,
+							Title:       "any title",
+							Description: "any description",
+							Changes: []model.MsgSubmitParamChangeProposalChange{
+								{
+									Subspace: "gov",
+									Key:      "votingparams",
+									Value:    json.RawMessage(`"{ \"voting_period\": \"28800000000000\" }"`),
+								},
+								{
+									Subspace: "gov",
+									Key:      "depositparams",
+									Value: json.RawMessage(
+										`"{ \"min_deposit\": [{ \"denom\": \"basetcro\", \"amount\": \"2000\" }] }"`,
+									),
+								},
+							},
+						},
+						ProposerAddress: anyProposerAddress,
+						InitialDeposit:  coin.MustNewCoinFromString("1000"),
+					},
+				),
+			})).To(BeNil())
+
+			// the params are changed only when the proposal is passed
+			Expect(paramsView.Get(proposal_view.PARAM_VOTING_PERIOD)).To(Equal(primptr.String("200s")))
+
+			Expect(projection.HandleEvents(2, []event_entity.Event{
+				newBlockCreated(2, int64(201000000000)),
+				event_usecase.NewProposalEnded(2, "1", "proposal_passed"),
+			})).To(BeNil())
+
+			Expect(paramsView.Get(proposal_view.PARAM_VOTING_PERIOD)).To(Equal(primptr.String("8h0m0s")))
+			Expect(paramsView.Get(proposal_view.PARAM_MIN_DEPOSIT)).To(Equal(primptr.String("2000")))
+			Expect(paramsView.Get(proposal_view.PARAM_MAX_DEPOSIT_PERIOD)).To(Equal(primptr.String("100s")))
+
+			Expect(projection.HandleEvents(3, []event_entity.Event{
+				newBlockCreated(3, int64(300000000000)),
+				event_usecase.NewMsgSubmitTextProposal(newMsgCommonParams(3), model.MsgSubmitTextProposalParams{
+					MaybeProposalId: primptr.String("2"),
+					Content: model.MsgSubmitTextProposalContent{
+						Type:        "/cosmos.gov.v1beta1.TextProposal",
+						Title:       "any title",
+						Description: "any description",
+					},
+					ProposerAddress: anyProposerAddress,
+					InitialDeposit:  coin.MustNewCoinFromString("2000"),
+				}),
+			})).To(BeNil())
+
+			actual, err := proposalsView.FindById("2")
+			Expect(err).To(BeNil())
+			Expect(actual.Status).To(Equal(constants.VOTING_PERIOD))
+			Expect(*actual.MaybeVotingEndTime).To(Equal(utctime.FromUnixNano(int64(28800000000000 + 300000000000))))
+		})
+
+		It("should drop the proposal not reaching the min deposit", func() {
+			projection := proposal.NewProposal(NewFakeLogger(), pgConn, baseDenom)
+			proposalsView := proposal_view.NewProposals(pgConn.ToHandle())
+
+			Expect(projection.HandleEvents(1, []event_entity.Event{
+				newBlockCreated(1, int64(1000000000)),
+				newMsgSubmitTextProposal(1, "0"),
+			})).To(BeNil())
+
+			actual, err := proposalsView.FindById("1")
+			Expect(err).To(BeNil())
+			Expect(actual.MaybeDepositEndTime).To(BeNil())
+
+			Expect(projection.HandleEvents(2, []event_entity.Event{
+				newBlockCreated(2, int64(101000000000)),
+				event_usecase.NewProposalInactived(2, "1", "proposal_dropped"),
+			})).To(BeNil())
+
+			actual, err = proposalsView.FindById("1")
+			Expect(err).To(BeNil())
+			Expect(actual.Status).To(Equal(constants.DROPPED))
+			Expect(*actual.MaybeDepositEndTime).To(Equal(utctime.FromUnixNano(int64(101000000000))))
+		})
+	})
+})
@@ -0,0 +1,153 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	pagination_interface "github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/view"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+)
+
+// ProposalDeposits projection view implemented by relational database
+type ProposalDeposits struct {
+	rdb *rdb.Handle
+}
+
+func NewProposalDeposits(handle *rdb.Handle) *ProposalDeposits {
+	return &ProposalDeposits{
+		handle,
+	}
+}
+
+func (depositsView *ProposalDeposits) Insert(deposit *ProposalDepositRow) error {
+	proposalId, err := parseProposalId(deposit.ProposalId)
+	if err != nil {
+		return err
+	}
+
+	sql, sqlArgs, err := depositsView.rdb.StmtBuilder.Insert(
+		"view_proposal_deposits",
+	).Columns(
+		"proposal_id",
+		"depositor",
+		"amount",
+		"transaction_hash",
+		"block_height",
+		"block_time",
+	).Values(
+		proposalId,
+		deposit.Depositor,
+		deposit.Amount,
+		deposit.TransactionHash,
+		deposit.BlockHeight,
+		depositsView.rdb.Tton(&deposit.BlockTime),
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building proposal deposit insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := depositsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting proposal deposit into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting proposal deposit into the table: no rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+type ProposalDepositsListOrder struct {
+	Id view.ORDER
+}
+
+func (depositsView *ProposalDeposits) ListByProposal(
+	proposalId string,
+	order ProposalDepositsListOrder,
+	pagination *pagination_interface.Pagination,
+) ([]ProposalDepositRow, *pagination_interface.PaginationResult, error) {
+	id, err := parseProposalId(proposalId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stmtBuilder := depositsView.rdb.StmtBuilder.Select(
+		"proposal_id",
+		"depositor",
+		"amount",
+		"transaction_hash",
+		"block_height",
+		"block_time",
+	).From(
+		"view_proposal_deposits",
+	).Where(
+		"proposal_id = ?", id,
+	)
+	if order.Id == view.ORDER_DESC {
+		stmtBuilder = stmtBuilder.OrderBy("id DESC")
+	} else {
+		stmtBuilder = stmtBuilder.OrderBy("id")
+	}
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		depositsView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building proposal deposits select SQL: %v, %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := depositsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing proposal deposits select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	deposits := make([]ProposalDepositRow, 0)
+	for rowsResult.Next() {
+		var deposit ProposalDepositRow
+		var depositProposalId int64
+		blockTimeReader := depositsView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&depositProposalId,
+			&deposit.Depositor,
+			&deposit.Amount,
+			&deposit.TransactionHash,
+			&deposit.BlockHeight,
+			blockTimeReader.ScannableArg(),
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, nil, rdb.ErrNoRows
+			}
+			return nil, nil, fmt.Errorf("error scanning proposal deposit row: %v: %w", err, rdb.ErrQuery)
+		}
+		deposit.ProposalId = strconv.FormatInt(depositProposalId, 10)
+		blockTime, parseErr := blockTimeReader.Parse()
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("error parsing proposal deposit block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		deposit.BlockTime = *blockTime
+
+		deposits = append(deposits, deposit)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return deposits, paginationResult, nil
+}
+
+type ProposalDepositRow struct {
+	ProposalId      string          `json:"proposalId"`
+	Depositor       string          `json:"depositor"`
+	Amount          string          `json:"amount"`
+	TransactionHash string          `json:"transactionHash"`
+	BlockHeight     int64           `json:"blockHeight"`
+	BlockTime       utctime.UTCTime `json:"blockTime"`
+}
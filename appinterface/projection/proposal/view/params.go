@@ -0,0 +1,75 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+// Keys of the governance params kept by the projection
+const (
+	PARAM_MIN_DEPOSIT        = "min_deposit"
+	PARAM_MAX_DEPOSIT_PERIOD = "max_deposit_period"
+	PARAM_VOTING_PERIOD      = "voting_period"
+)
+
+// ProposalParams keeps the governance params to follow the deposit and voting periods of the proposals
+type ProposalParams struct {
+	rdb *rdb.Handle
+}
+
+func NewProposalParams(handle *rdb.Handle) *ProposalParams {
+	return &ProposalParams{
+		handle,
+	}
+}
+
+func (paramsView *ProposalParams) Set(key string, value string) error {
+	sql, sqlArgs, err := paramsView.rdb.StmtBuilder.Insert(
+		"view_proposal_params",
+	).Columns(
+		"key",
+		"value",
+	).Values(
+		key,
+		value,
+	).Suffix("ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building proposal param upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := paramsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting proposal param into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error upserting proposal param into the table: no rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// Get returns the value of the param, or nil when it is unknown
+func (paramsView *ProposalParams) Get(key string) (*string, error) {
+	sql, sqlArgs, err := paramsView.rdb.StmtBuilder.Select(
+		"value",
+	).From(
+		"view_proposal_params",
+	).Where(
+		"key = ?", key,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building proposal param selection sql: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	var value string
+	if err = paramsView.rdb.QueryRow(sql, sqlArgs...).Scan(&value); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error scanning proposal param: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return &value, nil
+}
@@ -0,0 +1,331 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	jsoniter "github.com/json-iterator/go"
+
+	pagination_interface "github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/view"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+)
+
+// Proposals projection view implemented by relational database
+type Proposals struct {
+	rdb *rdb.Handle
+}
+
+func NewProposals(handle *rdb.Handle) *Proposals {
+	return &Proposals{
+		handle,
+	}
+}
+
+func (proposalsView *Proposals) Insert(proposal *ProposalRow) error {
+	proposalId, err := parseProposalId(proposal.ProposalId)
+	if err != nil {
+		return err
+	}
+	contentJSON, err := jsoniter.MarshalToString(proposal.Content)
+	if err != nil {
+		return fmt.Errorf("error JSON marshalling proposal content for insertion: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	sql, sqlArgs, err := proposalsView.rdb.StmtBuilder.Insert(
+		"view_proposals",
+	).Columns(
+		"proposal_id",
+		"type",
+		"title",
+		"description",
+		"content",
+		"status",
+		"proposer_address",
+		"initial_deposit",
+		"total_deposit",
+		"vote_count_yes",
+		"vote_count_abstain",
+		"vote_count_no",
+		"vote_count_no_with_veto",
+		"transaction_hash",
+		"submit_block_height",
+		"submit_time",
+		"deposit_end_time",
+		"voting_start_time",
+		"voting_end_time",
+	).Values(
+		proposalId,
+		proposal.Type,
+		proposal.Title,
+		proposal.Description,
+		contentJSON,
+		proposal.Status,
+		proposal.ProposerAddress,
+		proposal.InitialDeposit,
+		proposal.TotalDeposit,
+		proposal.VoteCounts.Yes,
+		proposal.VoteCounts.Abstain,
+		proposal.VoteCounts.No,
+		proposal.VoteCounts.NoWithVeto,
+		proposal.TransactionHash,
+		proposal.SubmitBlockHeight,
+		proposalsView.rdb.Tton(&proposal.SubmitTime),
+		proposalsView.rdb.Tton(proposal.MaybeDepositEndTime),
+		proposalsView.rdb.Tton(proposal.MaybeVotingStartTime),
+		proposalsView.rdb.Tton(proposal.MaybeVotingEndTime),
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building proposal insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := proposalsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting proposal into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting proposal into the table: no rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// Update updates the lifecycle of the proposal, i.e. its status, deposit, vote counts and periods
+func (proposalsView *Proposals) Update(proposal *ProposalRow) error {
+	proposalId, err := parseProposalId(proposal.ProposalId)
+	if err != nil {
+		return err
+	}
+
+	sql, sqlArgs, err := proposalsView.rdb.StmtBuilder.Update(
+		"view_proposals",
+	).SetMap(map[string]interface{}{
+		"status":                  proposal.Status,
+		"total_deposit":           proposal.TotalDeposit,
+		"vote_count_yes":          proposal.VoteCounts.Yes,
+		"vote_count_abstain":      proposal.VoteCounts.Abstain,
+		"vote_count_no":           proposal.VoteCounts.No,
+		"vote_count_no_with_veto": proposal.VoteCounts.NoWithVeto,
+		"deposit_end_time":        proposalsView.rdb.Tton(proposal.MaybeDepositEndTime),
+		"voting_start_time":       proposalsView.rdb.Tton(proposal.MaybeVotingStartTime),
+		"voting_end_time":         proposalsView.rdb.Tton(proposal.MaybeVotingEndTime),
+	}).Where(
+		"proposal_id = ?", proposalId,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building proposal update sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := proposalsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error updating proposal into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error updating proposal: no rows updated: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (proposalsView *Proposals) FindById(proposalId string) (*ProposalRow, error) {
+	id, err := parseProposalId(proposalId)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, sqlArgs, err := proposalsView.selectStmtBuilder().Where(
+		"proposal_id = ?", id,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building proposal selection sql: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	proposal, err := proposalsView.scanRow(proposalsView.rdb.QueryRow(sql, sqlArgs...))
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+type ProposalsListFilter struct {
+	MaybeStatuses []string
+}
+
+type ProposalsListOrder struct {
+	Id view.ORDER
+}
+
+func (proposalsView *Proposals) List(
+	filter ProposalsListFilter,
+	order ProposalsListOrder,
+	pagination *pagination_interface.Pagination,
+) ([]ProposalRow, *pagination_interface.PaginationResult, error) {
+	stmtBuilder := proposalsView.selectStmtBuilder()
+	if filter.MaybeStatuses != nil {
+		stmtBuilder = stmtBuilder.Where(sq.Eq{"status": filter.MaybeStatuses})
+	}
+	if order.Id == view.ORDER_DESC {
+		stmtBuilder = stmtBuilder.OrderBy("proposal_id DESC")
+	} else {
+		stmtBuilder = stmtBuilder.OrderBy("proposal_id")
+	}
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		proposalsView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building proposals select SQL: %v, %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := proposalsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing proposals select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	proposals := make([]ProposalRow, 0)
+	for rowsResult.Next() {
+		proposal, err := proposalsView.scanRow(rowsResult)
+		if err != nil {
+			return nil, nil, err
+		}
+		proposals = append(proposals, *proposal)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return proposals, paginationResult, nil
+}
+
+func (proposalsView *Proposals) selectStmtBuilder() sq.SelectBuilder {
+	return proposalsView.rdb.StmtBuilder.Select(
+		"proposal_id",
+		"type",
+		"title",
+		"description",
+		"content",
+		"status",
+		"proposer_address",
+		"initial_deposit",
+		"total_deposit",
+		"vote_count_yes",
+		"vote_count_abstain",
+		"vote_count_no",
+		"vote_count_no_with_veto",
+		"transaction_hash",
+		"submit_block_height",
+		"submit_time",
+		"deposit_end_time",
+		"voting_start_time",
+		"voting_end_time",
+	).From(
+		"view_proposals",
+	)
+}
+
+func (proposalsView *Proposals) scanRow(row rdb.RowResult) (*ProposalRow, error) {
+	var proposal ProposalRow
+	var proposalId int64
+	var contentJSON string
+	submitTimeReader := proposalsView.rdb.NtotReader()
+	depositEndTimeReader := proposalsView.rdb.NtotReader()
+	votingStartTimeReader := proposalsView.rdb.NtotReader()
+	votingEndTimeReader := proposalsView.rdb.NtotReader()
+	if err := row.Scan(
+		&proposalId,
+		&proposal.Type,
+		&proposal.Title,
+		&proposal.Description,
+		&contentJSON,
+		&proposal.Status,
+		&proposal.ProposerAddress,
+		&proposal.InitialDeposit,
+		&proposal.TotalDeposit,
+		&proposal.VoteCounts.Yes,
+		&proposal.VoteCounts.Abstain,
+		&proposal.VoteCounts.No,
+		&proposal.VoteCounts.NoWithVeto,
+		&proposal.TransactionHash,
+		&proposal.SubmitBlockHeight,
+		submitTimeReader.ScannableArg(),
+		depositEndTimeReader.ScannableArg(),
+		votingStartTimeReader.ScannableArg(),
+		votingEndTimeReader.ScannableArg(),
+	); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, fmt.Errorf("error scanning proposal row: %v: %w", err, rdb.ErrQuery)
+	}
+	proposal.ProposalId = strconv.FormatInt(proposalId, 10)
+
+	if err := jsoniter.UnmarshalFromString(contentJSON, &proposal.Content); err != nil {
+		return nil, fmt.Errorf("error unmarshalling proposal content JSON: %v: %w", err, rdb.ErrQuery)
+	}
+
+	submitTime, err := submitTimeReader.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing proposal submit time: %v: %w", err, rdb.ErrQuery)
+	}
+	proposal.SubmitTime = *submitTime
+	if proposal.MaybeDepositEndTime, err = depositEndTimeReader.Parse(); err != nil {
+		return nil, fmt.Errorf("error parsing proposal deposit end time: %v: %w", err, rdb.ErrQuery)
+	}
+	if proposal.MaybeVotingStartTime, err = votingStartTimeReader.Parse(); err != nil {
+		return nil, fmt.Errorf("error parsing proposal voting start time: %v: %w", err, rdb.ErrQuery)
+	}
+	if proposal.MaybeVotingEndTime, err = votingEndTimeReader.Parse(); err != nil {
+		return nil, fmt.Errorf("error parsing proposal voting end time: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return &proposal, nil
+}
+
+// parseProposalId converts the proposal id in events and requests to the id stored in the tables
+func parseProposalId(proposalId string) (int64, error) {
+	id, err := strconv.ParseInt(proposalId, 10, 64)
+	if err != nil {
+		return int64(0), fmt.Errorf("error parsing proposal id `%s`: %v: %w", proposalId, err, rdb.ErrTypeConv)
+	}
+	return id, nil
+}
+
+type ProposalRow struct {
+	ProposalId           string             `json:"proposalId"`
+	Type                 string             `json:"type"`
+	Title                string             `json:"title"`
+	Description          string             `json:"description"`
+	Content              interface{}        `json:"content"`
+	Status               string             `json:"status"`
+	ProposerAddress      string             `json:"proposerAddress"`
+	InitialDeposit       string             `json:"initialDeposit"`
+	TotalDeposit         string             `json:"totalDeposit"`
+	VoteCounts           ProposalVoteCounts `json:"voteCounts"`
+	TransactionHash      string             `json:"transactionHash"`
+	SubmitBlockHeight    int64              `json:"submitBlockHeight"`
+	SubmitTime           utctime.UTCTime    `json:"submitTime"`
+	MaybeDepositEndTime  *utctime.UTCTime   `json:"depositEndTime"`
+	MaybeVotingStartTime *utctime.UTCTime   `json:"votingStartTime"`
+	MaybeVotingEndTime   *utctime.UTCTime   `json:"votingEndTime"`
+}
+
+// ProposalVoteCounts is the number of voters by the option of their latest vote. It counts voters and
+// is not the tally of the chain, which is weighted by voting power.
+type ProposalVoteCounts struct {
+	Yes        int64 `json:"yes"`
+	Abstain    int64 `json:"abstain"`
+	No         int64 `json:"no"`
+	NoWithVeto int64 `json:"noWithVeto"`
+}
@@ -0,0 +1,207 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+
+	pagination_interface "github.com/crypto-com/chain-indexing/appinterface/pagination"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/view"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/internal/utctime"
+)
+
+// ProposalVotes projection view implemented by relational database. It keeps the latest vote of each
+// voter on a proposal.
+type ProposalVotes struct {
+	rdb *rdb.Handle
+}
+
+func NewProposalVotes(handle *rdb.Handle) *ProposalVotes {
+	return &ProposalVotes{
+		handle,
+	}
+}
+
+// Upsert inserts the vote, or replaces the previous vote of the voter on the proposal
+func (votesView *ProposalVotes) Upsert(vote *ProposalVoteRow) error {
+	proposalId, err := parseProposalId(vote.ProposalId)
+	if err != nil {
+		return err
+	}
+
+	sql, sqlArgs, err := votesView.rdb.StmtBuilder.Insert(
+		"view_proposal_votes",
+	).Columns(
+		"proposal_id",
+		"voter",
+		"option",
+		"transaction_hash",
+		"block_height",
+		"block_time",
+	).Values(
+		proposalId,
+		vote.Voter,
+		vote.Option,
+		vote.TransactionHash,
+		vote.BlockHeight,
+		votesView.rdb.Tton(&vote.BlockTime),
+	).Suffix(`ON CONFLICT (proposal_id, voter) DO UPDATE SET
+		option = EXCLUDED.option,
+		transaction_hash = EXCLUDED.transaction_hash,
+		block_height = EXCLUDED.block_height,
+		block_time = EXCLUDED.block_time
+	`).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building proposal vote upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := votesView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting proposal vote into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error upserting proposal vote into the table: no rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (votesView *ProposalVotes) FindBy(proposalId string, voter string) (*ProposalVoteRow, error) {
+	id, err := parseProposalId(proposalId)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, sqlArgs, err := votesView.selectStmtBuilder().Where(
+		"proposal_id = ? AND voter = ?", id, voter,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building proposal vote selection sql: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	vote, err := votesView.scanRow(votesView.rdb.QueryRow(sql, sqlArgs...))
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, err
+	}
+
+	return vote, nil
+}
+
+type ProposalVotesListFilter struct {
+	MaybeOptions []string
+}
+
+type ProposalVotesListOrder struct {
+	BlockHeight view.ORDER
+}
+
+func (votesView *ProposalVotes) ListByProposal(
+	proposalId string,
+	filter ProposalVotesListFilter,
+	order ProposalVotesListOrder,
+	pagination *pagination_interface.Pagination,
+) ([]ProposalVoteRow, *pagination_interface.PaginationResult, error) {
+	id, err := parseProposalId(proposalId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stmtBuilder := votesView.selectStmtBuilder().Where(
+		"proposal_id = ?", id,
+	)
+	if filter.MaybeOptions != nil {
+		stmtBuilder = stmtBuilder.Where(sq.Eq{"option": filter.MaybeOptions})
+	}
+	if order.BlockHeight == view.ORDER_DESC {
+		stmtBuilder = stmtBuilder.OrderBy("block_height DESC", "voter")
+	} else {
+		stmtBuilder = stmtBuilder.OrderBy("block_height", "voter")
+	}
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		votesView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building proposal votes select SQL: %v, %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := votesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing proposal votes select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	votes := make([]ProposalVoteRow, 0)
+	for rowsResult.Next() {
+		vote, err := votesView.scanRow(rowsResult)
+		if err != nil {
+			return nil, nil, err
+		}
+		votes = append(votes, *vote)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return votes, paginationResult, nil
+}
+
+func (votesView *ProposalVotes) selectStmtBuilder() sq.SelectBuilder {
+	return votesView.rdb.StmtBuilder.Select(
+		"proposal_id",
+		"voter",
+		"option",
+		"transaction_hash",
+		"block_height",
+		"block_time",
+	).From(
+		"view_proposal_votes",
+	)
+}
+
+func (votesView *ProposalVotes) scanRow(row rdb.RowResult) (*ProposalVoteRow, error) {
+	var vote ProposalVoteRow
+	var proposalId int64
+	blockTimeReader := votesView.rdb.NtotReader()
+	if err := row.Scan(
+		&proposalId,
+		&vote.Voter,
+		&vote.Option,
+		&vote.TransactionHash,
+		&vote.BlockHeight,
+		blockTimeReader.ScannableArg(),
+	); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, fmt.Errorf("error scanning proposal vote row: %v: %w", err, rdb.ErrQuery)
+	}
+	vote.ProposalId = strconv.FormatInt(proposalId, 10)
+
+	blockTime, err := blockTimeReader.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing proposal vote block time: %v: %w", err, rdb.ErrQuery)
+	}
+	vote.BlockTime = *blockTime
+
+	return &vote, nil
+}
+
+type ProposalVoteRow struct {
+	ProposalId      string          `json:"proposalId"`
+	Voter           string          `json:"voter"`
+	Option          string          `json:"option"`
+	TransactionHash string          `json:"transactionHash"`
+	BlockHeight     int64           `json:"blockHeight"`
+	BlockTime       utctime.UTCTime `json:"blockTime"`
+}
@@ -1,11 +1,19 @@
 package rdbprojectionbase
 
 import (
+	"fmt"
+	"strings"
+	"unicode"
+
 	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 )
 
 const DEFAULT_TABLE = "projections"
 
+// MIGRATIONS_TABLE_PREFIX prefixes the tables keeping the applied version of the migrations owned by
+// each projection
+const MIGRATIONS_TABLE_PREFIX = "schema_migrations_projection_"
+
 // Table should have the following schema
 // | Field                     | Data Type | Constraint  |
 // | ------------------------- | --------- | ----------- |
@@ -15,17 +23,24 @@ const DEFAULT_TABLE = "projections"
 // Base is a bas for projection which keeps track of last handled event height using relational
 // database. It implements Id() and GetLastHandledEventHeight() of projection interface.
 type Base struct {
-	rdbHandle *rdb.Handle
-	store     *Store
+	rdbHandle    *rdb.Handle
+	store        *Store
+	versionStore *VersionStore
 
 	projectionId string
+
+	// liveProjectionId and tableVersion are set when the projection builds a version of the tables
+	// of the live projection, see NewRDbVersionBuilderBase
+	liveProjectionId string
+	tableVersion     int
 }
 
 // Create a new Base using table name in the RDb to keep the projection handling records
 func NewRDbBase(rdbHandle *rdb.Handle, projectionId string) *Base {
 	return &Base{
-		rdbHandle: rdbHandle,
-		store:     NewStore(DEFAULT_TABLE),
+		rdbHandle:    rdbHandle,
+		store:        NewStore(DEFAULT_TABLE),
+		versionStore: NewVersionStore(DEFAULT_VERSION_TABLE),
 
 		projectionId: projectionId,
 	}
@@ -34,13 +49,24 @@ func NewRDbBase(rdbHandle *rdb.Handle, projectionId string) *Base {
 // Create a new Base with customize table name in the RDb to keep the projection handling records
 func NewRDbBaseWithTable(rdbHandle *rdb.Handle, projectionId string, table string) *Base {
 	return &Base{
-		rdbHandle: rdbHandle,
-		store:     NewStore(table),
+		rdbHandle:    rdbHandle,
+		store:        NewStore(table),
+		versionStore: NewVersionStore(DEFAULT_VERSION_TABLE),
 
 		projectionId: projectionId,
 	}
 }
 
+// Create a new Base of the projection building version of the tables of the live projection. It keeps
+// track of the last handled event height separately from the live projection.
+func NewRDbVersionBuilderBase(rdbHandle *rdb.Handle, liveProjectionId string, version int) *Base {
+	base := NewRDbBase(rdbHandle, VersionedProjectionId(liveProjectionId, version))
+	base.liveProjectionId = liveProjectionId
+	base.tableVersion = version
+
+	return base
+}
+
 // Implements projection.Id()
 func (base *Base) Id() string {
 	return base.projectionId
@@ -57,3 +83,203 @@ func (base *Base) UpdateLastHandledEventHeight(rdbHandle *rdb.Handle, height int
 func (base *Base) GetLastHandledEventHeight() (*int64, error) {
 	return base.store.GetLastHandledEventHeight(base.rdbHandle, base.projectionId)
 }
+
+// ResetOwnedTables removes all rows of the tables owned by the projection together with its last
+// handled event height in a transaction. It is the building block of projection.Resettable.
+func (base *Base) ResetOwnedTables(rdbConn rdb.Conn, ownedTables ...string) error {
+	rdbTx, err := rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+	rdbTxHandle := rdbTx.ToHandle()
+
+	if len(ownedTables) > 0 {
+		if _, err = rdbTxHandle.Exec(fmt.Sprintf("TRUNCATE %s", strings.Join(ownedTables, ", "))); err != nil {
+			return fmt.Errorf("error truncating projection tables: %v", err)
+		}
+	}
+	if err = base.store.DeleteLastHandledEventHeight(rdbTxHandle, base.projectionId); err != nil {
+		return err
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing projection reset: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+// MigrateOwnedTables applies the migrations of the tables owned by the projection, keyed by file name
+// in the format `<version>_<title>.<up|down>.sql`. The applied version is kept separately for each
+// projection, see MigrationsTableName. It is the building block of projection.OnInit().
+func (base *Base) MigrateOwnedTables(rdbConn rdb.Conn, migrations map[string]string) error {
+	// a version builder owns the same migrations as the live projection
+	projectionId := base.projectionId
+	if base.liveProjectionId != "" {
+		projectionId = base.liveProjectionId
+	}
+
+	migrator, ok := rdbConn.(rdb.Migrator)
+	if !ok {
+		return fmt.Errorf("error migrating tables of projection `%s`: connection does not support migrations", projectionId)
+	}
+	if err := migrator.MigrateUp(migrations, MigrationsTableName(projectionId)); err != nil {
+		return fmt.Errorf("error migrating tables of projection `%s`: %v", projectionId, err)
+	}
+
+	return nil
+}
+
+// MigrationsTableName returns the table keeping the applied version of the migrations owned by the
+// projection, e.g. `schema_migrations_projection_block_event` of projection `BlockEvent`
+func MigrationsTableName(projectionId string) string {
+	var builder strings.Builder
+	builder.WriteString(MIGRATIONS_TABLE_PREFIX)
+	for i, r := range projectionId {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// GetVersion returns the version of the live tables of the projection
+func (base *Base) GetVersion() (int, error) {
+	return base.versionStore.GetVersion(base.rdbHandle, base.projectionId)
+}
+
+// TableName returns the name of the owned table the projection writes to. It is the table of the
+// version being built when the Base is created by NewRDbVersionBuilderBase.
+func (base *Base) TableName(table string) string {
+	if base.tableVersion == 0 {
+		return table
+	}
+	return VersionedTableName(table, base.tableVersion)
+}
+
+// CreateVersionedTables creates the tables of the version being built alongside the live owned tables,
+// with the same columns, constraints and indexes. The tables already created are kept, so that an
+// interrupted build resumes on restart.
+func (base *Base) CreateVersionedTables(rdbConn rdb.Conn, ownedTables ...string) error {
+	if base.tableVersion == 0 {
+		return fmt.Errorf("error creating versioned tables: projection `%s` is not a version builder", base.projectionId)
+	}
+
+	for _, table := range ownedTables {
+		if _, err := rdbConn.Exec(fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING ALL)", base.TableName(table), table,
+		)); err != nil {
+			return fmt.Errorf("error creating table of version %d of %s: %v", base.tableVersion, table, err)
+		}
+	}
+
+	return nil
+}
+
+// SwapInVersionedTables atomically replaces the live owned tables with the tables built by this
+// projection. The live projection takes over the last handled event height of this projection, whose
+// record is removed.
+func (base *Base) SwapInVersionedTables(rdbConn rdb.Conn, ownedTables ...string) error {
+	if base.tableVersion == 0 {
+		return fmt.Errorf("error swapping in versioned tables: projection `%s` is not a version builder", base.projectionId)
+	}
+
+	rdbTx, err := rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+	rdbTxHandle := rdbTx.ToHandle()
+
+	lastHandledEventHeight, err := base.store.GetLastHandledEventHeight(rdbTxHandle, base.projectionId)
+	if err != nil {
+		return err
+	}
+	if lastHandledEventHeight == nil {
+		return fmt.Errorf("error swapping in projection `%s`: no event has been handled", base.projectionId)
+	}
+
+	for _, table := range ownedTables {
+		if err = swapInTable(rdbTxHandle, table, base.TableName(table)); err != nil {
+			return err
+		}
+	}
+	if err = base.store.UpdateLastHandledEventHeight(
+		rdbTxHandle, base.liveProjectionId, *lastHandledEventHeight,
+	); err != nil {
+		return err
+	}
+	if err = base.versionStore.UpdateVersion(rdbTxHandle, base.liveProjectionId, base.tableVersion); err != nil {
+		return err
+	}
+	if err = base.store.DeleteLastHandledEventHeight(rdbTxHandle, base.projectionId); err != nil {
+		return err
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing projection version swap: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+// swapInTable replaces the live table with the versioned table. The serial sequences the versioned
+// table shares with the live table are handed over before the live table is dropped.
+func swapInTable(rdbHandle *rdb.Handle, liveTable string, versionedTable string) error {
+	rowsResult, err := rdbHandle.Query(
+		"SELECT attname, pg_get_serial_sequence($1, attname) FROM pg_attribute "+
+			"WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped "+
+			"AND pg_get_serial_sequence($1, attname) IS NOT NULL",
+		liveTable,
+	)
+	if err != nil {
+		return fmt.Errorf("error executing serial sequences selection SQL of %s: %v", liveTable, err)
+	}
+	sequences := make(map[string]string)
+	for rowsResult.Next() {
+		var column, sequence string
+		if err = rowsResult.Scan(&column, &sequence); err != nil {
+			rowsResult.Close()
+			return fmt.Errorf("error scanning serial sequence of %s: %v", liveTable, err)
+		}
+		sequences[column] = sequence
+	}
+	rowsResult.Close()
+	if err = rowsResult.Err(); err != nil {
+		return fmt.Errorf("error iterating serial sequences of %s: %v", liveTable, err)
+	}
+
+	for column, sequence := range sequences {
+		if _, err = rdbHandle.Exec(fmt.Sprintf(
+			"ALTER SEQUENCE %s OWNED BY %s.%s", sequence, versionedTable, column,
+		)); err != nil {
+			return fmt.Errorf("error handing over sequence %s to %s: %v", sequence, versionedTable, err)
+		}
+	}
+	if _, err = rdbHandle.Exec(fmt.Sprintf("DROP TABLE %s", liveTable)); err != nil {
+		return fmt.Errorf("error dropping live table %s: %v", liveTable, err)
+	}
+	if _, err = rdbHandle.Exec(fmt.Sprintf("ALTER TABLE %s RENAME TO %s", versionedTable, liveTable)); err != nil {
+		return fmt.Errorf("error renaming %s to %s: %v", versionedTable, liveTable, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package rdbprojectionbase_test
+
+import (
+	. "github.com/crypto-com/chain-indexing/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	. "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+)
+
+var _ = Describe("Base", func() {
+	Describe("MigrationsTableName", func() {
+		It("should return the migrations table named after the projection Id in snake case", func() {
+			Expect(rdbprojectionbase.MigrationsTableName("Block")).To(Equal("schema_migrations_projection_block"))
+			Expect(rdbprojectionbase.MigrationsTableName("BlockEvent")).To(
+				Equal("schema_migrations_projection_block_event"),
+			)
+		})
+	})
+
+	Describe("MigrateOwnedTables", func() {
+		anyMigrations := map[string]string{
+			"1_view_anys.up.sql":   "CREATE TABLE view_anys (id BIGSERIAL, value VARCHAR NOT NULL, PRIMARY KEY (id));",
+			"1_view_anys.down.sql": "DROP TABLE view_anys;",
+		}
+
+		It("should return error when the connection does not support migrations", func() {
+			base := rdbprojectionbase.NewRDbBase(NewFakeRDbConn().ToHandle(), "AnyProjection")
+
+			err := base.MigrateOwnedTables(NewFakeRDbConn(), anyMigrations)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("connection does not support migrations"))
+		})
+
+		WithTestPgxConn(func(pgxConn *pg.PgxConn, pgMigrate *pg.Migrate) {
+			BeforeEach(func() {
+				_ = pgMigrate.Reset()
+				pgMigrate.MustUp()
+			})
+
+			AfterEach(func() {
+				_ = pgMigrate.Reset()
+			})
+
+			It("should apply the migrations once under the version table of the projection", func() {
+				base := rdbprojectionbase.NewRDbBase(pgxConn.ToHandle(), "AnyProjection")
+
+				Expect(base.MigrateOwnedTables(pgxConn, anyMigrations)).To(BeNil())
+				_, err := pgxConn.Exec("INSERT INTO view_anys (value) VALUES ('any')")
+				Expect(err).To(BeNil())
+
+				// applied migrations are not run again, e.g. by a version builder of the projection
+				builderBase := rdbprojectionbase.NewRDbVersionBuilderBase(pgxConn.ToHandle(), "AnyProjection", 2)
+				Expect(builderBase.MigrateOwnedTables(pgxConn, anyMigrations)).To(BeNil())
+
+				var version int64
+				Expect(pgxConn.QueryRow(
+					"SELECT version FROM schema_migrations_projection_any_projection",
+				).Scan(&version)).To(BeNil())
+				Expect(version).To(Equal(int64(1)))
+			})
+		})
+	})
+})
@@ -96,3 +96,19 @@ func (impl *Store) GetLastHandledEventHeight(rdbHandle *rdb.Handle, projectionId
 
 	return primptr.Int64(lastHandledEventHeight), nil
 }
+
+// DeleteLastHandledEventHeight deletes the last handled event height record of projection id
+func (impl *Store) DeleteLastHandledEventHeight(rdbHandle *rdb.Handle, projectionId string) error {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		impl.table,
+	).Where("id = ?", projectionId).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building last handled event height deletion SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing last handled event height deletion SQL: %v", err)
+	}
+
+	return nil
+}
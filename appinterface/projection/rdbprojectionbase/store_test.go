@@ -52,6 +52,20 @@ var _ = Describe("RdbStoreImpl", func() {
 			})
 		})
 
+		Describe("DeleteLastHandledEventHeight", func() {
+			It("should delete the record of the projection id only", func() {
+				store := rdbprojectionbase.NewStore(rdbprojectionbase.DEFAULT_TABLE)
+
+				Expect(store.UpdateLastHandledEventHeight(pgxConn.ToHandle(), "projection", 1)).To(BeNil())
+				Expect(store.UpdateLastHandledEventHeight(pgxConn.ToHandle(), "other_projection", 1)).To(BeNil())
+
+				Expect(store.DeleteLastHandledEventHeight(pgxConn.ToHandle(), "projection")).To(BeNil())
+
+				Expect(IsProjectionRowExist(pgxConn, "projection")).To(BeFalse())
+				Expect(IsProjectionRowExist(pgxConn, "other_projection")).To(BeTrue())
+			})
+		})
+
 		It("should update projection last handled height when record already exist", func() {
 			var err error
 
@@ -0,0 +1,83 @@
+package rdbprojectionbase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+)
+
+const DEFAULT_VERSION_TABLE = "projection_versions"
+
+// INITIAL_VERSION is the version of the live tables of a projection never swapped
+const INITIAL_VERSION = 1
+
+// Table should have the following schema
+// | Field   | Data Type | Constraint  |
+// | ------- | --------- | ----------- |
+// | id      | VARCHAR   | PRIMARY KEY |
+// | version | INTEGER   | NOT NULL    |
+
+// VersionStore keeps track of the version of the live tables of projections
+type VersionStore struct {
+	table string
+}
+
+func NewVersionStore(table string) *VersionStore {
+	return &VersionStore{
+		table,
+	}
+}
+
+// GetVersion returns the version of the live tables of projection id, INITIAL_VERSION if it has never
+// been swapped
+func (impl *VersionStore) GetVersion(rdbHandle *rdb.Handle, projectionId string) (int, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"version",
+	).From(
+		impl.table,
+	).Where("id = ?", projectionId).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building projection version selection SQL: %v", err)
+	}
+
+	var version int
+	if err := rdbHandle.QueryRow(sql, args...).Scan(&version); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return INITIAL_VERSION, nil
+		}
+		return 0, fmt.Errorf("error executing projection version selection SQL: %v", err)
+	}
+
+	return version, nil
+}
+
+// UpdateVersion updates the version of the live tables of projection id
+func (impl *VersionStore) UpdateVersion(rdbHandle *rdb.Handle, projectionId string, version int) error {
+	sql, args, err := rdbHandle.StmtBuilder.Insert(
+		impl.table,
+	).Columns(
+		"id", "version",
+	).Values(
+		projectionId, version,
+	).Suffix("ON CONFLICT (id) DO UPDATE SET version = EXCLUDED.version").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building projection version upsert SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing projection version upsert SQL: %v", err)
+	}
+
+	return nil
+}
+
+// VersionedProjectionId returns the Id of the projection building version of the live projection
+func VersionedProjectionId(projectionId string, version int) string {
+	return fmt.Sprintf("%s_v%d", projectionId, version)
+}
+
+// VersionedTableName returns the name of the table built for version alongside the live table
+func VersionedTableName(tableName string, version int) string {
+	return fmt.Sprintf("%s_v%d", tableName, version)
+}
@@ -0,0 +1,66 @@
+package rdbprojectionbase_test
+
+import (
+	. "github.com/crypto-com/chain-indexing/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+var _ = Describe("Projection versions", func() {
+	WithTestPgxConn(func(pgxConn *pg.PgxConn, pgMigrate *pg.Migrate) {
+		BeforeEach(func() {
+			_ = pgMigrate.Reset()
+			pgMigrate.MustUp()
+		})
+
+		AfterEach(func() {
+			_, _ = pgxConn.Exec("DROP TABLE IF EXISTS view_anys, view_anys_v2")
+			_ = pgMigrate.Reset()
+		})
+
+		Describe("GetVersion", func() {
+			It("should return the initial version when the projection has never been swapped", func() {
+				base := rdbprojectionbase.NewRDbBase(pgxConn.ToHandle(), "AnyProjection")
+
+				Expect(base.GetVersion()).To(Equal(rdbprojectionbase.INITIAL_VERSION))
+			})
+		})
+
+		Describe("SwapInVersionedTables", func() {
+			It("should replace the live tables and progress with the built version", func() {
+				_, err := pgxConn.Exec("CREATE TABLE view_anys (id BIGSERIAL, value VARCHAR NOT NULL, PRIMARY KEY (id))")
+				Expect(err).To(BeNil())
+				_, err = pgxConn.Exec("INSERT INTO view_anys (value) VALUES ('live')")
+				Expect(err).To(BeNil())
+
+				liveBase := rdbprojectionbase.NewRDbBase(pgxConn.ToHandle(), "AnyProjection")
+				Expect(liveBase.UpdateLastHandledEventHeight(pgxConn.ToHandle(), 10)).To(BeNil())
+
+				builderBase := rdbprojectionbase.NewRDbVersionBuilderBase(pgxConn.ToHandle(), "AnyProjection", 2)
+				Expect(builderBase.Id()).To(Equal("AnyProjection_v2"))
+				Expect(builderBase.TableName("view_anys")).To(Equal("view_anys_v2"))
+				Expect(builderBase.CreateVersionedTables(pgxConn, "view_anys")).To(BeNil())
+				_, err = pgxConn.Exec("INSERT INTO view_anys_v2 (value) VALUES ('built')")
+				Expect(err).To(BeNil())
+				Expect(builderBase.UpdateLastHandledEventHeight(pgxConn.ToHandle(), 8)).To(BeNil())
+
+				Expect(builderBase.SwapInVersionedTables(pgxConn, "view_anys")).To(BeNil())
+
+				var value string
+				Expect(pgxConn.QueryRow("SELECT value FROM view_anys").Scan(&value)).To(BeNil())
+				Expect(value).To(Equal("built"))
+				// the serial sequence survives the live table
+				_, err = pgxConn.Exec("INSERT INTO view_anys (value) VALUES ('next')")
+				Expect(err).To(BeNil())
+
+				Expect(liveBase.GetLastHandledEventHeight()).To(Equal(primptr.Int64(8)))
+				Expect(liveBase.GetVersion()).To(Equal(2))
+				Expect(builderBase.GetLastHandledEventHeight()).To(BeNil())
+			})
+		})
+	})
+})
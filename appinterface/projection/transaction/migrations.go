@@ -0,0 +1,41 @@
+package transaction
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept.
+var migrations = map[string]string{
+	"1_view_transactions.up.sql": `
+CREATE TABLE IF NOT EXISTS view_transactions (
+    id BIGSERIAL,
+    block_height BIGINT,
+    block_hash VARCHAR NOT NULL,
+    block_time BIGINT NOT NULL,
+    hash VARCHAR NOT NULL,
+    success BOOLEAN NOT NULL,
+    code INT NOT NULL,
+    log VARCHAR NOT NULL,
+    fee VARCHAR NOT NULL,
+    fee_payer VARCHAR NOT NULL,
+    fee_granter VARCHAR NOT NULL,
+    gas_wanted BIGINT NOT NULL,
+    gas_used BIGINT NOT NULL,
+    memo VARCHAR NOT NULL,
+    timeout_height BIGINT NOT NULL,
+    messages JSONB NOT NULL,
+    PRIMARY KEY(id)
+);
+CREATE INDEX IF NOT EXISTS view_transactions_block_height_brin_index ON view_transactions USING brin (block_height);
+CREATE INDEX IF NOT EXISTS view_transactions_block_hash_btree_index ON view_transactions(hash);
+CREATE INDEX IF NOT EXISTS view_transactions_block_height_btree_index ON view_transactions USING btree (block_height);
+CREATE INDEX IF NOT EXISTS view_transactions_block_height_id_btree_index ON view_transactions USING btree (block_height, id);
+
+CREATE TABLE IF NOT EXISTS view_transactions_total (
+    identity VARCHAR,
+    total BIGINT NOT NULL,
+    PRIMARY KEY (identity)
+);
+`,
+	"1_view_transactions.down.sql": `
+DROP TABLE IF EXISTS view_transactions_total;
+DROP TABLE IF EXISTS view_transactions;
+`,
+}
@@ -17,6 +17,9 @@ import (
 )
 
 var _ projection_entity.Projection = &Transaction{}
+var _ projection_entity.Rollbackable = &Transaction{}
+var _ projection_entity.Resettable = &Transaction{}
+var _ projection_entity.BatchHandler = &Transaction{}
 
 type Transaction struct {
 	*rdbprojectionbase.Base
@@ -42,11 +45,29 @@ func (_ *Transaction) GetEventsToListen() []string {
 	}, event_usecase.MSG_EVENTS...)
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *Transaction) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *Transaction) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, "view_transactions", "view_transactions_total")
 }
 
 func (projection *Transaction) HandleEvents(height int64, events []event_entity.Event) error {
+	return projection.HandleEventsBatch([]projection_entity.HeightEvents{{
+		Height: height,
+		Events: events,
+	}})
+}
+
+// HandleEventsBatch implements projection.BatchHandler and projects all the heights in one transaction
+func (projection *Transaction) HandleEventsBatch(batch []projection_entity.HeightEvents) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %v", err)
@@ -63,6 +84,31 @@ func (projection *Transaction) HandleEvents(height int64, events []event_entity.
 	transactionsView := transaction_view.NewTransactions(rdbTxHandle)
 	transactionsTotalView := transaction_view.NewTransactionsTotal(rdbTxHandle)
 
+	for _, heightEvents := range batch {
+		if err := projection.handleHeightEvents(
+			transactionsView, transactionsTotalView, heightEvents.Height, heightEvents.Events,
+		); err != nil {
+			return fmt.Errorf("error handling events at height %d: %v", heightEvents.Height, err)
+		}
+	}
+
+	if err := projection.UpdateLastHandledEventHeight(rdbTxHandle, batch[len(batch)-1].Height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+func (projection *Transaction) handleHeightEvents(
+	transactionsView *transaction_view.BlockTransactions,
+	transactionsTotalView *transaction_view.TransactionsTotal,
+	height int64,
+	events []event_entity.Event,
+) error {
 	var blockTime utctime.UTCTime
 	var blockHash string
 	txs := make([]transaction_view.TransactionRow, 0)
@@ -138,11 +184,51 @@ func (projection *Transaction) HandleEvents(height int64, events []event_entity.
 		return fmt.Errorf("error setting total blcok transactions: %w", err)
 	}
 
-	if err := projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
+	return nil
+}
+
+// RollbackTo implements projection.Rollbackable and removes all projected rows after `height`
+func (projection *Transaction) RollbackTo(height int64) error {
+	lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height: %v", err)
+	}
+	if lastHandledEventHeight == nil || *lastHandledEventHeight <= height {
+		return nil
+	}
+
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	transactionsView := transaction_view.NewTransactions(rdbTxHandle)
+	transactionsTotalView := transaction_view.NewTransactionsTotal(rdbTxHandle)
+
+	deletedTxs, err := transactionsView.DeleteAfterHeight(height)
+	if err != nil {
+		return fmt.Errorf("error deleting transactions after height %d: %v", height, err)
+	}
+	if err = transactionsTotalView.Increment("-", -deletedTxs); err != nil {
+		return fmt.Errorf("error decrementing total transactions: %w", err)
+	}
+	if err = transactionsTotalView.DeleteByHeightAfter(height); err != nil {
+		return fmt.Errorf("error deleting total block transactions after height %d: %w", height, err)
+	}
+
+	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
 		return fmt.Errorf("error updating last handled event height: %v", err)
 	}
 
-	if err := rdbTx.Commit(); err != nil {
+	if err = rdbTx.Commit(); err != nil {
 		return fmt.Errorf("error committing changes: %v", err)
 	}
 	committed = true
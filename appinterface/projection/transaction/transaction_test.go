@@ -30,6 +30,7 @@ var _ = Describe("Transaction", func() {
 		BeforeEach(func() {
 			_ = pgMigrate.Reset()
 			pgMigrate.MustUp()
+			Expect(block.NewBlock(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
 		})
 
 		AfterEach(func() {
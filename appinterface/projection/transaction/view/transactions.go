@@ -88,6 +88,26 @@ func (transactionsView *BlockTransactions) Insert(transaction *TransactionRow) e
 	return nil
 }
 
+// DeleteAfterHeight deletes all transactions after the provided block height and returns the number
+// of transactions deleted
+func (transactionsView *BlockTransactions) DeleteAfterHeight(height int64) (int64, error) {
+	sql, sqlArgs, err := transactionsView.rdb.StmtBuilder.Delete(
+		"view_transactions",
+	).Where(
+		"block_height > ?", height,
+	).ToSql()
+	if err != nil {
+		return int64(0), fmt.Errorf("error building block transactions deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := transactionsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return int64(0), fmt.Errorf("error deleting block transactions from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (transactionsView *BlockTransactions) FindByHash(txHash string) (*TransactionRow, error) {
 	var err error
 
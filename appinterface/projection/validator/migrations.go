@@ -0,0 +1,66 @@
+package validator
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept, and brought up to date by the later versions.
+var migrations = map[string]string{
+	"1_view_validators.up.sql": `
+CREATE TABLE IF NOT EXISTS view_validators (
+    id BIGSERIAL,
+    operator_address VARCHAR NOT NULL,
+    consensus_node_address VARCHAR,
+    initial_delegator_address VARCHAR NOT NULL,
+    status VARCHAR NOT NULL,
+    jailed BOOL NOT NULL,
+    joined_at_block_height BIGINT NOT NULL,
+    power VARCHAR NOT NULL,
+    unbonding_height BIGINT NULL,
+    unbonding_completion_time BIGINT NULL,
+    moniker VARCHAR NOT NULL,
+    identity VARCHAR NULL,
+    website VARCHAR NULL,
+    security_contact VARCHAR NULL,
+    details VARCHAR NULL,
+    commission_rate VARCHAR NOT NULL,
+    commission_max_rate VARCHAR NOT NULL,
+    commission_max_change_rate VARCHAR NOT NULL,
+    min_self_delegation VARCHAR NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (operator_address, consensus_node_address)
+);
+CREATE INDEX IF NOT EXISTS view_validators_operator_address_btree_index ON view_validators USING btree (operator_address);
+CREATE INDEX IF NOT EXISTS view_validators_consensus_node_address_btree_index ON view_validators USING btree (consensus_node_address);
+
+CREATE TABLE IF NOT EXISTS view_validator_activities (
+    id BIGSERIAL,
+    block_height BIGINT NOT NULL,
+    block_hash VARCHAR NOT NULL,
+    block_time BIGINT NOT NULL,
+    transaction_hash VARCHAR NULL,
+    operator_address VARCHAR NOT NULL,
+    success BOOLEAN NOT NULL,
+    data JSONB NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS view_validator_activities_block_height_brin_index ON view_validator_activities USING brin (block_height);
+CREATE INDEX IF NOT EXISTS view_validator_activities_operator_address_btree_index ON view_validator_activities USING btree (operator_address);
+CREATE INDEX IF NOT EXISTS view_validator_activities_block_height_btree_index ON view_validator_activities USING btree (block_height);
+CREATE INDEX IF NOT EXISTS view_validator_activities_block_height_id_btree_index ON view_validator_activities USING btree (block_height, id);
+
+CREATE TABLE IF NOT EXISTS view_validator_activities_total (
+    identity VARCHAR,
+    total BIGINT NOT NULL,
+    PRIMARY KEY (identity)
+);
+`,
+	"1_view_validators.down.sql": `
+DROP TABLE IF EXISTS view_validator_activities_total;
+DROP TABLE IF EXISTS view_validator_activities;
+DROP TABLE IF EXISTS view_validators;
+`,
+	"2_view_validator_activities_event_uuid.up.sql": `
+ALTER TABLE view_validator_activities ADD COLUMN IF NOT EXISTS event_uuid VARCHAR NULL;
+`,
+	"2_view_validator_activities_event_uuid.down.sql": `
+ALTER TABLE view_validator_activities DROP COLUMN IF EXISTS event_uuid;
+`,
+}
@@ -18,9 +18,15 @@ import (
 )
 
 var _ projection_entity.Projection = &Validator{}
+var _ projection_entity.Resettable = &Validator{}
+var _ projection_entity.Versioned = &Validator{}
 
 const DO_NOT_MODIFY = "[do-not-modify]"
 
+// Cosmos staking bond status of the validator state
+const BOND_STATUS_BONDED = "BOND_STATUS_BONDED"
+const BOND_STATUS_UNBONDING = "BOND_STATUS_UNBONDING"
+
 type Validator struct {
 	*rdbprojectionbase.Base
 
@@ -57,11 +63,18 @@ func (_ *Validator) GetEventsToListen() []string {
 		event_usecase.VALIDATOR_SLASHED,
 		event_usecase.MSG_UNJAIL_CREATED,
 		event_usecase.POWER_CHANGED,
+		event_usecase.VALIDATOR_BOOTSTRAPPED,
 	}
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *Validator) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *Validator) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, projection.ownedTableNames()...)
 }
 
 func (projection *Validator) HandleEvents(height int64, events []event_entity.Event) error {
@@ -78,9 +91,15 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
-	validatorsView := view.NewValidators(rdbTxHandle)
-	validatorActivitiesView := view.NewValidatorActivities(rdbTxHandle)
-	validatorActivitiesTotalView := view.NewValidatorActivitiesTotal(rdbTxHandle)
+	validatorsView := view.NewValidatorsWithTableName(
+		rdbTxHandle, projection.TableName("view_validators"),
+	)
+	validatorActivitiesView := view.NewValidatorActivitiesWithTableName(
+		rdbTxHandle, projection.TableName("view_validator_activities"),
+	)
+	validatorActivitiesTotalView := view.NewValidatorActivitiesTotalWithTableName(
+		rdbTxHandle, projection.TableName("view_validator_activities_total"),
+	)
 
 	var blockTime utctime.UTCTime
 	var blockHash string
@@ -122,6 +141,18 @@ func (projection *Validator) projectValidatorView(
 	blockHeight int64,
 	events []event_entity.Event,
 ) error {
+	// validators bootstrapped from the state are created before any message is handled
+	for _, event := range events {
+		if validatorBootstrappedEvent, ok := event.(*event_usecase.ValidatorBootstrapped); ok {
+			projection.logger.Debug("handling ValidatorBootstrapped event")
+			if err := projection.handleValidatorBootstrapped(
+				validatorsView, blockHeight, validatorBootstrappedEvent,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
 	// MsgCreateValidator should be handled first
 	for _, event := range events {
 		if msgCreateValidatorEvent, ok := event.(*event_usecase.MsgCreateValidator); ok {
@@ -283,3 +314,55 @@ func (projection *Validator) projectValidatorView(
 
 	return nil
 }
+
+func (projection *Validator) handleValidatorBootstrapped(
+	validatorsView *view.Validators,
+	blockHeight int64,
+	event *event_usecase.ValidatorBootstrapped,
+) error {
+	pubKey, err := base64.StdEncoding.DecodeString(event.TendermintPubkey)
+	if err != nil {
+		return fmt.Errorf("error base64 decoding Tendermint node pubkey: %v", err)
+	}
+	consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(
+		projection.conNodeAddressPrefix, pubKey,
+	)
+	if err != nil {
+		return fmt.Errorf("error converting Tendermint node pubkey to address: %v", err)
+	}
+
+	status := constants.UNBONDED
+	if event.Jailed {
+		status = constants.JAILED
+	} else if event.Status == BOND_STATUS_BONDED {
+		status = constants.BONDED
+	} else if event.Status == BOND_STATUS_UNBONDING {
+		status = constants.UNBONDING
+	}
+
+	// the validator may have joined before the bootstrap height, which is the earliest known height
+	if err := validatorsView.Upsert(&view.ValidatorRow{
+		ConsensusNodeAddress:         consensusNodeAddress,
+		OperatorAddress:              event.OperatorAddress,
+		InitialDelegatorAddress:      event.InitialDelegatorAddress,
+		MinSelfDelegation:            event.MinSelfDelegation,
+		Status:                       status,
+		Jailed:                       event.Jailed,
+		JoinedAtBlockHeight:          blockHeight,
+		Power:                        event.Power,
+		MaybeUnbondingHeight:         nil,
+		MaybeUnbondingCompletionTime: nil,
+		Moniker:                      event.Description.Moniker,
+		Identity:                     event.Description.Identity,
+		Website:                      event.Description.Website,
+		SecurityContact:              event.Description.SecurityContact,
+		Details:                      event.Description.Details,
+		CommissionRate:               event.Commission.Rate,
+		CommissionMaxRate:            event.Commission.MaxRate,
+		CommissionMaxChangeRate:      event.Commission.MaxChangeRate,
+	}); err != nil {
+		return fmt.Errorf("error inserting bootstrapped validator into view: %v", err)
+	}
+
+	return nil
+}
@@ -60,6 +60,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(createValidatorEvent.TxHash()),
 				OperatorAddress:      createValidatorEvent.ValidatorAddress,
 				Success:              createValidatorEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(createValidatorEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    createValidatorEvent.MsgType(),
 					Content: createValidatorEvent,
@@ -80,6 +81,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(editValidatorEvent.TxHash()),
 				OperatorAddress:      editValidatorEvent.ValidatorAddress,
 				Success:              editValidatorEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(editValidatorEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    editValidatorEvent.MsgType(),
 					Content: editValidatorEvent,
@@ -100,6 +102,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(delegateEvent.TxHash()),
 				OperatorAddress:      delegateEvent.ValidatorAddress,
 				Success:              delegateEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(delegateEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    delegateEvent.MsgType(),
 					Content: delegateEvent,
@@ -120,6 +123,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(redelegateEvent.TxHash()),
 				OperatorAddress:      redelegateEvent.ValidatorSrcAddress,
 				Success:              redelegateEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(redelegateEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    redelegateEvent.MsgType(),
 					Content: redelegateEvent,
@@ -132,6 +136,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(redelegateEvent.TxHash()),
 				OperatorAddress:      redelegateEvent.ValidatorDstAddress,
 				Success:              redelegateEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(redelegateEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    redelegateEvent.MsgType(),
 					Content: redelegateEvent,
@@ -154,6 +159,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(undelegateEvent.TxHash()),
 				OperatorAddress:      undelegateEvent.ValidatorAddress,
 				Success:              undelegateEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(undelegateEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    undelegateEvent.MsgType(),
 					Content: undelegateEvent,
@@ -174,6 +180,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(withdrawDelegatorRewardEvent.TxHash()),
 				OperatorAddress:      withdrawDelegatorRewardEvent.ValidatorAddress,
 				Success:              withdrawDelegatorRewardEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(withdrawDelegatorRewardEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    withdrawDelegatorRewardEvent.MsgType(),
 					Content: withdrawDelegatorRewardEvent,
@@ -197,6 +204,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(withdrawValidatorCommissionEvent.TxHash()),
 				OperatorAddress:      withdrawValidatorCommissionEvent.ValidatorAddress,
 				Success:              withdrawValidatorCommissionEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(withdrawValidatorCommissionEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    withdrawValidatorCommissionEvent.MsgType(),
 					Content: withdrawValidatorCommissionEvent,
@@ -230,6 +238,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: nil,
 				OperatorAddress:      validatorRow.OperatorAddress,
 				Success:              true,
+				MaybeEventUUID:       primptr.String(validatorJailedEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    validatorJailedEvent.Name(),
 					Content: validatorJailedEvent,
@@ -257,6 +266,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: nil,
 				OperatorAddress:      validatorRow.OperatorAddress,
 				Success:              true,
+				MaybeEventUUID:       primptr.String(validatorSlashedEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    validatorSlashedEvent.Name(),
 					Content: validatorSlashedEvent,
@@ -277,6 +287,7 @@ func (projection *Validator) projectValidatorActivitiesView(
 				MaybeTransactionHash: primptr.String(unjailEvent.TxHash()),
 				OperatorAddress:      unjailEvent.ValidatorAddr,
 				Success:              unjailEvent.TxSuccess(),
+				MaybeEventUUID:       primptr.String(unjailEvent.UUID()),
 				Data: view.ValidatorActivityRowData{
 					Type:    unjailEvent.MsgType(),
 					Content: unjailEvent,
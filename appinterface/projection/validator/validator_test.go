@@ -37,6 +37,8 @@ var _ = Describe("Validator Events", func() {
 		BeforeEach(func() {
 			_ = pgMigrate.Reset()
 			pgMigrate.MustUp()
+			Expect(block.NewBlock(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
+			Expect(validator.NewValidator(NewFakeLogger(), pgConn, prefixConsensusAddress).OnInit()).To(BeNil())
 		})
 
 		AfterEach(func() {
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/rdbprojectionbase"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+)
+
+var _ projection_entity.VersionBuilder = &validatorVersionBuilder{}
+
+// VERSION of the projected tables. Bump it when the projected rows change, the tables of the new
+// version are then built alongside the live ones and swapped in once they have caught up.
+const VERSION = 1
+
+var ownedTables = []string{"view_validators", "view_validator_activities", "view_validator_activities_total"}
+
+func (projection *Validator) ownedTableNames() []string {
+	tableNames := make([]string, 0, len(ownedTables))
+	for _, table := range ownedTables {
+		tableNames = append(tableNames, projection.TableName(table))
+	}
+	return tableNames
+}
+
+// NewVersionBuilder implements projection.Versioned
+func (projection *Validator) NewVersionBuilder() (projection_entity.VersionBuilder, error) {
+	liveVersion, err := projection.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting live version of projection: %v", err)
+	}
+	if liveVersion >= VERSION {
+		return nil, nil
+	}
+
+	builder := &validatorVersionBuilder{
+		&Validator{
+			rdbprojectionbase.NewRDbVersionBuilderBase(projection.rdbConn.ToHandle(), projection.Id(), VERSION),
+
+			projection.rdbConn,
+			projection.logger,
+			projection.conNodeAddressPrefix,
+		},
+	}
+	if err := builder.CreateVersionedTables(projection.rdbConn, ownedTables...); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+type validatorVersionBuilder struct {
+	*Validator
+}
+
+// LiveProjectionId implements projection.VersionBuilder
+func (builder *validatorVersionBuilder) LiveProjectionId() string {
+	return "Validator"
+}
+
+// SwapIn implements projection.VersionBuilder
+func (builder *validatorVersionBuilder) SwapIn() error {
+	return builder.SwapInVersionedTables(builder.rdbConn, ownedTables...)
+}
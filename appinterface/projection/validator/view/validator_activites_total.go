@@ -10,7 +10,13 @@ type ValidatorActivitiesTotal struct {
 }
 
 func NewValidatorActivitiesTotal(rdbHandle *rdb.Handle) *ValidatorActivitiesTotal {
+	return NewValidatorActivitiesTotalWithTableName(rdbHandle, "view_validator_activities_total")
+}
+
+// NewValidatorActivitiesTotalWithTableName creates the view on a table other than
+// view_validator_activities_total, e.g. the table of a projection version being built
+func NewValidatorActivitiesTotalWithTableName(rdbHandle *rdb.Handle, tableName string) *ValidatorActivitiesTotal {
 	return &ValidatorActivitiesTotal{
-		view.NewTotal(rdbHandle, "view_validator_activities_total"),
+		view.NewTotal(rdbHandle, tableName),
 	}
 }
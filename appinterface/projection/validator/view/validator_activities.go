@@ -17,11 +17,21 @@ import (
 // BlockEvents projection view implemented by relational database
 type ValidatorActivities struct {
 	rdb *rdb.Handle
+
+	tableName string
 }
 
 func NewValidatorActivities(handle *rdb.Handle) *ValidatorActivities {
+	return NewValidatorActivitiesWithTableName(handle, "view_validator_activities")
+}
+
+// NewValidatorActivitiesWithTableName creates the view on a table other than view_validator_activities,
+// e.g. the table of a projection version being built
+func NewValidatorActivitiesWithTableName(handle *rdb.Handle, tableName string) *ValidatorActivities {
 	return &ValidatorActivities{
 		handle,
+
+		tableName,
 	}
 }
 
@@ -30,7 +40,7 @@ func (validatorActivitiesView *ValidatorActivities) Insert(validatorActivity *Va
 
 	var sql string
 	sql, _, err = validatorActivitiesView.rdb.StmtBuilder.Insert(
-		"view_validator_activities",
+		validatorActivitiesView.tableName,
 	).Columns(
 		"block_height",
 		"block_hash",
@@ -38,8 +48,9 @@ func (validatorActivitiesView *ValidatorActivities) Insert(validatorActivity *Va
 		"transaction_hash",
 		"operator_address",
 		"success",
+		"event_uuid",
 		"data",
-	).Values("?", "?", "?", "?", "?", "?", "?").ToSql()
+	).Values("?", "?", "?", "?", "?", "?", "?", "?").ToSql()
 	if err != nil {
 		return fmt.Errorf("error building valiator activity insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
 	}
@@ -51,6 +62,7 @@ func (validatorActivitiesView *ValidatorActivities) Insert(validatorActivity *Va
 		validatorActivity.MaybeTransactionHash,
 		validatorActivity.OperatorAddress,
 		validatorActivity.Success,
+		validatorActivity.MaybeEventUUID,
 		validatorActivity.Data,
 	)
 	if err != nil {
@@ -69,7 +81,7 @@ func (validatorActivitiesView *ValidatorActivities) InsertAll(validatorActivitie
 	}
 
 	stmtBuilder := validatorActivitiesView.rdb.StmtBuilder.Insert(
-		"view_validator_activities",
+		validatorActivitiesView.tableName,
 	).Columns(
 		"block_height",
 		"block_hash",
@@ -77,6 +89,7 @@ func (validatorActivitiesView *ValidatorActivities) InsertAll(validatorActivitie
 		"transaction_hash",
 		"operator_address",
 		"success",
+		"event_uuid",
 		"data",
 	)
 
@@ -88,6 +101,7 @@ func (validatorActivitiesView *ValidatorActivities) InsertAll(validatorActivitie
 			validatorActivity.MaybeTransactionHash,
 			validatorActivity.OperatorAddress,
 			validatorActivity.Success,
+			validatorActivity.MaybeEventUUID,
 			validatorActivity.Data,
 		)
 	}
@@ -120,9 +134,10 @@ func (validatorActivitiesView *ValidatorActivities) List(
 		"transaction_hash",
 		"operator_address",
 		"success",
+		"event_uuid",
 		"data",
 	).From(
-		"view_validator_activities",
+		validatorActivitiesView.tableName,
 	)
 
 	if order.MaybeBlockHeight == nil {
@@ -185,6 +200,7 @@ func (validatorActivitiesView *ValidatorActivities) List(
 			&validatorActivity.MaybeTransactionHash,
 			&validatorActivity.OperatorAddress,
 			&validatorActivity.Success,
+			&validatorActivity.MaybeEventUUID,
 			&validatorActivity.Data,
 		); scanErr != nil {
 			if errors.Is(scanErr, rdb.ErrNoRows) {
@@ -226,6 +242,7 @@ type ValidatorActivityRow struct {
 	MaybeTransactionHash *string                  `json:"transactionHash"`
 	OperatorAddress      string                   `json:"operatorAddress"`
 	Success              bool                     `json:"success"`
+	MaybeEventUUID       *string                  `json:"eventUuid"`
 	Data                 ValidatorActivityRowData `json:"activity"`
 }
 
@@ -21,11 +21,21 @@ import (
 
 type Validators struct {
 	rdb *rdb.Handle
+
+	tableName string
 }
 
 func NewValidators(handle *rdb.Handle) *Validators {
+	return NewValidatorsWithTableName(handle, "view_validators")
+}
+
+// NewValidatorsWithTableName creates the view on a table other than view_validators, e.g. the table
+// of a projection version being built
+func NewValidatorsWithTableName(handle *rdb.Handle, tableName string) *Validators {
 	return &Validators{
 		handle,
+
+		tableName,
 	}
 }
 
@@ -40,7 +50,7 @@ func (validatorsView *Validators) LastJoinedBlockHeight(
 	if sql, sqlArgs, err = validatorsView.rdb.StmtBuilder.Select(
 		"joined_at_block_height",
 	).From(
-		"view_validators",
+		validatorsView.tableName,
 	).Where(
 		"operator_address = ? AND consensus_node_address = ?", operatorAddress, consensusNodeAddress,
 	).ToSql(); err != nil {
@@ -64,7 +74,7 @@ func (validatorsView *Validators) Upsert(validator *ValidatorRow) error {
 		unbondingCompletionTime = validatorsView.rdb.Tton(validator.MaybeUnbondingCompletionTime)
 	}
 	sql, sqlArgs, err := validatorsView.rdb.StmtBuilder.Insert(
-		"view_validators",
+		validatorsView.tableName,
 	).Columns(
 		"operator_address",
 		"consensus_node_address",
@@ -140,7 +150,7 @@ func (validatorsView *Validators) Insert(validator *ValidatorRow) error {
 
 	var sql string
 	sql, _, err = validatorsView.rdb.StmtBuilder.Insert(
-		"view_validators",
+		validatorsView.tableName,
 	).Columns(
 		"operator_address",
 		"consensus_node_address",
@@ -205,7 +215,7 @@ func (validatorsView *Validators) Update(validator *ValidatorRow) error {
 		unbondingCompletionTime = validatorsView.rdb.Tton(validator.MaybeUnbondingCompletionTime)
 	}
 	sql, sqlArgs, err := validatorsView.rdb.StmtBuilder.Update(
-		"view_validators",
+		validatorsView.tableName,
 	).SetMap(map[string]interface{}{
 		"initial_delegator_address":  validator.InitialDelegatorAddress,
 		"status":                     validator.Status,
@@ -280,7 +290,7 @@ func (validatorsView *Validators) List(
 	cumulativePowerStmtBuilder := validatorsView.rdb.StmtBuilder.Select(
 		"power",
 	).From(
-		"view_validators",
+		validatorsView.tableName,
 	).Offset(0).Limit(
 		uint64(pagination.OffsetParams().Offset()),
 	)
@@ -352,7 +362,7 @@ func (validatorsView *Validators) List(
 		"commission_max_change_rate",
 		"min_self_delegation",
 	).From(
-		"view_validators",
+		validatorsView.tableName,
 	)
 	stmtBuilder = stmtBuilder.OrderBy(orderClauses...)
 
@@ -447,7 +457,7 @@ func (validatorsView *Validators) List(
 }
 
 func (validatorsView *Validators) totalPower() (*big.Float, error) {
-	sql, _, _ := validatorsView.rdb.StmtBuilder.Select("power").From("view_validators").ToSql()
+	sql, _, _ := validatorsView.rdb.StmtBuilder.Select("power").From(validatorsView.tableName).ToSql()
 	rowsResult, err := validatorsView.rdb.Query(sql)
 	if err != nil {
 		return nil, fmt.Errorf("error getting validators from table: %v", err)
@@ -493,7 +503,7 @@ func (validatorsView *Validators) Search(keyword string) ([]ValidatorRow, error)
 		"commission_max_change_rate",
 		"min_self_delegation",
 	).From(
-		"view_validators",
+		validatorsView.tableName,
 	).Where(
 		"operator_address = ? OR consensus_node_address = ? OR LOWER(moniker) LIKE ?",
 		keyword, keyword, fmt.Sprintf("%%%s%%", keyword),
@@ -576,7 +586,7 @@ func (validatorsView *Validators) FindBy(identity ValidatorIdentity) (*Validator
 		"commission_max_change_rate",
 		"min_self_delegation",
 	).From(
-		"view_validators",
+		validatorsView.tableName,
 	).OrderBy("id DESC")
 	if identity.MaybeConsensusNodeAddress != nil {
 		selectStmtBuilder = selectStmtBuilder.Where(
@@ -637,7 +647,7 @@ func (validatorsView *Validators) Count(filter CountFilter) (int64, error) {
 	stmt := validatorsView.rdb.StmtBuilder.Select(
 		"COUNT(*)",
 	).From(
-		"view_validators",
+		validatorsView.tableName,
 	)
 
 	if filter.MaybeStatus != nil {
@@ -0,0 +1,16 @@
+package validatorstats
+
+// migrations of the tables owned by the projection. The tables created by the global migrations before
+// they are owned by the projection are kept.
+var migrations = map[string]string{
+	"1_view_validator_stats.up.sql": `
+CREATE TABLE IF NOT EXISTS view_validator_stats (
+    metrics VARCHAR,
+    value VARCHAR,
+    PRIMARY KEY (metrics)
+);
+`,
+	"1_view_validator_stats.down.sql": `
+DROP TABLE IF EXISTS view_validator_stats;
+`,
+}
@@ -15,6 +15,7 @@ import (
 )
 
 var _ entity_projection.Projection = &ValidatorStats{}
+var _ entity_projection.Resettable = &ValidatorStats{}
 
 const TOTAL_REWARD = "total_reward"
 const TOTAL_DELEGATE = "total_delegate"
@@ -42,11 +43,18 @@ func (_ *ValidatorStats) GetEventsToListen() []string {
 		event_usecase.BLOCK_REWARDED,
 		event_usecase.MSG_DELEGATE_CREATED,
 		event_usecase.MSG_UNDELEGATE_CREATED,
+		event_usecase.VALIDATOR_BOOTSTRAPPED,
 	}
 }
 
+// OnInit implements projection.Projection and applies the migrations of the owned tables
 func (projection *ValidatorStats) OnInit() error {
-	return nil
+	return projection.MigrateOwnedTables(projection.rdbConn, migrations)
+}
+
+// Reset implements projection.Resettable and removes all projected rows
+func (projection *ValidatorStats) Reset() error {
+	return projection.ResetOwnedTables(projection.rdbConn, "view_validator_stats")
 }
 
 func (projection *ValidatorStats) HandleEvents(height int64, events []event_entity.Event) error {
@@ -113,6 +121,16 @@ func (projection *ValidatorStats) HandleEvents(height int64, events []event_enti
 			if err != nil {
 				return fmt.Errorf("error subtracting delegate: %v", err)
 			}
+		} else if validatorBootstrappedEvent, ok := event.(*event_usecase.ValidatorBootstrapped); ok {
+			// validator tokens are all the delegations to the validator at the bootstrap height
+			tokens, tokensErr := coin.NewCoinFromString(TrimDecimalPlaces(validatorBootstrappedEvent.Tokens))
+			if tokensErr != nil {
+				return fmt.Errorf("error parsing bootstrapped validator tokens: %v", tokensErr)
+			}
+			totalDelegate, err = totalDelegate.Add(tokens)
+			if err != nil {
+				return fmt.Errorf("error adding bootstrapped delegate: %v", err)
+			}
 		}
 	}
 
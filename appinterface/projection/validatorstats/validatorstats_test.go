@@ -36,6 +36,8 @@ var _ = Describe("Validator Events", func() {
 		BeforeEach(func() {
 			_ = pgMigrate.Reset()
 			pgMigrate.MustUp()
+			Expect(block.NewBlock(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
+			Expect(validatorstats.NewValidatorStats(NewFakeLogger(), pgConn).OnInit()).To(BeNil())
 		})
 
 		AfterEach(func() {
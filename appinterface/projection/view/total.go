@@ -72,6 +72,26 @@ func (view *Total) Increment(identity string, total int64) error {
 	return nil
 }
 
+// DeleteByHeightAfter deletes the totals with identity of a height, or a height prefixed key in the form
+// of `{height}:{key}`, where the height is after the provided height
+func (view *Total) DeleteByHeightAfter(height int64) error {
+	sql, sqlArgs, err := view.rdbHandle.StmtBuilder.Delete(
+		view.tableName,
+	).Where(
+		"CASE WHEN split_part(identity, ':', 1) ~ '^[0-9]+$' THEN split_part(identity, ':', 1)::BIGINT > ? ELSE FALSE END",
+		height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building total deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = view.rdbHandle.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting total: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
 func (view *Total) FindBy(identity string) (int64, error) {
 	sql, sqlArgs, err := view.rdbHandle.StmtBuilder.Select(
 		"total",
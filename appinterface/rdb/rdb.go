@@ -1,6 +1,10 @@
 package rdb
 
-import sq "github.com/Masterminds/squirrel"
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+)
 
 // Relational database interface
 
@@ -12,6 +16,21 @@ type Conn interface {
 	ToHandle() *Handle
 }
 
+// Listener is implemented by connections supporting asynchronous notifications
+type Listener interface {
+	// Listen sends the payload of every notification on channel to payloadCh until the context is
+	// cancelled. It returns an error when listening is interrupted.
+	Listen(ctx context.Context, channel string, payloadCh chan<- string) error
+}
+
+// Migrator is implemented by connections able to apply a set of migrations independently of the other
+// migrations of the database
+type Migrator interface {
+	// MigrateUp applies the migrations not applied yet. Migrations are keyed by file name in the format
+	// `<version>_<title>.<up|down>.sql`, and the applied version is kept in migrationsTable.
+	MigrateUp(migrations map[string]string, migrationsTable string) error
+}
+
 type Tx interface {
 	Exec(sql string, args ...interface{}) (ExecResult, error)
 	Query(sql string, args ...interface{}) (RowsResult, error)
@@ -0,0 +1,351 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+
+	"github.com/crypto-com/chain-indexing/internal/filereader/toml"
+	"github.com/urfave/cli/v2"
+)
+
+const SYSTEM_MODE_EVENT_STORE = "EVENT_STORE"
+const SYSTEM_MODE_TENDERMINT_DIRECT = "TENDERMINT_DIRECT"
+const SYSTEM_MODE_IN_MEMORY = "IN_MEMORY"
+
+const SYNC_STRATEGY_WINDOW = "WINDOW"
+const SYNC_STRATEGY_RESILIENT = "RESILIENT"
+
+const BLOCK_FEED_POLLING = "POLLING"
+const BLOCK_FEED_WEBSOCKET = "WEBSOCKET"
+
+// Run runs the command line application with the arguments, where the first argument is the program
+// name
+func (app *App) Run(args []string) error {
+	cliApp := &cli.App{
+		Name:                 filepath.Base(args[0]),
+		Usage:                "Crypto.com Chain Indexing Service",
+		Version:              "v0.0.1",
+		Copyright:            "(c) 2020 Crypto.com",
+		EnableBashCompletion: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "config",
+				Value: "./config/config.toml",
+				Usage: "TOML `FILE` to load configuration from",
+			},
+
+			&cli.StringFlag{
+				Name:  "logLevel,l",
+				Usage: "Log level (Allowed values: fatal,error,info,debug)",
+			},
+			&cli.BoolFlag{
+				Name:  "color",
+				Usage: "Display colored log",
+			},
+
+			&cli.BoolFlag{
+				Name:    "dbSSL",
+				Usage:   "Enable Postgres SSL mode",
+				EnvVars: []string{"DB_SSL"},
+			},
+			&cli.StringFlag{
+				Name:    "dbHost",
+				Usage:   "Postgres database hostname",
+				EnvVars: []string{"DB_HOST"},
+			},
+			&cli.UintFlag{
+				Name:    "dbPort",
+				Usage:   "Postgres database port",
+				EnvVars: []string{"DB_PORT"},
+			},
+			&cli.StringFlag{
+				Name:    "dbUsername",
+				Usage:   "Postgres username",
+				EnvVars: []string{"DB_USERNAME"},
+			},
+			// required by indexing service only, so that sub-commands can run without database
+			&cli.StringFlag{
+				Name:    "dbPassword",
+				Usage:   "Postgres password (Required)",
+				EnvVars: []string{"DB_PASSWORD"},
+			},
+			&cli.StringFlag{
+				Name:    "dbName",
+				Usage:   "Postgres database name",
+				EnvVars: []string{"DB_NAME"},
+			},
+			&cli.StringFlag{
+				Name:    "dbSchema",
+				Usage:   "Postgres schema name",
+				EnvVars: []string{"DB_SCHEMA"},
+			},
+
+			&cli.StringFlag{
+				Name:    "tendermintURL",
+				Usage:   "Tendermint HTTP RPC URL",
+				EnvVars: []string{"TENDERMINT_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "cosmosAppURL",
+				Usage:   " Cosmos App RPC URL",
+				EnvVars: []string{"COSMOSAPP_URL"},
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if args := ctx.Args(); args.Len() > 0 {
+				return fmt.Errorf("Unexpected arguments: %q", args.Get(0))
+			}
+
+			config, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+
+			// database is not used in in-memory mode
+			if config.System.Mode != SYSTEM_MODE_IN_MEMORY && !ctx.IsSet("dbPassword") {
+				return errors.New("Required flag \"dbPassword\" not set")
+			}
+
+			// Create logger
+			logLevel := parseLogLevel(config.Logger.Level)
+			logger := infrastructure.NewZerologLogger(os.Stdout)
+			logger.SetLogLevel(logLevel)
+
+			// Setup system
+			if config.System.Mode != SYSTEM_MODE_EVENT_STORE &&
+				config.System.Mode != SYSTEM_MODE_TENDERMINT_DIRECT &&
+				config.System.Mode != SYSTEM_MODE_IN_MEMORY {
+				logger.Panicf("unrecognized system mode: %s", config.System.Mode)
+			}
+			if len(config.Tendermint.AllHTTPRPCURLs()) == 0 && config.Tendermint.ArchivePath == "" {
+				logger.Panicf("missing tendermint http_rpc_url")
+			}
+			if config.Sync.Strategy == "" {
+				config.Sync.Strategy = SYNC_STRATEGY_WINDOW
+			}
+			if config.Sync.Strategy != SYNC_STRATEGY_WINDOW && config.Sync.Strategy != SYNC_STRATEGY_RESILIENT {
+				logger.Panicf("unrecognized sync strategy: %s", config.Sync.Strategy)
+			}
+			if config.Tendermint.BlockFeed == "" {
+				config.Tendermint.BlockFeed = BLOCK_FEED_POLLING
+			}
+			if config.Tendermint.BlockFeed != BLOCK_FEED_POLLING && config.Tendermint.BlockFeed != BLOCK_FEED_WEBSOCKET {
+				logger.Panicf("unrecognized tendermint block feed: %s", config.Tendermint.BlockFeed)
+			}
+
+			if err = verifyEndpointsChainID(config); err != nil {
+				logger.Panicf("error verifying endpoints: %v", err)
+			}
+
+			if config.System.Mode == SYSTEM_MODE_IN_MEMORY {
+				return app.runInMemoryIndexService(logger, config)
+			}
+
+			rdbConn, err := SetupRDbConn(config, logger)
+			if err != nil {
+				logger.Panicf("error setting up RDb connection: %v", err)
+			}
+
+			projections, err := app.initProjections(logger, rdbConn, config)
+			if err != nil {
+				logger.Panicf("error setting up projections: %v", err)
+			}
+			// version builders create their tables after the live tables, which are migrated on init
+			projections = onInitProjections(logger, projections)
+			projections, err = appendVersionBuilders(logger, projections)
+			if err != nil {
+				logger.Panicf("error setting up projections: %v", err)
+			}
+
+			// any of the services stops the others when it fails
+			shutdownCtx, shutdown := newShutdownContext(logger)
+			var wg sync.WaitGroup
+
+			httpAPIServer := app.newHTTPAPIServer(logger, rdbConn, config)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if runErr := httpAPIServer.Run(shutdownCtx); runErr != nil {
+					logger.Errorf("%v", runErr)
+					shutdown()
+				}
+			}()
+
+			indexService := NewIndexService(logger, rdbConn, config, app.newEventRegistry(), projections)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if runErr := indexService.Run(shutdownCtx); runErr != nil {
+					logger.Errorf("%v", runErr)
+					shutdown()
+				}
+			}()
+
+			wg.Wait()
+			if closeErr := rdbConn.Close(); closeErr != nil {
+				logger.Errorf("error closing RDb connection: %v", closeErr)
+			}
+			logger.Info("shutdown completed")
+
+			return nil
+		},
+		Commands: []*cli.Command{
+			newDumpCommand(),
+			app.newSyncCommand(),
+			app.newVerifyCommand(),
+			app.newEventsCommand(),
+			app.newProjectionCommand(),
+			app.newMigrateCommand(),
+		},
+	}
+
+	err := cliApp.Run(args)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// verifyEndpointsChainID checks that the configured Tendermint and Cosmos app endpoints serve the same
+// chain, which is the configured chain id when it is set
+func verifyEndpointsChainID(config *Config) error {
+	if config.Tendermint.ArchivePath == "" {
+		tendermintClient := tendermint.NewMultiEndpointHTTPClient(
+			config.Tendermint.AllHTTPRPCURLs(),
+		).WithChainID(config.Blockchain.ChainID)
+		if err := tendermintClient.VerifyChainID(); err != nil {
+			return fmt.Errorf("error verifying tendermint endpoints: %v", err)
+		}
+	}
+
+	if cosmosAppHTTPRPCURLs := config.CosmosApp.AllHTTPRPCURLs(); len(cosmosAppHTTPRPCURLs) > 0 {
+		cosmosAppClient := cosmosapp_infrastructure.NewMultiEndpointHTTPClient(
+			cosmosAppHTTPRPCURLs,
+		).WithChainID(config.Blockchain.ChainID)
+		if err := cosmosAppClient.VerifyChainID(); err != nil {
+			return fmt.Errorf("error verifying Cosmos app endpoints: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// runInMemoryIndexService runs the index service without database until shutdown. Only the in-memory
+// projections are run, the other projections and HTTP API require database.
+func (app *App) runInMemoryIndexService(logger applogger.Logger, config *Config) error {
+	logger.Info("running in-memory mode, indexed events are lost on exit and HTTP API is not served")
+
+	projections, err := app.initInMemoryProjections(logger, config)
+	if err != nil {
+		return fmt.Errorf("error initializing in-memory projections: %v", err)
+	}
+	projections = onInitProjections(logger, projections)
+
+	shutdownCtx, shutdown := newShutdownContext(logger)
+	defer shutdown()
+
+	indexService := NewIndexService(logger, nil, config, app.newEventRegistry(), projections)
+	if err := indexService.Run(shutdownCtx); err != nil {
+		return err
+	}
+
+	logger.Info("shutdown completed")
+	return nil
+}
+
+// newShutdownContext returns a context which is cancelled on SIGINT or SIGTERM, or when the returned
+// cancel function is called. A second signal exits immediately.
+func newShutdownContext(logger applogger.Logger) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signalCh := make(chan os.Signal, 2)
+	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signalCh:
+			logger.Infof("received %s, shutting down gracefully", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+
+		sig := <-signalCh
+		logger.Errorf("received %s again, exiting immediately", sig)
+		os.Exit(1)
+	}()
+
+	return ctx, cancel
+}
+
+// loadConfig reads the config file and overrides it with the CLI flags
+func loadConfig(ctx *cli.Context) (*Config, error) {
+	// Prepare FileConfig
+	configPath := ctx.String("config")
+	configReader, configFileErr := toml.FromFile(configPath)
+	if configFileErr != nil {
+		return nil, configFileErr
+	}
+	var fileConfig FileConfig
+	readConfigErr := configReader.Read(&fileConfig)
+	if readConfigErr != nil {
+		return nil, readConfigErr
+	}
+
+	cliConfig := CLIConfig{
+		LogLevel: ctx.String("logLevel"),
+
+		DatabaseHost:     ctx.String("dbHost"),
+		DatabaseUsername: ctx.String("dbUsername"),
+		DatabasePassword: ctx.String("dbPassword"),
+		DatabaseName:     ctx.String("dbName"),
+		DatabaseSchema:   ctx.String("dbSchema"),
+
+		TendermintHTTPRPCURL: ctx.String("tendermintURL"),
+		CosmosHTTPRPCURL:     ctx.String("cosmosAppURL"),
+	}
+	if ctx.IsSet("color") {
+		cliConfig.LoggerColor = primptr.Bool(ctx.Bool("color"))
+	}
+	if ctx.IsSet("dbSSL") {
+		cliConfig.DatabaseSSL = primptr.Bool(ctx.Bool("dbSSL"))
+	}
+	if ctx.IsSet("dgPort") {
+		cliConfig.DatabasePort = primptr.Int32(int32(ctx.Int("dbPort")))
+	}
+
+	config := Config{
+		fileConfig,
+	}
+	config.OverrideByCLIConfig(&cliConfig)
+
+	return &config, nil
+}
+
+func parseLogLevel(level string) applogger.LogLevel {
+	switch level {
+	case "panic":
+		return applogger.LOG_LEVEL_PANIC
+	case "error":
+		return applogger.LOG_LEVEL_ERROR
+	case "info":
+		return applogger.LOG_LEVEL_INFO
+	case "debug":
+		return applogger.LOG_LEVEL_DEBUG
+	default:
+		panic("Unsupported log level: " + level)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package bootstrap
 
 type Config struct {
 	FileConfig
@@ -31,11 +31,14 @@ func (config *Config) OverrideByCLIConfig(cliConfig *CLIConfig) {
 	if cliConfig.DatabaseSchema != "" {
 		config.Database.Schema = cliConfig.DatabaseSchema
 	}
+	// CLI endpoint replaces all endpoints in config file
 	if cliConfig.TendermintHTTPRPCURL != "" {
 		config.Tendermint.HTTPRPCURL = cliConfig.TendermintHTTPRPCURL
+		config.Tendermint.HTTPRPCURLs = nil
 	}
 	if cliConfig.CosmosHTTPRPCURL != "" {
 		config.CosmosApp.HTTPRPCUL = cliConfig.CosmosHTTPRPCURL
+		config.CosmosApp.HTTPRPCURLs = nil
 	}
 }
 
@@ -60,6 +63,8 @@ type FileConfig struct {
 	Blockchain BlockchainConfig
 	System     SystemConfig
 	Sync       SyncConfig
+	EventStore EventStoreConfig `toml:"event_store"`
+	Projection ProjectionConfig `toml:"projection"`
 	Tendermint TendermintConfig
 	CosmosApp  CosmosAppConfig `toml:"cosmosapp"`
 	HTTP       HTTPConfig
@@ -69,6 +74,9 @@ type FileConfig struct {
 }
 
 type BlockchainConfig struct {
+	// ChainID is optional, every endpoint has to report it when set. Otherwise the chain id reported by the
+	// majority of the reachable endpoints is followed.
+	ChainID   string `toml:"chain_id"`
 	CRODenom  string `toml:"cro_denom"`
 	BaseDenom string `toml:"base_denom"`
 
@@ -85,7 +93,26 @@ type SystemConfig struct {
 }
 
 type SyncConfig struct {
-	WindowSize int `toml:"window_size"`
+	WindowSize          int    `toml:"window_size"`
+	Strategy            string `toml:"strategy"`
+	MaxInFlight         int    `toml:"max_in_flight"`
+	MaxRetries          int    `toml:"max_retries"`
+	RetryInitialBackoff string `toml:"retry_initial_backoff"`
+	RetryMaxBackoff     string `toml:"retry_max_backoff"`
+	StartHeight         int64  `toml:"start_height"`
+	MaxReorgDepth       int64  `toml:"max_reorg_depth"`
+}
+
+type EventStoreConfig struct {
+	RawBlockRetentionDepth int64 `toml:"raw_block_retention_depth"`
+}
+
+type ProjectionConfig struct {
+	MaxHandleRetries int `toml:"max_handle_retries"`
+	// Enables are the Ids of the projections to run, all the built-in projections run when empty
+	Enables []string `toml:"enables"`
+	// Options are the settings of each projection keyed by projection Id
+	Options map[string]map[string]interface{} `toml:"options"`
 }
 
 type HTTPConfig struct {
@@ -97,11 +124,50 @@ type HTTPConfig struct {
 }
 
 type TendermintConfig struct {
-	HTTPRPCURL string `toml:"http_rpc_url"`
+	HTTPRPCURL   string   `toml:"http_rpc_url"`
+	HTTPRPCURLs  []string `toml:"http_rpc_urls"`
+	BlockFeed    string   `toml:"block_feed"`
+	WebSocketURL string   `toml:"websocket_url"`
+	ArchivePath  string   `toml:"archive_path"`
+	CacheDir     string   `toml:"cache_dir"`
+	CacheMaxSize int64    `toml:"cache_max_size_mb"`
+}
+
+// AllHTTPRPCURLs returns the configured Tendermint endpoints
+func (config *TendermintConfig) AllHTTPRPCURLs() []string {
+	return mergeURLs(config.HTTPRPCURL, config.HTTPRPCURLs)
 }
 
 type CosmosAppConfig struct {
-	HTTPRPCUL string `toml:"http_rpc_url"`
+	HTTPRPCUL   string   `toml:"http_rpc_url"`
+	HTTPRPCURLs []string `toml:"http_rpc_urls"`
+}
+
+// AllHTTPRPCURLs returns the configured Cosmos app endpoints
+func (config *CosmosAppConfig) AllHTTPRPCURLs() []string {
+	return mergeURLs(config.HTTPRPCUL, config.HTTPRPCURLs)
+}
+
+// mergeURLs returns the single URL followed by the URL list, without duplicates
+func mergeURLs(url string, urls []string) []string {
+	merged := make([]string, 0, len(urls)+1)
+	if url != "" {
+		merged = append(merged, url)
+	}
+	for _, candidate := range urls {
+		isDuplicated := false
+		for _, existing := range merged {
+			if existing == candidate {
+				isDuplicated = true
+				break
+			}
+		}
+		if !isDuplicated {
+			merged = append(merged, candidate)
+		}
+	}
+
+	return merged
 }
 
 type DatabaseConfig struct {
@@ -0,0 +1,267 @@
+package bootstrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+const DEFAULT_DUMP_CONCURRENCY = 10
+const DUMP_PROGRESS_LOG_INTERVAL = 1000
+
+func newDumpCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "dump",
+		Usage: "Dump block and block_results of a height range from Tendermint to an archive for offline replay",
+		Flags: []cli.Flag{
+			&cli.Int64Flag{
+				Name:  "from",
+				Usage: "First block height to dump. Height 0 dumps the genesis",
+				Value: 0,
+			},
+			&cli.Int64Flag{
+				Name:  "to",
+				Usage: "Last block height to dump, default to the latest block height",
+			},
+			&cli.StringFlag{
+				Name:     "output",
+				Usage:    "Archive directory, or a zip `FILE` when it ends with .zip",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "compress",
+				Usage: "Gzip every file in a directory archive",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Number of concurrent requests to Tendermint",
+				Value: DEFAULT_DUMP_CONCURRENCY,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			config, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger := infrastructure.NewZerologLogger(os.Stdout)
+			logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+			tendermintRPCUrls := config.Tendermint.AllHTTPRPCURLs()
+			if len(tendermintRPCUrls) == 0 {
+				return errors.New("missing tendermint http_rpc_url")
+			}
+
+			dumper := &Dumper{
+				logger: logger.WithFields(applogger.LogFields{
+					"module": "Dumper",
+				}),
+				client:      tendermint.NewMultiEndpointHTTPClient(tendermintRPCUrls),
+				concurrency: ctx.Int("concurrency"),
+			}
+			var toHeight *int64
+			if ctx.IsSet("to") {
+				to := ctx.Int64("to")
+				toHeight = &to
+			}
+
+			return dumper.Dump(ctx.String("output"), ctx.Bool("compress"), ctx.Int64("from"), toHeight)
+		},
+	}
+}
+
+// Dumper writes the raw Tendermint responses of a height range to an archive, which can be replayed
+// by tendermint.FileClient
+type Dumper struct {
+	logger      applogger.Logger
+	client      *tendermint.HTTPClient
+	concurrency int
+}
+
+// Dump dumps blocks from `fromHeight` to `toHeight` inclusive, or to the latest block height when
+// `toHeight` is nil. An existing directory archive is extended when the range is contiguous with it.
+func (dumper *Dumper) Dump(outputPath string, compress bool, fromHeight int64, toHeight *int64) error {
+	if fromHeight < 0 {
+		return fmt.Errorf("invalid from height: %d", fromHeight)
+	}
+
+	chainID, err := dumper.client.ChainID()
+	if err != nil {
+		return fmt.Errorf("error getting chain id: %v", err)
+	}
+	if toHeight == nil {
+		latestHeight, latestHeightErr := dumper.client.LatestBlockHeight()
+		if latestHeightErr != nil {
+			return fmt.Errorf("error getting latest block height: %v", latestHeightErr)
+		}
+		toHeight = &latestHeight
+	}
+	if *toHeight < fromHeight {
+		return fmt.Errorf("to height %d is less than from height %d", *toHeight, fromHeight)
+	}
+
+	writer, err := tendermint.NewArchiveWriter(outputPath, compress)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	manifest, err := dumper.mergeManifest(writer, chainID, fromHeight, *toHeight)
+	if err != nil {
+		return err
+	}
+
+	dumper.logger.Infof("dumping chain %s blocks %d-%d to %s", chainID, fromHeight, *toHeight, outputPath)
+	if err = dumper.dumpHeights(writer, fromHeight, *toHeight); err != nil {
+		return err
+	}
+
+	if err = writer.WriteManifest(manifest); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	dumper.logger.Infof("archive now contains blocks %d-%d", manifest.FromHeight, manifest.ToHeight)
+	return nil
+}
+
+// mergeManifest returns the manifest of the archive after dumping the height range
+func (dumper *Dumper) mergeManifest(
+	writer *tendermint.ArchiveWriter, chainID string, fromHeight int64, toHeight int64,
+) (*tendermint.ArchiveManifest, error) {
+	manifest := &tendermint.ArchiveManifest{
+		Version:    tendermint.ARCHIVE_VERSION,
+		ChainID:    chainID,
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+
+	existingManifest, err := writer.ReadManifest()
+	if err != nil {
+		return nil, fmt.Errorf("error reading existing archive manifest: %v", err)
+	}
+	if existingManifest == nil {
+		return manifest, nil
+	}
+
+	if existingManifest.ChainID != chainID {
+		return nil, fmt.Errorf(
+			"existing archive is of chain %s but Tendermint reports chain %s", existingManifest.ChainID, chainID,
+		)
+	}
+	// archive must not have gaps because heights are replayed one by one
+	if fromHeight > existingManifest.ToHeight+1 || toHeight < existingManifest.FromHeight-1 {
+		return nil, fmt.Errorf(
+			"height range %d-%d is not contiguous with existing archive %d-%d",
+			fromHeight, toHeight, existingManifest.FromHeight, existingManifest.ToHeight,
+		)
+	}
+	if existingManifest.FromHeight < manifest.FromHeight {
+		manifest.FromHeight = existingManifest.FromHeight
+	}
+	if existingManifest.ToHeight > manifest.ToHeight {
+		manifest.ToHeight = existingManifest.ToHeight
+	}
+
+	return manifest, nil
+}
+
+func (dumper *Dumper) dumpHeights(writer *tendermint.ArchiveWriter, fromHeight int64, toHeight int64) error {
+	concurrency := dumper.concurrency
+	if concurrency <= 0 {
+		concurrency = DEFAULT_DUMP_CONCURRENCY
+	}
+
+	heightCh := make(chan int64)
+	errCh := make(chan error, concurrency)
+	doneCh := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := range heightCh {
+				if err := dumper.dumpHeight(writer, height); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var dumpErr error
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	for height := fromHeight; height <= toHeight && dumpErr == nil; height += 1 {
+		select {
+		case heightCh <- height:
+			if height%DUMP_PROGRESS_LOG_INTERVAL == 0 {
+				dumper.logger.Infof("dumping block %d", height)
+			}
+		case dumpErr = <-errCh:
+		}
+	}
+	close(heightCh)
+	<-doneCh
+
+	if dumpErr != nil {
+		return dumpErr
+	}
+	select {
+	case err := <-errCh:
+		return err
+	default:
+		return nil
+	}
+}
+
+func (dumper *Dumper) dumpHeight(writer *tendermint.ArchiveWriter, height int64) error {
+	if height == 0 {
+		return dumper.dumpMethod(writer, tendermint.ARCHIVE_GENESIS_FILE, "genesis")
+	}
+
+	heightQuery := "height=" + strconv.FormatInt(height, 10)
+	if err := dumper.dumpMethod(writer, tendermint.ArchiveBlockFile(height), "block", heightQuery); err != nil {
+		return fmt.Errorf("error dumping block %d: %v", height, err)
+	}
+	if err := dumper.dumpMethod(
+		writer, tendermint.ArchiveBlockResultsFile(height), "block_results", heightQuery,
+	); err != nil {
+		return fmt.Errorf("error dumping block_results %d: %v", height, err)
+	}
+
+	return nil
+}
+
+func (dumper *Dumper) dumpMethod(
+	writer *tendermint.ArchiveWriter, name string, method string, queryString ...string,
+) error {
+	rawRespBody, err := dumper.client.RequestRaw(method, queryString...)
+	if err != nil {
+		return err
+	}
+	defer rawRespBody.Close()
+
+	// read the whole response first so that a zip archive is not locked while waiting for the network
+	rawResp, err := ioutil.ReadAll(rawRespBody)
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %v", method, err)
+	}
+
+	return writer.WriteFile(name, bytes.NewReader(rawResp))
+}
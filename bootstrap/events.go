@@ -0,0 +1,273 @@
+package bootstrap
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+// STDIO_PATH reads from stdin or writes to stdout in place of a file
+const STDIO_PATH = "-"
+
+func (app *App) newEventsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "events",
+		Usage: "Export, import and verify the event store",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "export",
+				Usage: "Export the events of a height range as newline-delimited JSON",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:  "from",
+						Usage: "First event height to export",
+						Value: 0,
+					},
+					&cli.Int64Flag{
+						Name:  "to",
+						Usage: "Last event height to export, default to the latest event height",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "NDJSON `FILE` to write to, or - for stdout",
+						Value: STDIO_PATH,
+					},
+					&cli.BoolFlag{
+						Name:  "gzip",
+						Usage: "Gzip the output",
+					},
+				},
+				Action: app.exportEvents,
+			},
+			{
+				Name:  "import",
+				Usage: "Import newline-delimited JSON events, skipping the events already stored",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "input",
+						Usage: "NDJSON `FILE` to read from, or - for stdin. Gzipped input is detected automatically",
+						Value: STDIO_PATH,
+					},
+					&cli.IntFlag{
+						Name:  "batchSize",
+						Usage: "Maximum number of events of a height inserted at a time",
+						Value: event_interface.DEFAULT_IMPORT_BATCH_SIZE,
+					},
+				},
+				Action: app.importEvents,
+			},
+			{
+				Name:  "verify",
+				Usage: "Verify the stored events of a height range against the chained event digests",
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:  "from",
+						Usage: "First event height to verify. The digest of the height before is trusted",
+						Value: 0,
+					},
+					&cli.Int64Flag{
+						Name:  "to",
+						Usage: "Last event height to verify, default to the latest event height",
+					},
+				},
+				Action: app.verifyEventDigests,
+			},
+		},
+	}
+}
+
+func (app *App) exportEvents(ctx *cli.Context) error {
+	outputPath := ctx.String("output")
+	logger, rdbConn, eventStore, err := app.setupEventStore(ctx, outputPath == STDIO_PATH)
+	if err != nil {
+		return err
+	}
+	defer closeRDbConn(logger, rdbConn)
+
+	fromHeight, toHeight, err := parseEventHeightRange(ctx, eventStore)
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = os.Stdout
+	if outputPath != STDIO_PATH {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %v", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	var gzipWriter *gzip.Writer
+	if ctx.Bool("gzip") {
+		gzipWriter = gzip.NewWriter(writer)
+		writer = gzipWriter
+	}
+
+	logger.Infof("exporting events %d-%d", fromHeight, toHeight)
+	count, err := eventStore.ExportNDJSON(writer, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	if gzipWriter != nil {
+		if err = gzipWriter.Close(); err != nil {
+			return fmt.Errorf("error writing gzip output: %v", err)
+		}
+	}
+
+	logger.Infof("exported %d events", count)
+	return nil
+}
+
+func (app *App) importEvents(ctx *cli.Context) error {
+	logger, rdbConn, eventStore, err := app.setupEventStore(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer closeRDbConn(logger, rdbConn)
+
+	var reader io.Reader = os.Stdin
+	if inputPath := ctx.String("input"); inputPath != STDIO_PATH {
+		file, err := os.Open(inputPath)
+		if err != nil {
+			return fmt.Errorf("error opening input file: %v", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+	if reader, err = maybeGunzip(reader); err != nil {
+		return err
+	}
+
+	count, err := eventStore.ImportNDJSON(rdbConn, reader, ctx.Int("batchSize"))
+	if err != nil {
+		return fmt.Errorf("error importing events after %d events are imported: %v", count, err)
+	}
+
+	logger.Infof("imported %d events", count)
+	return nil
+}
+
+func (app *App) verifyEventDigests(ctx *cli.Context) error {
+	logger, rdbConn, eventStore, err := app.setupEventStore(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer closeRDbConn(logger, rdbConn)
+
+	fromHeight, toHeight, err := parseEventHeightRange(ctx, eventStore)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("verifying event digests %d-%d", fromHeight, toHeight)
+	mismatches, err := eventStore.VerifyDigests(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error verifying event digests: %v", err)
+	}
+	if len(mismatches) == 0 {
+		logger.Infof("event digests %d-%d are verified", fromHeight, toHeight)
+		return nil
+	}
+
+	mismatchedHeights := make([]int64, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		mismatchedHeights = append(mismatchedHeights, mismatch.Height)
+		if mismatch.MaybeStoredDigest == nil {
+			logger.Errorf("missing event digest at height %d", mismatch.Height)
+		} else {
+			logger.Errorf(
+				"mismatched event digest at height %d: stored %s, computed %s",
+				mismatch.Height, *mismatch.MaybeStoredDigest, mismatch.ComputedDigest,
+			)
+		}
+	}
+	return fmt.Errorf("event digests do not match at heights %v", mismatchedHeights)
+}
+
+// parseEventHeightRange returns the height range from the `from` and `to` flags. It defaults to the
+// latest event height when `to` is not set.
+func parseEventHeightRange(ctx *cli.Context, eventStore *event_interface.RDbStore) (int64, int64, error) {
+	fromHeight := ctx.Int64("from")
+	var toHeight int64
+	if ctx.IsSet("to") {
+		toHeight = ctx.Int64("to")
+	} else {
+		maybeLatestHeight, err := eventStore.GetLatestHeight()
+		if err != nil {
+			return 0, 0, fmt.Errorf("error getting latest event height: %v", err)
+		}
+		if maybeLatestHeight == nil {
+			return 0, 0, errors.New("event store is empty")
+		}
+		toHeight = *maybeLatestHeight
+	}
+	if fromHeight < 0 || fromHeight > toHeight {
+		return 0, 0, fmt.Errorf("invalid height range %d-%d", fromHeight, toHeight)
+	}
+
+	return fromHeight, toHeight, nil
+}
+
+// maybeGunzip returns a gzip reader when the input starts with the gzip header, the input as is otherwise
+func maybeGunzip(reader io.Reader) (io.Reader, error) {
+	bufferedReader := bufio.NewReader(reader)
+	header, err := bufferedReader.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error reading input: %v", err)
+	}
+	if len(header) < 2 || header[0] != 0x1f || header[1] != 0x8b {
+		return bufferedReader, nil
+	}
+
+	gzipReader, err := gzip.NewReader(bufferedReader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading gzip input: %v", err)
+	}
+	return gzipReader, nil
+}
+
+// setupEventStore connects to the database of the event store. Logs are written to stderr when logToStderr
+// is true, so that they do not mix with the output.
+func (app *App) setupEventStore(
+	ctx *cli.Context, logToStderr bool,
+) (applogger.Logger, *pg.PgxConn, *event_interface.RDbStore, error) {
+	if !ctx.IsSet("dbPassword") {
+		return nil, nil, nil, errors.New("Required flag \"dbPassword\" not set")
+	}
+
+	config, err := loadConfig(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	logOutput := os.Stdout
+	if logToStderr {
+		logOutput = os.Stderr
+	}
+	logger := infrastructure.NewZerologLogger(logOutput)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	rdbConn, err := SetupRDbConn(config, logger)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error setting up RDb connection: %v", err)
+	}
+
+	return logger, rdbConn, event_interface.NewRDbStore(rdbConn.ToHandle(), app.newEventRegistry()), nil
+}
+
+func closeRDbConn(logger applogger.Logger, rdbConn *pg.PgxConn) {
+	if err := rdbConn.Close(); err != nil {
+		logger.Errorf("error closing RDb connection: %v", err)
+	}
+}
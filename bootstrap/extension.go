@@ -0,0 +1,133 @@
+package bootstrap
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+// App is the chain indexing application. Downstream modules embed the indexer as a library by
+// registering their own projections, events, HTTP routes and migrations before running it.
+type App struct {
+	extraProjections         []registeredProjection
+	extraInMemoryProjections []registeredProjection
+	eventRegistrars          []EventRegistrar
+	routeRegistrars          []RouteRegistrar
+	migrationSources         []MigrationSource
+}
+
+func NewApp() *App {
+	return &App{
+		extraProjections:         make([]registeredProjection, 0),
+		extraInMemoryProjections: make([]registeredProjection, 0),
+		eventRegistrars:          make([]EventRegistrar, 0),
+		routeRegistrars:          make([]RouteRegistrar, 0),
+		migrationSources:         make([]MigrationSource, 0),
+	}
+}
+
+// EventRegistrar registers extra events to the registry decoding the events in the event store
+type EventRegistrar = func(registry *event.Registry)
+
+// RouteRegistrar mounts extra endpoints on the HTTP API server. The route prefix is empty when the API
+// is served from root.
+type RouteRegistrar = func(server *httpapi.Server, routePrefix string, params RouteParams)
+
+// RouteParams are the dependencies to serve extra endpoints
+type RouteParams struct {
+	Logger    applogger.Logger
+	RDbHandle *rdb.Handle
+	Config    *Config
+}
+
+// MigrationSource is a folder of database migrations owned by an extension
+type MigrationSource struct {
+	// Name identifies the source. Its applied version is kept in table `schema_migrations_<name>`, so
+	// that it is versioned independently of the built-in migrations.
+	Name string
+	// Folder is the path of the folder containing the migration files
+	Folder string
+}
+
+var migrationSourceNamePattern = regexp.MustCompile("^[a-z][a-z0-9_]*$")
+
+// RegisterProjection registers an extra projection. It can be enabled by Id in config like the built-in
+// projections, and runs after them.
+func (app *App) RegisterProjection(projectionId string, constructor ProjectionConstructor) error {
+	if app.isProjectionIdRegistered(projectionId) {
+		return fmt.Errorf("projection `%s` already registered", projectionId)
+	}
+	app.extraProjections = append(app.extraProjections, registeredProjection{
+		id:          projectionId,
+		constructor: constructor,
+	})
+	return nil
+}
+
+// RegisterInMemoryProjection registers an extra projection keeping its states in memory, see
+// inmemoryprojectionbase. It runs in in-memory mode, where the database is not available and
+// ProjectionParams.RDbConn is nil.
+func (app *App) RegisterInMemoryProjection(projectionId string, constructor ProjectionConstructor) error {
+	if app.isProjectionIdRegistered(projectionId) {
+		return fmt.Errorf("projection `%s` already registered", projectionId)
+	}
+	app.extraInMemoryProjections = append(app.extraInMemoryProjections, registeredProjection{
+		id:          projectionId,
+		constructor: constructor,
+	})
+	return nil
+}
+
+func (app *App) isProjectionIdRegistered(projectionId string) bool {
+	return app.findRegisteredProjection(projectionId) != nil ||
+		findProjectionIn(app.inMemoryProjectionRegistry(), projectionId) != nil
+}
+
+// RegisterEvents registers extra events, so that they can be read from the event store
+func (app *App) RegisterEvents(registrar EventRegistrar) {
+	app.eventRegistrars = append(app.eventRegistrars, registrar)
+}
+
+// RegisterRoutes registers extra endpoints, which are mounted after the built-in ones
+func (app *App) RegisterRoutes(registrar RouteRegistrar) {
+	app.routeRegistrars = append(app.routeRegistrars, registrar)
+}
+
+// RegisterMigrations registers a folder of extra migrations, which are applied by the `migrate`
+// command after the built-in migrations. The name must be lower case alphanumeric or underscore.
+func (app *App) RegisterMigrations(name string, folder string) error {
+	if !migrationSourceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid migration source name `%s`", name)
+	}
+	for _, source := range app.migrationSources {
+		if source.Name == name {
+			return fmt.Errorf("migration source `%s` already registered", name)
+		}
+	}
+	app.migrationSources = append(app.migrationSources, MigrationSource{
+		Name:   name,
+		Folder: folder,
+	})
+	return nil
+}
+
+// newEventRegistry returns the registry of the built-in and the extra events
+func (app *App) newEventRegistry() *event.Registry {
+	registry := event.NewRegistry()
+	event_usecase.RegisterEvents(registry)
+	for _, registrar := range app.eventRegistrars {
+		registrar(registry)
+	}
+	return registry
+}
+
+// newHTTPAPIServer returns the HTTP API server mounting the endpoints of the enabled projections and
+// the extra endpoints
+func (app *App) newHTTPAPIServer(logger applogger.Logger, rdbConn rdb.Conn, config *Config) *HTTPAPIServer {
+	return NewHTTPAPIServer(logger, rdbConn, config, app.enabledProjectionIds(config), app.routeRegistrars)
+}
@@ -0,0 +1,133 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+
+	rdb_test "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	event_test "github.com/crypto-com/chain-indexing/entity/event/test"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	projection_test "github.com/crypto-com/chain-indexing/entity/projection/test"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+var _ = Describe("App extension", func() {
+	var app *App
+	var anyExtraEvent *event_test.FakeEvent
+	var decodedPayload []byte
+
+	BeforeEach(func() {
+		app = NewApp()
+
+		Expect(app.RegisterProjection(
+			"AnyExtraProjection",
+			func(params ProjectionParams) (projection_entity.Projection, error) {
+				mockProjection := projection_test.NewMockProjection()
+				mockProjection.On("Id").Return("AnyExtraProjection")
+				return mockProjection, nil
+			},
+		)).To(Succeed())
+
+		anyExtraEvent = event_test.NewFakeEvent()
+		decodedPayload = nil
+		app.RegisterEvents(func(registry *entity_event.Registry) {
+			registry.Register("AnyExtraEvent", 1, func(encoded []byte) (entity_event.Event, error) {
+				decodedPayload = encoded
+				return anyExtraEvent, nil
+			})
+		})
+
+		app.RegisterRoutes(func(server *httpapi.Server, routePrefix string, params RouteParams) {
+			server.GET(fmt.Sprintf("%s/api/v1/any-extra", routePrefix), func(ctx *fasthttp.RequestCtx) {
+				ctx.SetStatusCode(fasthttp.StatusOK)
+				ctx.SetBodyString(params.Config.Blockchain.BaseDenom)
+			})
+		})
+	})
+
+	It("should run the registered projection after the built-in projections when it is enabled", func() {
+		config := &Config{}
+		config.Projection.Enables = []string{"AnyExtraProjection", "Block"}
+
+		projections, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(BeNil())
+		Expect(projectionIds(projections)).To(Equal([]string{"Block", "AnyExtraProjection"}))
+	})
+
+	It("should decode the registered events along with the built-in events", func() {
+		registry := app.newEventRegistry()
+		Expect(registry.IsRegistered(event_usecase.BLOCK_CREATED, 1)).To(BeTrue())
+
+		decodedEvent, err := registry.DecodeByType("AnyExtraEvent", 1, []byte("{\"anyField\":\"anyValue\"}"))
+		Expect(err).To(BeNil())
+		Expect(decodedEvent).To(BeIdenticalTo(anyExtraEvent))
+		Expect(string(decodedPayload)).To(Equal("{\"anyField\":\"anyValue\"}"))
+	})
+
+	It("should mount the registered routes under the route prefix of the HTTP API server", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		listeningAddress := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		config := &Config{}
+		config.HTTP.ListeningAddress = listeningAddress
+		config.HTTP.RoutePrefix = "/indexer"
+		config.CosmosApp.HTTPRPCUL = "http://127.0.0.1:1317"
+		config.Blockchain.BaseDenom = "basetcro"
+		server := app.newHTTPAPIServer(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		runErrCh := make(chan error, 1)
+		go func() {
+			runErrCh <- server.Run(ctx)
+		}()
+		defer func() {
+			cancel()
+			Eventually(runErrCh).Should(Receive(BeNil()))
+		}()
+
+		// the connection is closed after each request, so that the server shuts down immediately
+		httpClient := &http.Client{
+			Transport: &http.Transport{
+				DisableKeepAlives: true,
+			},
+		}
+		Eventually(func() (int, error) {
+			response, err := httpClient.Get(fmt.Sprintf("http://%s/indexer/api/v1/health", listeningAddress))
+			if err != nil {
+				return 0, err
+			}
+			defer response.Body.Close()
+			return response.StatusCode, nil
+		}).Should(Equal(http.StatusOK))
+
+		response, err := httpClient.Get(fmt.Sprintf("http://%s/indexer/api/v1/any-extra", listeningAddress))
+		Expect(err).To(BeNil())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("basetcro"))
+	})
+
+	It("should return Error when the migration source name is invalid or registered twice", func() {
+		Expect(app.RegisterMigrations("any_extra", "/any/folder")).To(Succeed())
+		Expect(app.RegisterMigrations("any_extra", "/any/other/folder")).To(MatchError(
+			"migration source `any_extra` already registered",
+		))
+		Expect(app.RegisterMigrations("Any-Extra", "/any/folder")).To(MatchError(
+			"invalid migration source name `Any-Extra`",
+		))
+	})
+})
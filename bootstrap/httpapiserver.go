@@ -1,7 +1,9 @@
-package main
+package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/lab259/cors"
 
@@ -18,6 +20,9 @@ import (
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
 )
 
+// HTTP_SHUTDOWN_TIMEOUT is the maximum time to wait for open connections to finish on shutdown
+const HTTP_SHUTDOWN_TIMEOUT = 10 * time.Second
+
 type HTTPAPIServer struct {
 	logger          applogger.Logger
 	rdbConn         rdb.Conn
@@ -32,14 +37,26 @@ type HTTPAPIServer struct {
 	corsAllowedOrigins []string
 	corsAllowedMethods []string
 	corsAllowedHeaders []string
+
+	enabledProjectionIds []string
+	routeRegistrars      []RouteRegistrar
+	config               *Config
 }
 
 // NewIndexService creates a new server instance for polling and indexing
-func NewHTTPAPIServer(logger applogger.Logger, rdbConn rdb.Conn, config *Config) *HTTPAPIServer {
+func NewHTTPAPIServer(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config *Config,
+	enabledProjectionIds []string,
+	routeRegistrars []RouteRegistrar,
+) *HTTPAPIServer {
 	return &HTTPAPIServer{
-		logger:          logger,
-		rdbConn:         rdbConn,
-		cosmosAppClient: cosmosapp_infrastructure.NewHTTPClient(config.CosmosApp.HTTPRPCUL),
+		logger:  logger,
+		rdbConn: rdbConn,
+		cosmosAppClient: cosmosapp_infrastructure.NewMultiEndpointHTTPClient(
+			config.CosmosApp.AllHTTPRPCURLs(),
+		),
 
 		validatorAddressPrefix: config.Blockchain.ValidatorAddressPrefix,
 		conNodeAddressPrefix:   config.Blockchain.ConNodeAddressPrefix,
@@ -49,11 +66,16 @@ func NewHTTPAPIServer(logger applogger.Logger, rdbConn rdb.Conn, config *Config)
 		corsAllowedOrigins: config.HTTP.CorsAllowedOrigins,
 		corsAllowedMethods: config.HTTP.CorsAllowedMethods,
 		corsAllowedHeaders: config.HTTP.CorsAllowedHeaders,
+
+		enabledProjectionIds: enabledProjectionIds,
+		routeRegistrars:      routeRegistrars,
+		config:               config,
 	}
 }
 
-// Run function runs the polling server to index the data from Tendermint
-func (server *HTTPAPIServer) Run() error {
+// Run function runs the polling server to index the data from Tendermint. It returns after the
+// context is cancelled and the server is shut down.
+func (server *HTTPAPIServer) Run(ctx context.Context) error {
 	httpServer := httpapi.NewServer(
 		server.listeningAddress,
 	).WithLogger(
@@ -69,9 +91,13 @@ func (server *HTTPAPIServer) Run() error {
 		})
 	}
 
-	searchHandler := handlers.NewSearch(server.logger, server.rdbConn.ToHandle())
+	searchHandler := handlers.NewSearch(
+		server.logger, server.rdbConn.ToHandle(),
+	).WithEnabledProjections(server.enabledProjectionIds)
 	blocksHandler := handlers.NewBlocks(server.logger, server.rdbConn.ToHandle())
-	statusHandler := handlers.NewStatusHandler(server.logger, server.rdbConn.ToHandle())
+	statusHandler := handlers.NewStatusHandler(
+		server.logger, server.rdbConn.ToHandle(),
+	).WithEnabledProjections(server.enabledProjectionIds)
 	transactionsHandler := handlers.NewTransactions(server.logger, server.rdbConn.ToHandle())
 	blockEventsHandler := handlers.NewBlockEvents(server.logger, server.rdbConn.ToHandle())
 	validatorsHandler := handlers.NewValidators(
@@ -83,6 +109,8 @@ func (server *HTTPAPIServer) Run() error {
 	)
 	accountMessagesHandler := handlers.NewAccountMessages(server.logger, server.rdbConn.ToHandle())
 	accountsHandler := handlers.NewAccounts(server.logger, server.rdbConn.ToHandle())
+	eventDigestsHandler := handlers.NewEventDigests(server.logger, server.rdbConn.ToHandle())
+	proposalsHandler := handlers.NewProposals(server.logger, server.rdbConn.ToHandle())
 
 	routeRegistry := routes.NewRoutesRegistry(
 		searchHandler,
@@ -93,13 +121,28 @@ func (server *HTTPAPIServer) Run() error {
 		validatorsHandler,
 		accountMessagesHandler,
 		accountsHandler,
-	)
+		eventDigestsHandler,
+		proposalsHandler,
+	).WithEnabledProjections(server.enabledProjectionIds)
 	routeRegistry.Register(httpServer, server.routePrefix)
 
+	extraRoutePrefix := server.routePrefix
+	if extraRoutePrefix == "/" {
+		extraRoutePrefix = ""
+	}
+	for _, registrar := range server.routeRegistrars {
+		registrar(httpServer, extraRoutePrefix, RouteParams{
+			Logger:    server.logger,
+			RDbHandle: server.rdbConn.ToHandle(),
+			Config:    server.config,
+		})
+	}
+
 	server.logger.Infof("server start listening on: %s", server.listeningAddress)
-	if err := httpServer.ListenAndServe(); err != nil {
+	if err := httpServer.ListenAndServeWithContext(ctx, HTTP_SHUTDOWN_TIMEOUT); err != nil {
 		return fmt.Errorf("error listening and serving HTTP API server: %v", err)
 	}
+	server.logger.Info("server stopped")
 
 	return nil
 }
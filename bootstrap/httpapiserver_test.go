@@ -0,0 +1,279 @@
+package bootstrap_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	polling "github.com/crypto-com/chain-indexing/appinterface/polling"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	rdb_test "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+)
+
+var _ = Describe("HTTPAPIServer", func() {
+	var rdbConn *fakeViewsRDbConn
+	var baseURL string
+	var cancel context.CancelFunc
+	var runErrCh chan error
+
+	runServer := func(enabledProjectionIds []string, routeRegistrars []bootstrap.RouteRegistrar) {
+		listeningAddress := freeListeningAddress()
+		baseURL = fmt.Sprintf("http://%s", listeningAddress)
+
+		config := &bootstrap.Config{}
+		config.HTTP.ListeningAddress = listeningAddress
+		config.HTTP.RoutePrefix = "/"
+		config.CosmosApp.HTTPRPCUL = "http://127.0.0.1:1317"
+		server := bootstrap.NewHTTPAPIServer(
+			NewFakeLogger(), rdbConn, config, enabledProjectionIds, routeRegistrars,
+		)
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		runErrCh = make(chan error, 1)
+		go func() {
+			runErrCh <- server.Run(ctx)
+		}()
+		Eventually(func() (int, error) {
+			statusCode, _, err := httpGet(baseURL + "/api/v1/health")
+			return statusCode, err
+		}).Should(Equal(http.StatusOK))
+	}
+
+	BeforeEach(func() {
+		rdbConn = newFakeViewsRDbConn()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(runErrCh).Should(Receive(BeNil()))
+	})
+
+	Context("when only some projections are enabled", func() {
+		BeforeEach(func() {
+			runServer([]string{"Block", "Transaction"}, nil)
+		})
+
+		It("should mount the endpoints of the enabled projections", func() {
+			for _, path := range []string{
+				"/api/v1/blocks",
+				"/api/v1/blocks/1/transactions",
+				"/api/v1/transactions",
+			} {
+				statusCode, _, err := httpGet(baseURL + path)
+				Expect(err).To(BeNil())
+				Expect(statusCode).NotTo(Equal(http.StatusNotFound), path)
+			}
+		})
+
+		It("should return 404 on the endpoints of the projections which are not enabled", func() {
+			for _, path := range []string{
+				"/api/v1/blocks/1/events",
+				"/api/v1/events",
+				"/api/v1/accounts/tcro1/messages",
+				"/api/v1/validators",
+				"/api/v1/validators/active",
+				"/api/v1/accounts/info",
+				"/api/v1/proposals",
+			} {
+				statusCode, _, err := httpGet(baseURL + path)
+				Expect(err).To(BeNil())
+				Expect(statusCode).To(Equal(http.StatusNotFound), path)
+			}
+		})
+
+		It("should omit the status of the projections which are not enabled", func() {
+			statusCode, body, err := httpGet(baseURL + "/api/v1/status")
+			Expect(err).To(BeNil())
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			var response struct {
+				Result map[string]interface{} `json:"result"`
+			}
+			Expect(json.Unmarshal(body, &response)).To(Succeed())
+			Expect(response.Result).To(HaveKey("blockCount"))
+			Expect(response.Result).To(HaveKey("transactionCount"))
+			Expect(response.Result).To(HaveKeyWithValue("latestHeight", float64(100)))
+			Expect(response.Result).NotTo(HaveKey("validatorCount"))
+			Expect(response.Result).NotTo(HaveKey("activeValidatorCount"))
+			Expect(response.Result).NotTo(HaveKey("totalDelegated"))
+			Expect(response.Result).NotTo(HaveKey("totalReward"))
+
+			Expect(rdbConn.hasQueried("view_blocks")).To(BeTrue())
+			Expect(rdbConn.hasQueried("view_transactions_total")).To(BeTrue())
+			Expect(rdbConn.hasQueried("view_validators")).To(BeFalse())
+			Expect(rdbConn.hasQueried("view_validator_stats")).To(BeFalse())
+		})
+
+		It("should search the views of the enabled projections only", func() {
+			statusCode, _, err := httpGet(baseURL + "/api/v1/search?keyword=1")
+			Expect(err).To(BeNil())
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			Expect(rdbConn.hasQueried("view_blocks")).To(BeTrue())
+			Expect(rdbConn.hasQueried("view_validators")).To(BeFalse())
+		})
+	})
+
+	Context("when all the projections are enabled", func() {
+		BeforeEach(func() {
+			runServer([]string{
+				"Block",
+				"Transaction",
+				"BlockEvent",
+				"Validator",
+				"ValidatorStats",
+				"AccountMessage",
+				"Account",
+				"Proposal",
+			}, nil)
+		})
+
+		It("should mount the endpoints of all the projections", func() {
+			for _, path := range []string{
+				"/api/v1/blocks",
+				"/api/v1/transactions",
+				"/api/v1/events",
+				"/api/v1/accounts/tcro1/messages",
+				"/api/v1/validators",
+				"/api/v1/proposals",
+			} {
+				statusCode, _, err := httpGet(baseURL + path)
+				Expect(err).To(BeNil())
+				Expect(statusCode).NotTo(Equal(http.StatusNotFound), path)
+			}
+		})
+
+		It("should report the status of all the projections", func() {
+			statusCode, body, err := httpGet(baseURL + "/api/v1/status")
+			Expect(err).To(BeNil())
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			var response struct {
+				Result map[string]interface{} `json:"result"`
+			}
+			Expect(json.Unmarshal(body, &response)).To(Succeed())
+			for _, key := range []string{
+				"blockCount",
+				"transactionCount",
+				"validatorCount",
+				"activeValidatorCount",
+				"totalDelegated",
+				"totalReward",
+			} {
+				Expect(response.Result).To(HaveKey(key))
+			}
+		})
+	})
+})
+
+func freeListeningAddress() string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+// httpClient closes the connection after each request, so that the server shuts down immediately
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		DisableKeepAlives: true,
+	},
+}
+
+func httpGet(url string) (int, []byte, error) {
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return response.StatusCode, body, nil
+}
+
+// fakeViewsRDbConn records the queried SQL. Every view is empty, except the latest height in status
+// is 100.
+type fakeViewsRDbConn struct {
+	*rdb_test.FakeRDbConn
+
+	mutex   sync.Mutex
+	queries []string
+}
+
+func newFakeViewsRDbConn() *fakeViewsRDbConn {
+	return &fakeViewsRDbConn{
+		FakeRDbConn: rdb_test.NewFakeRDbConn(),
+
+		queries: make([]string, 0),
+	}
+}
+
+func (conn *fakeViewsRDbConn) Query(sql string, args ...interface{}) (rdb.RowsResult, error) {
+	conn.recordQuery(sql)
+	return &rdb_test.FakeRDbRowsResult{}, nil
+}
+
+func (conn *fakeViewsRDbConn) QueryRow(sql string, args ...interface{}) rdb.RowResult {
+	conn.recordQuery(sql)
+	return &fakeViewsRDbRowResult{sql, args}
+}
+
+func (conn *fakeViewsRDbConn) ToHandle() *rdb.Handle {
+	return &rdb.Handle{
+		Runner:      conn,
+		TypeConv:    &pg.PgxTypeConv{},
+		StmtBuilder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (conn *fakeViewsRDbConn) recordQuery(sql string) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	conn.queries = append(conn.queries, sql)
+}
+
+func (conn *fakeViewsRDbConn) hasQueried(table string) bool {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	for _, query := range conn.queries {
+		if strings.Contains(query, fmt.Sprintf("FROM %s ", table)) || strings.HasSuffix(query, "FROM "+table) {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeViewsRDbRowResult struct {
+	sql  string
+	args []interface{}
+}
+
+func (result *fakeViewsRDbRowResult) Scan(dest ...interface{}) error {
+	if !strings.Contains(result.sql, "FROM view_status") {
+		return nil
+	}
+	if result.args[0] == polling.PARTIAL_HISTORY_START_HEIGHT {
+		return rdb.ErrNoRows
+	}
+	*dest[0].(*string) = "100"
+	return nil
+}
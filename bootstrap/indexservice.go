@@ -0,0 +1,333 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	eventhandler_interface "github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/deadletter"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/parser"
+	"github.com/crypto-com/chain-indexing/usecase/syncstrategy"
+)
+
+type IndexService struct {
+	logger        applogger.Logger
+	rdbConn       rdb.Conn
+	eventRegistry *event.Registry
+	projections   []projection_entity.Projection
+
+	systemMode            string
+	baseDenom             string
+	accountAddressPrefix  string
+	consNodeAddressPrefix string
+	windowSize            int
+	syncStrategy          string
+	syncConfig            SyncConfig
+	rawBlockRetention     int64
+	maxHandleRetries      int
+	resilientSyncParams   syncstrategy.ResilientParams
+	tendermintHTTPRPCURLs []string
+	tendermintBlockFeed   string
+	tendermintWSURL       string
+	tendermintArchivePath string
+	tendermintCacheDir    string
+	tendermintCacheSize   int64
+	cosmosAppHTTPRPCURLs  []string
+}
+
+// NewIndexService creates a new server instance for polling and indexing
+func NewIndexService(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config *Config,
+	eventRegistry *event.Registry,
+	projections []projection_entity.Projection,
+) *IndexService {
+	return &IndexService{
+		logger:        logger,
+		rdbConn:       rdbConn,
+		eventRegistry: eventRegistry,
+		projections:   projections,
+
+		systemMode:            config.System.Mode,
+		baseDenom:             config.Blockchain.BaseDenom,
+		accountAddressPrefix:  config.Blockchain.AccountAddressPrefix,
+		consNodeAddressPrefix: config.Blockchain.ConNodeAddressPrefix,
+		windowSize:            config.Sync.WindowSize,
+		syncStrategy:          config.Sync.Strategy,
+		syncConfig:            config.Sync,
+		rawBlockRetention:     config.EventStore.RawBlockRetentionDepth,
+		maxHandleRetries:      config.Projection.MaxHandleRetries,
+		tendermintHTTPRPCURLs: config.Tendermint.AllHTTPRPCURLs(),
+		tendermintBlockFeed:   config.Tendermint.BlockFeed,
+		tendermintWSURL:       config.Tendermint.WebSocketURL,
+		tendermintArchivePath: config.Tendermint.ArchivePath,
+		tendermintCacheDir:    config.Tendermint.CacheDir,
+		tendermintCacheSize:   config.Tendermint.CacheMaxSize * 1024 * 1024,
+		cosmosAppHTTPRPCURLs:  config.CosmosApp.AllHTTPRPCURLs(),
+	}
+}
+
+// Run runs the index service until the context is cancelled. It returns after the in-flight blocks are
+// handled and all projections are stopped.
+func (service *IndexService) Run(ctx context.Context) error {
+	// run polling tendermint manager, update view tables directly
+	// no live node is required when replaying from an archive, and the chain info is not kept without
+	// database
+	if len(service.tendermintHTTPRPCURLs) > 0 && service.rdbConn != nil {
+		infoManager := NewInfoManager(
+			service.logger,
+			service.rdbConn,
+			service.tendermintHTTPRPCURLs,
+		)
+		infoManager.Run(ctx)
+	}
+
+	var err error
+	if service.resilientSyncParams, err = parseResilientSyncParams(&service.syncConfig); err != nil {
+		return fmt.Errorf("error parsing resilient sync config: %v", err)
+	}
+
+	switch service.systemMode {
+	case SYSTEM_MODE_EVENT_STORE:
+		err = service.RunEventStoreMode(ctx)
+	case SYSTEM_MODE_TENDERMINT_DIRECT:
+		err = service.RunTendermintDirectMode(ctx)
+	case SYSTEM_MODE_IN_MEMORY:
+		err = service.RunInMemoryMode(ctx)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *IndexService) RunEventStoreMode(ctx context.Context) error {
+	eventStore := event_interface.NewRDbStore(service.rdbConn.ToHandle(), service.eventRegistry)
+
+	projectionManager := projection_entity.NewStoreBasedManager(service.logger, eventStore)
+	if isAfterGenesis(service.syncConfig.StartHeight) {
+		projectionManager.WithStartHeight(service.syncConfig.StartHeight - 1)
+	}
+	projectionManager.WithDeadLetterQueue(
+		deadletter.NewRDbQueue(service.rdbConn.ToHandle()), service.maxHandleRetries,
+	)
+
+	for _, projection := range service.projections {
+		if err := projectionManager.RegisterProjection(projection); err != nil {
+			return fmt.Errorf("error registering projection `%s` to manager %v", projection.Id(), err)
+		}
+	}
+
+	eventStoreHandler := eventhandler_interface.NewRDbEventStoreHandler(
+
+		service.logger,
+		service.rdbConn,
+		service.eventRegistry,
+	)
+	txDecoder := parser.NewTxDecoder(service.baseDenom)
+	syncManager, err := NewSyncManager(
+		SyncManagerParams{
+			Logger:            service.logger,
+			RDbConn:           service.rdbConn,
+			TxDecoder:         txDecoder,
+			RollbackTargets:   []projection_entity.Rollbackable{projectionManager},
+			StateBootstrapper: service.newStateBootstrapper(),
+			Config:            service.syncManagerConfig(),
+		},
+		eventStoreHandler,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating sync manager: %v", err)
+	}
+
+	consumers := []func(ctx context.Context){
+		projectionManager.Run,
+		func(ctx context.Context) {
+			runProjectionRebuildListener(ctx, service.logger, service.rdbConn, projectionManager)
+		},
+	}
+	if service.rawBlockRetention > 0 {
+		payloadArchiver := event_interface.NewPayloadArchiver(
+			service.logger, service.rdbConn, eventStore, event_usecase.RAW_BLOCK_CREATED, service.rawBlockRetention,
+		)
+		consumers = append(consumers, payloadArchiver.Run)
+	}
+
+	return runUntilStopped(ctx, syncManager, func(ctx context.Context) {
+		runAll(ctx, consumers...)
+	})
+}
+
+// RunInMemoryMode runs the event store mode with the events kept in memory, so that no database is
+// required. Projections have to keep their states in memory as well, see inmemoryprojectionbase.
+func (service *IndexService) RunInMemoryMode(ctx context.Context) error {
+	eventStore := event_interface.NewInMemoryStore()
+
+	projectionManager := projection_entity.NewStoreBasedManager(service.logger, eventStore)
+	if isAfterGenesis(service.syncConfig.StartHeight) {
+		projectionManager.WithStartHeight(service.syncConfig.StartHeight - 1)
+	}
+	for _, projection := range service.projections {
+		if err := projectionManager.RegisterProjection(projection); err != nil {
+			return fmt.Errorf("error registering projection `%s` to manager %v", projection.Id(), err)
+		}
+	}
+
+	syncManager, err := NewSyncManager(
+		SyncManagerParams{
+			Logger:            service.logger,
+			TxDecoder:         parser.NewTxDecoder(service.baseDenom),
+			RollbackTargets:   []projection_entity.Rollbackable{projectionManager},
+			StateBootstrapper: service.newStateBootstrapper(),
+			Config:            service.syncManagerConfig(),
+		},
+		eventhandler_interface.NewInMemoryEventStoreHandler(service.logger, eventStore),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating sync manager: %v", err)
+	}
+
+	return runUntilStopped(ctx, syncManager, func(ctx context.Context) {
+		projectionManager.Run(ctx)
+	})
+}
+
+func (service *IndexService) syncManagerConfig() SyncManagerConfig {
+	return SyncManagerConfig{
+		WindowSize:        service.windowSize,
+		Strategy:          service.syncStrategy,
+		ResilientParams:   service.resilientSyncParams,
+		TendermintRPCUrls: service.tendermintHTTPRPCURLs,
+		BlockFeed:         service.tendermintBlockFeed,
+		WebSocketURL:      service.tendermintWSURL,
+		ArchivePath:       service.tendermintArchivePath,
+		CacheDir:          service.tendermintCacheDir,
+		CacheMaxSize:      service.tendermintCacheSize,
+		StartHeight:       service.syncConfig.StartHeight,
+		MaxReorgDepth:     service.syncConfig.MaxReorgDepth,
+	}
+}
+
+func (service *IndexService) RunTendermintDirectMode(ctx context.Context) error {
+	txDecoder := parser.NewTxDecoder(service.baseDenom)
+
+	// blocks are fetched and parsed once and the events are fanned out to all projections
+	projectionHandlers := make([]*eventhandler_interface.ProjectionHandler, 0, len(service.projections))
+	for _, projection := range service.projections {
+		projectionHandlers = append(projectionHandlers, eventhandler_interface.NewProjectionHandler(
+			service.logger.WithFields(applogger.LogFields{
+				"projection": projection.Id(),
+			}), service.rdbConn.ToHandle(), projection,
+		))
+	}
+	fanOutHandler := eventhandler_interface.NewFanOutHandler(service.logger, projectionHandlers)
+	if isAfterGenesis(service.syncConfig.StartHeight) {
+		fanOutHandler.WithStartHeight(service.syncConfig.StartHeight - 1)
+	}
+	fanOutHandler.WithDeadLetterQueue(
+		deadletter.NewRDbQueue(service.rdbConn.ToHandle()), service.maxHandleRetries,
+	)
+
+	syncManager, err := NewSyncManager(SyncManagerParams{
+		Logger:            service.logger,
+		RDbConn:           service.rdbConn,
+		TxDecoder:         txDecoder,
+		StateBootstrapper: service.newStateBootstrapper(),
+		Config:            service.syncManagerConfig(),
+	}, fanOutHandler)
+	if err != nil {
+		return fmt.Errorf("error creating sync manager: %v", err)
+	}
+
+	return runUntilStopped(ctx, syncManager, func(ctx context.Context) {
+		runAll(ctx, func(ctx context.Context) {
+			fanOutHandler.Run(ctx, syncManager.FetchBlockEvents)
+		}, func(ctx context.Context) {
+			runProjectionRebuildListener(ctx, service.logger, service.rdbConn, fanOutHandler)
+		})
+	})
+}
+
+// newStateBootstrapper returns the bootstrapper of Cosmos app state when indexing starts after
+// genesis, nil otherwise
+func (service *IndexService) newStateBootstrapper() *StateBootstrapper {
+	if !isAfterGenesis(service.syncConfig.StartHeight) {
+		return nil
+	}
+
+	return NewStateBootstrapper(
+		service.logger,
+		cosmosapp_infrastructure.NewMultiEndpointHTTPClient(service.cosmosAppHTTPRPCURLs),
+		service.baseDenom,
+		service.accountAddressPrefix,
+	)
+}
+
+// runUntilStopped runs the sync manager and the event consumer until the context is cancelled or the
+// sync manager fails. The consumer is always stopped before it returns, so that no event handling is
+// interrupted on shutdown.
+func runUntilStopped(ctx context.Context, syncManager *SyncManager, runConsumer func(ctx context.Context)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	consumerDoneCh := make(chan struct{})
+	go func() {
+		runConsumer(ctx)
+		close(consumerDoneCh)
+	}()
+
+	syncErr := syncManager.Run(ctx)
+	cancel()
+	<-consumerDoneCh
+
+	if syncErr != nil {
+		return fmt.Errorf("error running sync manager %v", syncErr)
+	}
+	return nil
+}
+
+// runAll runs the functions concurrently and returns after all of them return
+func runAll(ctx context.Context, runs ...func(ctx context.Context)) {
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		wg.Add(1)
+		go func(run func(ctx context.Context)) {
+			defer wg.Done()
+			run(ctx)
+		}(run)
+	}
+	wg.Wait()
+}
+
+func parseResilientSyncParams(config *SyncConfig) (syncstrategy.ResilientParams, error) {
+	params := syncstrategy.ResilientParams{
+		MaxInFlight: config.MaxInFlight,
+		MaxRetries:  config.MaxRetries,
+	}
+
+	var err error
+	if config.RetryInitialBackoff != "" {
+		if params.InitialBackoff, err = time.ParseDuration(config.RetryInitialBackoff); err != nil {
+			return params, fmt.Errorf("error parsing retry_initial_backoff: %v", err)
+		}
+	}
+	if config.RetryMaxBackoff != "" {
+		if params.MaxBackoff, err = time.ParseDuration(config.RetryMaxBackoff); err != nil {
+			return params, fmt.Errorf("error parsing retry_max_backoff: %v", err)
+		}
+	}
+
+	return params, nil
+}
@@ -1,6 +1,7 @@
-package main
+package bootstrap
 
 import (
+	"context"
 	"time"
 
 	"github.com/crypto-com/chain-indexing/appinterface/polling"
@@ -22,9 +23,9 @@ type InfoManager struct {
 func NewInfoManager(
 	logger applogger.Logger,
 	rdbConn rdb.Conn,
-	tendermintRPCUrl string,
+	tendermintRPCUrls []string,
 ) *InfoManager {
-	tendermintClient := tendermint.NewHTTPClient(tendermintRPCUrl)
+	tendermintClient := tendermint.NewMultiEndpointHTTPClient(tendermintRPCUrls)
 
 	viewStatus := polling.NewStatus(rdbConn.ToHandle())
 	return &InfoManager{
@@ -37,11 +38,24 @@ func NewInfoManager(
 
 }
 
-func (manager *InfoManager) Run() {
+// Run polls the status in background until the context is cancelled
+func (manager *InfoManager) Run(ctx context.Context) {
 	manager.logger.Infof("infomanager started")
 	go func() {
 		for {
-			status, _ := manager.client.Status()
+			select {
+			case <-ctx.Done():
+				manager.logger.Infof("infomanager stopped")
+				return
+			default:
+			}
+
+			status, err := manager.client.Status()
+			if err != nil {
+				manager.logger.Errorf("error requesting Tendermint status: %v", err)
+				time.Sleep(manager.pollingInterval)
+				continue
+			}
 			result := (*status)["result"]
 			syncInfo := result.(map[string]interface{})["sync_info"]
 			latestHeight := syncInfo.(map[string]interface{})["latest_block_height"].(string)
@@ -0,0 +1,73 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+)
+
+func (app *App) newMigrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Apply the built-in migrations and the migrations registered by the extensions",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "migrationsDir",
+				Usage: "`FOLDER` of the built-in migrations",
+				Value: "./migrations",
+			},
+		},
+		Action: app.migrate,
+	}
+}
+
+func (app *App) migrate(ctx *cli.Context) error {
+	if !ctx.IsSet("dbPassword") {
+		return errors.New("Required flag \"dbPassword\" not set")
+	}
+	config, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	connConfig := newPgConnConfig(config)
+
+	sources := append([]MigrationSource{{
+		Name:   "",
+		Folder: ctx.String("migrationsDir"),
+	}}, app.migrationSources...)
+	for _, source := range sources {
+		migrationsTable := ""
+		sourceName := "built-in"
+		if source.Name != "" {
+			migrationsTable = fmt.Sprintf("schema_migrations_%s", source.Name)
+			sourceName = fmt.Sprintf("`%s`", source.Name)
+		}
+
+		migrate, err := pg.NewMigrateWithTable(&connConfig, source.Folder, migrationsTable)
+		if err != nil {
+			return fmt.Errorf("error creating %s migrations from %s: %v", sourceName, source.Folder, err)
+		}
+		err = migrate.Up()
+		sourceErr, dbErr := migrate.Close()
+		if err != nil {
+			return fmt.Errorf("error applying %s migrations: %v", sourceName, err)
+		}
+		if sourceErr != nil {
+			logger.Errorf("error closing %s migrations source: %v", sourceName, sourceErr)
+		}
+		if dbErr != nil {
+			logger.Errorf("error closing %s migrations database connection: %v", sourceName, dbErr)
+		}
+		logger.Infof("applied %s migrations from %s", sourceName, source.Folder)
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/deadletter"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+const PROJECTION_REBUILD_CHANNEL = "projection_rebuild"
+const PROJECTION_REBUILD_DONE_CHANNEL = "projection_rebuild_done"
+
+// PROJECTION_REBUILD_RESEND_INTERVAL is how often a rebuild request is sent again until the running
+// service acknowledges it, since notifications sent before the service listens are lost
+const PROJECTION_REBUILD_RESEND_INTERVAL = 2 * time.Second
+
+func (app *App) newProjectionCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "projection",
+		Usage: "Manage the projections",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "rebuild",
+				Usage:     "Truncate the tables of a projection and replay it from the start",
+				ArgsUsage: "<Id>",
+				Description: "The projection is reset by the running index service, which keeps indexing and " +
+					"replays the projection in the background. Use --offline when the service is stopped, " +
+					"the projection is then replayed on the next start.",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "How long to wait for the running service to reset the projection",
+						Value: time.Minute,
+					},
+					&cli.BoolFlag{
+						Name:  "offline",
+						Usage: "Reset the projection directly, the index service must not be running",
+					},
+				},
+				Action: app.rebuildProjection,
+			},
+			{
+				Name:   "deadletters",
+				Usage:  "List the heights quarantined by the projections and not yet resolved",
+				Action: listDeadLetters,
+			},
+			{
+				Name:      "retry",
+				Usage:     "Request the projection to handle the quarantined height again",
+				ArgsUsage: "<Id> <height>",
+				Action: func(ctx *cli.Context) error {
+					return requestDeadLetterAction(ctx, projection_entity.DEAD_LETTER_STATUS_RETRY)
+				},
+			},
+			{
+				Name:      "skip",
+				Usage:     "Request the projection to skip the quarantined height without handling its events",
+				ArgsUsage: "<Id> <height>",
+				Action: func(ctx *cli.Context) error {
+					return requestDeadLetterAction(ctx, projection_entity.DEAD_LETTER_STATUS_SKIP)
+				},
+			},
+		},
+	}
+}
+
+// projectionRebuildRequest is sent to the running service on PROJECTION_REBUILD_CHANNEL
+type projectionRebuildRequest struct {
+	RequestId    string `json:"requestId"`
+	ProjectionId string `json:"projectionId"`
+}
+
+// projectionRebuildResult is sent back on PROJECTION_REBUILD_DONE_CHANNEL once the projection is reset
+type projectionRebuildResult struct {
+	RequestId  string  `json:"requestId"`
+	MaybeError *string `json:"error"`
+}
+
+func (app *App) rebuildProjection(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return errors.New("exactly one projection Id is expected")
+	}
+	projectionId := ctx.Args().First()
+
+	if !ctx.IsSet("dbPassword") {
+		return errors.New("Required flag \"dbPassword\" not set")
+	}
+	config, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	rdbConn, err := SetupRDbConn(config, logger)
+	if err != nil {
+		return fmt.Errorf("error setting up RDb connection: %v", err)
+	}
+	defer closeRDbConn(logger, rdbConn)
+
+	if ctx.Bool("offline") {
+		if err := app.resetProjectionOffline(logger, rdbConn, config, projectionId); err != nil {
+			return err
+		}
+		logger.Infof("projection `%s` is reset, it is replayed when the index service starts", projectionId)
+		return nil
+	}
+
+	if err := requestProjectionRebuild(rdbConn, rdbConn, projectionId, ctx.Duration("timeout")); err != nil {
+		return err
+	}
+	logger.Infof("projection `%s` is reset, it is being replayed by the index service", projectionId)
+	return nil
+}
+
+func listDeadLetters(ctx *cli.Context) error {
+	if !ctx.IsSet("dbPassword") {
+		return errors.New("Required flag \"dbPassword\" not set")
+	}
+	config, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	rdbConn, err := SetupRDbConn(config, logger)
+	if err != nil {
+		return fmt.Errorf("error setting up RDb connection: %v", err)
+	}
+	defer closeRDbConn(logger, rdbConn)
+
+	deadLetters, err := deadletter.NewRDbQueue(rdbConn.ToHandle()).ListUnresolved()
+	if err != nil {
+		return fmt.Errorf("error listing dead letters: %v", err)
+	}
+	if len(deadLetters) == 0 {
+		fmt.Println("no quarantined height")
+		return nil
+	}
+	for _, deadLetter := range deadLetters {
+		fmt.Printf(
+			"%s\theight=%d\tstatus=%s\tevents=%d\tquarantinedAt=%s\terror=%s\n",
+			deadLetter.ProjectionId,
+			deadLetter.Height,
+			deadLetter.Status,
+			len(deadLetter.Events),
+			deadLetter.QuarantinedAt.String(),
+			deadLetter.Error,
+		)
+	}
+	return nil
+}
+
+// requestDeadLetterAction sets the status of the quarantined height, which is picked up by the
+// running index service, or on next start
+func requestDeadLetterAction(ctx *cli.Context, status string) error {
+	if ctx.Args().Len() != 2 {
+		return errors.New("projection Id and height are expected")
+	}
+	projectionId := ctx.Args().Get(0)
+	height, err := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing height: %v", err)
+	}
+
+	if !ctx.IsSet("dbPassword") {
+		return errors.New("Required flag \"dbPassword\" not set")
+	}
+	config, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	rdbConn, err := SetupRDbConn(config, logger)
+	if err != nil {
+		return fmt.Errorf("error setting up RDb connection: %v", err)
+	}
+	defer closeRDbConn(logger, rdbConn)
+
+	if err := deadletter.NewRDbQueue(rdbConn.ToHandle()).RequestAction(projectionId, height, status); err != nil {
+		return err
+	}
+	logger.Infof("requested projection `%s` to %s height %d", projectionId, strings.ToLower(status), height)
+	return nil
+}
+
+func (app *App) resetProjectionOffline(logger applogger.Logger, rdbConn rdb.Conn, config *Config, projectionId string) error {
+	projections, err := app.initProjections(logger, rdbConn, config)
+	if err != nil {
+		return err
+	}
+	for _, projection := range projections {
+		if projection.Id() != projectionId {
+			continue
+		}
+
+		resettableProjection, ok := projection.(projection_entity.Resettable)
+		if !ok {
+			return fmt.Errorf("projection `%s` does not support reset", projectionId)
+		}
+		// the owned tables may not be migrated yet when the service has never run
+		if err := projection.OnInit(); err != nil {
+			return fmt.Errorf("error initializing projection `%s`: %v", projectionId, err)
+		}
+		if err := resettableProjection.Reset(); err != nil {
+			return fmt.Errorf("error resetting projection `%s`: %v", projectionId, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("projection `%s` is not registered", projectionId)
+}
+
+// requestProjectionRebuild asks the running service to reset the projection and waits until it is
+// done. The request is resent until it is acknowledged or timed out.
+func requestProjectionRebuild(
+	rdbConn rdb.Conn, listener rdb.Listener, projectionId string, timeout time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	request := projectionRebuildRequest{
+		RequestId:    uuid.New().String(),
+		ProjectionId: projectionId,
+	}
+	encodedRequest, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error encoding projection rebuild request: %v", err)
+	}
+
+	payloadCh := make(chan string)
+	listenErrCh := make(chan error, 1)
+	go func() {
+		listenErrCh <- listener.Listen(ctx, PROJECTION_REBUILD_DONE_CHANNEL, payloadCh)
+	}()
+
+	resendTicker := time.NewTicker(PROJECTION_REBUILD_RESEND_INTERVAL)
+	defer resendTicker.Stop()
+	if err := notify(rdbConn.ToHandle(), PROJECTION_REBUILD_CHANNEL, string(encodedRequest)); err != nil {
+		return err
+	}
+	for {
+		select {
+		case payload := <-payloadCh:
+			var result projectionRebuildResult
+			if err := json.Unmarshal([]byte(payload), &result); err != nil {
+				return fmt.Errorf("error decoding projection rebuild result: %v", err)
+			}
+			if result.RequestId != request.RequestId {
+				continue
+			}
+			if result.MaybeError != nil {
+				return fmt.Errorf("error rebuilding projection in the index service: %s", *result.MaybeError)
+			}
+			return nil
+		case <-resendTicker.C:
+			if err := notify(rdbConn.ToHandle(), PROJECTION_REBUILD_CHANNEL, string(encodedRequest)); err != nil {
+				return err
+			}
+		case err := <-listenErrCh:
+			if err != nil {
+				return fmt.Errorf("error waiting for projection rebuild result: %v", err)
+			}
+			return fmt.Errorf(
+				"timeout waiting for the index service to reset projection `%s`, is the service running?",
+				projectionId,
+			)
+		}
+	}
+}
+
+// runProjectionRebuildListener resets the projections requested by the rebuild command until the
+// context is cancelled. A request resent by the command is acknowledged without resetting again.
+func runProjectionRebuildListener(
+	ctx context.Context, logger applogger.Logger, rdbConn rdb.Conn, resetter projection_entity.Resetter,
+) {
+	logger = logger.WithFields(applogger.LogFields{
+		"module": "ProjectionRebuildListener",
+	})
+	listener, ok := rdbConn.(rdb.Listener)
+	if !ok {
+		logger.Infof("RDb connection does not support notifications, projections can only be rebuilt offline")
+		return
+	}
+
+	// the command resends its request until it is acknowledged
+	var lastResult projectionRebuildResult
+	for ctx.Err() == nil {
+		payloadCh := make(chan string)
+		listenErrCh := make(chan error, 1)
+		go func() {
+			listenErrCh <- listener.Listen(ctx, PROJECTION_REBUILD_CHANNEL, payloadCh)
+		}()
+
+	ListenLoop:
+		for {
+			select {
+			case payload := <-payloadCh:
+				var request projectionRebuildRequest
+				if err := json.Unmarshal([]byte(payload), &request); err != nil {
+					logger.Errorf("error decoding projection rebuild request: %v", err)
+					continue
+				}
+
+				if lastResult.RequestId != request.RequestId {
+					lastResult = resetProjection(logger, resetter, request)
+				}
+
+				encodedResult, err := json.Marshal(lastResult)
+				if err != nil {
+					logger.Errorf("error encoding projection rebuild result: %v", err)
+					continue
+				}
+				if err := notify(rdbConn.ToHandle(), PROJECTION_REBUILD_DONE_CHANNEL, string(encodedResult)); err != nil {
+					logger.Errorf("error sending projection rebuild result: %v", err)
+				}
+			case err := <-listenErrCh:
+				if err != nil {
+					logger.Errorf("error listening to projection rebuild requests, retrying in 5 seconds: %v", err)
+					select {
+					case <-time.After(5 * time.Second):
+					case <-ctx.Done():
+					}
+				}
+				break ListenLoop
+			}
+		}
+	}
+}
+
+func resetProjection(
+	logger applogger.Logger, resetter projection_entity.Resetter, request projectionRebuildRequest,
+) projectionRebuildResult {
+	logger = logger.WithFields(applogger.LogFields{
+		"projection": request.ProjectionId,
+	})
+	logger.Infof("resetting projection for rebuild")
+
+	result := projectionRebuildResult{
+		RequestId: request.RequestId,
+	}
+	if err := resetter.ResetProjection(request.ProjectionId); err != nil {
+		logger.Errorf("error resetting projection for rebuild: %v", err)
+		errMessage := err.Error()
+		result.MaybeError = &errMessage
+	}
+	return result
+}
+
+func notify(rdbHandle *rdb.Handle, channel string, payload string) error {
+	sql, args, err := rdbHandle.StmtBuilder.Select().Column(
+		sq.Expr("pg_notify(?, ?)", channel, payload),
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building notification SQL: %v", err)
+	}
+
+	if _, err = rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing notification SQL: %v", err)
+	}
+	return nil
+}
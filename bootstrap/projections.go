@@ -0,0 +1,303 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/projection/account"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/account_message"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/block"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/blockevent"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/inmemoryblock"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/proposal"
+	transaction "github.com/crypto-com/chain-indexing/appinterface/projection/transaction"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/validator"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/validatorstats"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+// ProjectionParams are the dependencies and settings to construct a projection
+type ProjectionParams struct {
+	Logger  applogger.Logger
+	RDbConn rdb.Conn
+	Config  *Config
+	// Options are the settings of the projection under [projection.options.<Id>]
+	Options map[string]interface{}
+}
+
+// ProjectionConstructor constructs the projection from the params
+type ProjectionConstructor = func(params ProjectionParams) (projection_entity.Projection, error)
+
+type registeredProjection struct {
+	id          string
+	constructor ProjectionConstructor
+}
+
+// builtinProjections are the built-in projections which can be enabled by Id, in the order they are run
+var builtinProjections = []registeredProjection{
+	{"Block", func(params ProjectionParams) (projection_entity.Projection, error) {
+		return block.NewBlock(params.Logger, params.RDbConn), nil
+	}},
+	{"Transaction", func(params ProjectionParams) (projection_entity.Projection, error) {
+		return transaction.NewTransaction(params.Logger, params.RDbConn), nil
+	}},
+	{"BlockEvent", func(params ProjectionParams) (projection_entity.Projection, error) {
+		return blockevent.NewBlockEvent(params.Logger, params.RDbConn), nil
+	}},
+	{"Validator", func(params ProjectionParams) (projection_entity.Projection, error) {
+		conNodeAddressPrefix, err := stringOption(
+			params.Options, "connode_address_prefix", params.Config.Blockchain.ConNodeAddressPrefix,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return validator.NewValidator(params.Logger, params.RDbConn, conNodeAddressPrefix), nil
+	}},
+	{"ValidatorStats", func(params ProjectionParams) (projection_entity.Projection, error) {
+		return validatorstats.NewValidatorStats(params.Logger, params.RDbConn), nil
+	}},
+	{"AccountMessage", func(params ProjectionParams) (projection_entity.Projection, error) {
+		return account_message.NewAccountMessage(params.Logger, params.RDbConn), nil
+	}},
+	{"Account", func(params ProjectionParams) (projection_entity.Projection, error) {
+		baseDenom, err := stringOption(params.Options, "base_denom", params.Config.Blockchain.BaseDenom)
+		if err != nil {
+			return nil, err
+		}
+		cosmosAppHTTPRPCURLs, err := stringsOption(
+			params.Options, "cosmosapp_http_rpc_urls", params.Config.CosmosApp.AllHTTPRPCURLs(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return account.NewAccount(
+			params.Logger,
+			params.RDbConn,
+			cosmosapp_infrastructure.NewMultiEndpointHTTPClient(cosmosAppHTTPRPCURLs),
+			baseDenom,
+		), nil
+	}},
+	{"Proposal", func(params ProjectionParams) (projection_entity.Projection, error) {
+		baseDenom, err := stringOption(params.Options, "base_denom", params.Config.Blockchain.BaseDenom)
+		if err != nil {
+			return nil, err
+		}
+		return proposal.NewProposal(params.Logger, params.RDbConn, baseDenom), nil
+	}},
+}
+
+// builtinInMemoryProjections are the built-in projections keeping their states in memory, which run
+// without database in in-memory mode
+var builtinInMemoryProjections = []registeredProjection{
+	{"InMemoryBlock", func(params ProjectionParams) (projection_entity.Projection, error) {
+		return inmemoryblock.NewInMemoryBlock(params.Logger), nil
+	}},
+}
+
+// projectionRegistry returns the built-in projections followed by the extra projections registered to
+// the app, in the order they are run
+func (app *App) projectionRegistry() []registeredProjection {
+	registry := make([]registeredProjection, 0, len(builtinProjections)+len(app.extraProjections))
+	registry = append(registry, builtinProjections...)
+	return append(registry, app.extraProjections...)
+}
+
+// inMemoryProjectionRegistry returns the built-in in-memory projections followed by the extra in-memory
+// projections registered to the app, in the order they are run
+func (app *App) inMemoryProjectionRegistry() []registeredProjection {
+	registry := make(
+		[]registeredProjection, 0, len(builtinInMemoryProjections)+len(app.extraInMemoryProjections),
+	)
+	registry = append(registry, builtinInMemoryProjections...)
+	return append(registry, app.extraInMemoryProjections...)
+}
+
+// enabledProjectionIds returns the Ids of the projections enabled in config, or all the registered
+// projections when none is specified
+func (app *App) enabledProjectionIds(config *Config) []string {
+	if len(config.Projection.Enables) > 0 {
+		return config.Projection.Enables
+	}
+
+	return registeredProjectionIds(app.projectionRegistry())
+}
+
+// initProjections constructs the projections enabled in config in the registry order
+func (app *App) initProjections(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config *Config,
+) ([]projection_entity.Projection, error) {
+	return initRegisteredProjections(
+		logger, rdbConn, config, app.projectionRegistry(), app.enabledProjectionIds(config),
+	)
+}
+
+// initInMemoryProjections constructs the in-memory projections enabled in config in the registry
+// order, or all of them when none is specified. Projections requiring database cannot be enabled.
+func (app *App) initInMemoryProjections(
+	logger applogger.Logger,
+	config *Config,
+) ([]projection_entity.Projection, error) {
+	registry := app.inMemoryProjectionRegistry()
+	enabledProjectionIds := config.Projection.Enables
+	if len(enabledProjectionIds) == 0 {
+		enabledProjectionIds = registeredProjectionIds(registry)
+	}
+	for _, projectionId := range enabledProjectionIds {
+		if findProjectionIn(registry, projectionId) == nil && app.findRegisteredProjection(projectionId) != nil {
+			return nil, fmt.Errorf("projection `%s` requires database and cannot run in in-memory mode", projectionId)
+		}
+	}
+
+	return initRegisteredProjections(logger, nil, config, registry, enabledProjectionIds)
+}
+
+// initRegisteredProjections constructs the enabled projections in the registry order
+func initRegisteredProjections(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config *Config,
+	registry []registeredProjection,
+	enabledProjectionIds []string,
+) ([]projection_entity.Projection, error) {
+	enabledIds := make(map[string]bool)
+	for _, projectionId := range enabledProjectionIds {
+		if findProjectionIn(registry, projectionId) == nil {
+			return nil, fmt.Errorf("unknown projection `%s` in config", projectionId)
+		}
+		enabledIds[projectionId] = true
+	}
+	for projectionId := range config.Projection.Options {
+		if !enabledIds[projectionId] {
+			return nil, fmt.Errorf("options of projection `%s` are given but it is not enabled", projectionId)
+		}
+	}
+
+	projections := make([]projection_entity.Projection, 0, len(enabledIds))
+	for _, registered := range registry {
+		if !enabledIds[registered.id] {
+			continue
+		}
+
+		projection, err := registered.constructor(ProjectionParams{
+			Logger:  logger,
+			RDbConn: rdbConn,
+			Config:  config,
+			Options: config.Projection.Options[registered.id],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error constructing projection `%s`: %v", registered.id, err)
+		}
+		projections = append(projections, projection)
+	}
+
+	return projections, nil
+}
+
+func (app *App) findRegisteredProjection(projectionId string) *registeredProjection {
+	return findProjectionIn(app.projectionRegistry(), projectionId)
+}
+
+func findProjectionIn(registry []registeredProjection, projectionId string) *registeredProjection {
+	for _, registered := range registry {
+		if registered.id == projectionId {
+			return &registered
+		}
+	}
+	return nil
+}
+
+func registeredProjectionIds(registry []registeredProjection) []string {
+	projectionIds := make([]string, 0, len(registry))
+	for _, registered := range registry {
+		projectionIds = append(projectionIds, registered.id)
+	}
+	return projectionIds
+}
+
+// stringOption returns the string option of the projection, or the default value when it is not given
+func stringOption(options map[string]interface{}, key string, defaultValue string) (string, error) {
+	value, ok := options[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	stringValue, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("option `%s` must be a string", key)
+	}
+	return stringValue, nil
+}
+
+// stringsOption returns the string list option of the projection, or the default value when it is not
+// given
+func stringsOption(options map[string]interface{}, key string, defaultValue []string) ([]string, error) {
+	value, ok := options[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("option `%s` must be a list of strings", key)
+	}
+	stringValues := make([]string, 0, len(values))
+	for _, element := range values {
+		stringValue, ok := element.(string)
+		if !ok {
+			return nil, fmt.Errorf("option `%s` must be a list of strings", key)
+		}
+		stringValues = append(stringValues, stringValue)
+	}
+	return stringValues, nil
+}
+
+// onInitProjections initializes the projections before any event is handled, which applies the
+// migrations of their owned tables. A projection failing to initialize is left out, so that none of
+// its events is handled until it is initialized on next start.
+func onInitProjections(
+	logger applogger.Logger,
+	projections []projection_entity.Projection,
+) []projection_entity.Projection {
+	initializedProjections := make([]projection_entity.Projection, 0, len(projections))
+	for _, projection := range projections {
+		if err := projection.OnInit(); err != nil {
+			logger.Errorf("error initializing projection `%s`, it is not run until next start: %v", projection.Id(), err)
+			continue
+		}
+		initializedProjections = append(initializedProjections, projection)
+	}
+
+	return initializedProjections
+}
+
+// appendVersionBuilders appends the projections building the latest version of the versioned
+// projections, which are swapped in once they have caught up with the live ones
+func appendVersionBuilders(
+	logger applogger.Logger,
+	projections []projection_entity.Projection,
+) ([]projection_entity.Projection, error) {
+	versionBuilders := make([]projection_entity.Projection, 0)
+	for _, projection := range projections {
+		versionedProjection, ok := projection.(projection_entity.Versioned)
+		if !ok {
+			continue
+		}
+
+		versionBuilder, err := versionedProjection.NewVersionBuilder()
+		if err != nil {
+			return nil, fmt.Errorf("error creating version builder of projection `%s`: %v", projection.Id(), err)
+		}
+		if versionBuilder == nil {
+			continue
+		}
+		logger.Infof(
+			"building projection `%s` alongside the live `%s`", versionBuilder.Id(), versionBuilder.LiveProjectionId(),
+		)
+		versionBuilders = append(versionBuilders, versionBuilder)
+	}
+
+	return append(projections, versionBuilders...), nil
+}
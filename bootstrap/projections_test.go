@@ -0,0 +1,152 @@
+package bootstrap
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdb_test "github.com/crypto-com/chain-indexing/appinterface/rdb/test"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	projection_test "github.com/crypto-com/chain-indexing/entity/projection/test"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+)
+
+var _ = Describe("Projection registry", func() {
+	var app *App
+	var anyExtraProjectionParams *ProjectionParams
+
+	BeforeEach(func() {
+		app = NewApp()
+		anyExtraProjectionParams = nil
+		Expect(app.RegisterProjection(
+			"AnyExtraProjection",
+			func(params ProjectionParams) (projection_entity.Projection, error) {
+				anyExtraProjectionParams = &params
+				mockProjection := projection_test.NewMockProjection()
+				mockProjection.On("Id").Return("AnyExtraProjection")
+				return mockProjection, nil
+			},
+		)).To(Succeed())
+	})
+
+	newConfig := func() *Config {
+		config := &Config{}
+		config.CosmosApp.HTTPRPCUL = "http://127.0.0.1:1317"
+		config.Blockchain.BaseDenom = "basetcro"
+		config.Blockchain.ConNodeAddressPrefix = "tcrocnclcons"
+		return config
+	}
+
+	It("should construct the built-in projections followed by the registered projections when none is enabled", func() {
+		config := newConfig()
+
+		projections, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(BeNil())
+		Expect(projectionIds(projections)).To(Equal([]string{
+			"Block",
+			"Transaction",
+			"BlockEvent",
+			"Validator",
+			"ValidatorStats",
+			"AccountMessage",
+			"Account",
+			"Proposal",
+			"AnyExtraProjection",
+		}))
+		Expect(app.enabledProjectionIds(config)).To(Equal(projectionIds(projections)))
+	})
+
+	It("should construct only the projections enabled in config in the registry order", func() {
+		config := newConfig()
+		config.Projection.Enables = []string{"AnyExtraProjection", "Transaction", "Block"}
+
+		projections, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(BeNil())
+		Expect(projectionIds(projections)).To(Equal([]string{"Block", "Transaction", "AnyExtraProjection"}))
+		Expect(app.enabledProjectionIds(config)).To(Equal(config.Projection.Enables))
+	})
+
+	It("should construct the projection with its options in config", func() {
+		config := newConfig()
+		config.Projection.Enables = []string{"AnyExtraProjection"}
+		config.Projection.Options = map[string]map[string]interface{}{
+			"AnyExtraProjection": {
+				"any_option": "any_value",
+			},
+		}
+
+		_, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(BeNil())
+		Expect(anyExtraProjectionParams).NotTo(BeNil())
+		Expect(anyExtraProjectionParams.Options).To(Equal(map[string]interface{}{
+			"any_option": "any_value",
+		}))
+		Expect(anyExtraProjectionParams.Config).To(Equal(config))
+	})
+
+	It("should return Error when an enabled projection is not registered", func() {
+		config := newConfig()
+		config.Projection.Enables = []string{"Block", "UnknownProjection"}
+
+		_, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(MatchError("unknown projection `UnknownProjection` in config"))
+	})
+
+	It("should return Error when the options of a projection which is not enabled are given", func() {
+		config := newConfig()
+		config.Projection.Enables = []string{"Block"}
+		config.Projection.Options = map[string]map[string]interface{}{
+			"Account": {
+				"base_denom": "basetcro",
+			},
+		}
+
+		_, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(MatchError("options of projection `Account` are given but it is not enabled"))
+	})
+
+	It("should return Error when an option of a built-in projection has the wrong type", func() {
+		config := newConfig()
+		config.Projection.Enables = []string{"Account"}
+		config.Projection.Options = map[string]map[string]interface{}{
+			"Account": {
+				"cosmosapp_http_rpc_urls": "http://127.0.0.1:1317",
+			},
+		}
+
+		_, err := app.initProjections(NewFakeLogger(), rdb_test.NewFakeRDbConn(), config)
+		Expect(err).To(MatchError(
+			"error constructing projection `Account`: option `cosmosapp_http_rpc_urls` must be a list of strings",
+		))
+	})
+
+	It("should return Error when a projection Id is registered twice", func() {
+		Expect(app.RegisterProjection(
+			"Block",
+			func(params ProjectionParams) (projection_entity.Projection, error) {
+				return projection_test.NewFakeProjection(), nil
+			},
+		)).To(MatchError("projection `Block` already registered"))
+		Expect(app.RegisterInMemoryProjection(
+			"AnyExtraProjection",
+			func(params ProjectionParams) (projection_entity.Projection, error) {
+				return projection_test.NewFakeProjection(), nil
+			},
+		)).To(MatchError("projection `AnyExtraProjection` already registered"))
+	})
+
+	It("should return Error when a projection requiring database is enabled in in-memory mode", func() {
+		config := newConfig()
+		config.Projection.Enables = []string{"InMemoryBlock", "Block"}
+
+		_, err := app.initInMemoryProjections(NewFakeLogger(), config)
+		Expect(err).To(MatchError("projection `Block` requires database and cannot run in in-memory mode"))
+	})
+})
+
+func projectionIds(projections []projection_entity.Projection) []string {
+	ids := make([]string, 0, len(projections))
+	for _, projection := range projections {
+		ids = append(ids, projection.Id())
+	}
+	return ids
+}
@@ -1,15 +1,14 @@
-package main
+package bootstrap
 
 import (
 	"fmt"
 	"time"
 
-	"github.com/crypto-com/chain-indexing/appinterface/rdb"
 	"github.com/crypto-com/chain-indexing/infrastructure/pg"
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
 )
 
-func SetupRDbConn(config *Config, logger applogger.Logger) (rdb.Conn, error) {
+func SetupRDbConn(config *Config, logger applogger.Logger) (*pg.PgxConn, error) {
 	var pgxConnPool *pg.PgxConn
 	var err error
 
@@ -29,14 +28,7 @@ func SetupRDbConn(config *Config, logger applogger.Logger) (rdb.Conn, error) {
 
 	for pgxConnPool == nil {
 		pgxConnPool, err = pg.NewPgxConnPool(&pg.PgxConnPoolConfig{
-			ConnConfig: pg.ConnConfig{
-				Host:          config.Database.Host,
-				Port:          config.Database.Port,
-				MaybeUsername: &config.Database.Username,
-				MaybePassword: &config.Database.Password,
-				Database:      config.Database.Name,
-				SSL:           config.Database.SSL,
-			},
+			ConnConfig:             newPgConnConfig(config),
 			MaybeMaxConns:          &config.Postgres.MaxConns,
 			MaybeMinConns:          &config.Postgres.MinConns,
 			MaybeMaxConnLifeTime:   &maxConnLifeTime,
@@ -53,3 +45,14 @@ func SetupRDbConn(config *Config, logger applogger.Logger) (rdb.Conn, error) {
 	logger.Info("successfully setup database connection")
 	return pgxConnPool, nil
 }
+
+func newPgConnConfig(config *Config) pg.ConnConfig {
+	return pg.ConnConfig{
+		Host:          config.Database.Host,
+		Port:          config.Database.Port,
+		MaybeUsername: &config.Database.Username,
+		MaybePassword: &config.Database.Password,
+		Database:      config.Database.Name,
+		SSL:           config.Database.SSL,
+	}
+}
@@ -0,0 +1,223 @@
+package bootstrap
+
+import (
+	"fmt"
+	"sync"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	command_entity "github.com/crypto-com/chain-indexing/entity/command"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	"github.com/crypto-com/chain-indexing/internal/tmcosmosutils"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	command_usecase "github.com/crypto-com/chain-indexing/usecase/command"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+const BOOTSTRAP_PROGRESS_LOG_INTERVAL = 1000
+
+// BOOTSTRAP_BALANCE_CONCURRENCY is the number of account balances requested at a time
+const BOOTSTRAP_BALANCE_CONCURRENCY = 10
+
+// StateBootstrapper creates the commands bootstrapping validators and accounts from the Cosmos app
+// state at a height, so that indexing can start after genesis on nodes without the early blocks
+type StateBootstrapper struct {
+	logger applogger.Logger
+	client cosmosapp_interface.Client
+
+	baseDenom            string
+	accountAddressPrefix string
+}
+
+func NewStateBootstrapper(
+	logger applogger.Logger,
+	client cosmosapp_interface.Client,
+	baseDenom string,
+	accountAddressPrefix string,
+) *StateBootstrapper {
+	return &StateBootstrapper{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "StateBootstrapper",
+		}),
+		client: client,
+
+		baseDenom:            baseDenom,
+		accountAddressPrefix: accountAddressPrefix,
+	}
+}
+
+// Commands returns the bootstrap commands of the state after the block at height is committed
+func (bootstrapper *StateBootstrapper) Commands(height int64) ([]command_entity.Command, error) {
+	bootstrapper.logger.Infof("bootstrapping state at height %d", height)
+
+	validatorCommands, err := bootstrapper.validatorCommands(height)
+	if err != nil {
+		return nil, err
+	}
+	accountCommands, err := bootstrapper.accountCommands(height)
+	if err != nil {
+		return nil, err
+	}
+
+	bootstrapper.logger.Infof(
+		"bootstrapped %d validators and %d accounts at height %d",
+		len(validatorCommands), len(accountCommands), height,
+	)
+	return append(validatorCommands, accountCommands...), nil
+}
+
+func (bootstrapper *StateBootstrapper) validatorCommands(height int64) ([]command_entity.Command, error) {
+	validators, err := bootstrapper.client.Validators(height)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting validators at height %d: %v", height, err)
+	}
+	validatorSet, err := bootstrapper.client.ValidatorSet(height)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting validator set at height %d: %v", height, err)
+	}
+	powers := make(map[string]string, len(validatorSet))
+	for _, validator := range validatorSet {
+		powers[validator.PubKey.Key] = validator.VotingPower
+	}
+
+	commands := make([]command_entity.Command, 0, len(validators))
+	for _, validator := range validators {
+		initialDelegatorAddress, err := tmcosmosutils.AccountAddressFromValidatorAddress(
+			bootstrapper.accountAddressPrefix, validator.OperatorAddress,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error converting validator %s to account address: %v", validator.OperatorAddress, err)
+		}
+		// validators out of the validator set have no voting power
+		power, ok := powers[validator.ConsensusPubkey.Key]
+		if !ok {
+			power = "0"
+		}
+
+		commands = append(commands, command_usecase.NewBootstrapValidator(height, model.ValidatorBootstrapParams{
+			OperatorAddress:         validator.OperatorAddress,
+			InitialDelegatorAddress: initialDelegatorAddress,
+			TendermintPubkey:        validator.ConsensusPubkey.Key,
+			Status:                  validator.Status,
+			Jailed:                  validator.Jailed,
+			Power:                   power,
+			Tokens:                  validator.Tokens,
+			Description: model.MsgValidatorDescription{
+				Moniker:         validator.Description.Moniker,
+				Identity:        validator.Description.Identity,
+				Website:         validator.Description.Website,
+				SecurityContact: validator.Description.SecurityContact,
+				Details:         validator.Description.Details,
+			},
+			Commission: model.MsgValidatorCommission{
+				Rate:          validator.Commission.CommissionRates.Rate,
+				MaxRate:       validator.Commission.CommissionRates.MaxRate,
+				MaxChangeRate: validator.Commission.CommissionRates.MaxChangeRate,
+			},
+			MinSelfDelegation: validator.MinSelfDelegation,
+		}))
+	}
+
+	return commands, nil
+}
+
+func (bootstrapper *StateBootstrapper) accountCommands(height int64) ([]command_entity.Command, error) {
+	accounts, err := bootstrapper.client.Accounts(height)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting accounts at height %d: %v", height, err)
+	}
+
+	addresses := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		addresses = append(addresses, account.AccountAddress)
+	}
+	balances, err := bootstrapper.baseDenomBalances(addresses, height)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]command_entity.Command, 0, len(accounts))
+	for i, account := range accounts {
+		commands = append(commands, command_usecase.NewBootstrapAccount(height, model.AccountBootstrapParams{
+			AccountType:    account.AccountType,
+			AccountAddress: account.AccountAddress,
+			Pubkey:         account.Pubkey,
+			AccountNumber:  account.AccountNumber,
+			SequenceNumber: account.SequenceNumber,
+			Balance:        balances[i],
+		}))
+	}
+
+	return commands, nil
+}
+
+// baseDenomBalances requests the base denom balances of the addresses with BOOTSTRAP_BALANCE_CONCURRENCY
+// requests at a time. The balances are returned in the order of the addresses.
+func (bootstrapper *StateBootstrapper) baseDenomBalances(addresses []string, height int64) ([]coin.Coin, error) {
+	balances := make([]coin.Coin, len(addresses))
+
+	indexCh := make(chan int)
+	errCh := make(chan error, BOOTSTRAP_BALANCE_CONCURRENCY)
+	doneCh := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < BOOTSTRAP_BALANCE_CONCURRENCY; i += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexCh {
+				balance, err := bootstrapper.baseDenomBalance(addresses[index], height)
+				if err != nil {
+					errCh <- err
+					return
+				}
+				balances[index] = balance
+			}
+		}()
+	}
+
+	var balanceErr error
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	for index := 0; index < len(addresses) && balanceErr == nil; index += 1 {
+		select {
+		case indexCh <- index:
+			if (index+1)%BOOTSTRAP_PROGRESS_LOG_INTERVAL == 0 {
+				bootstrapper.logger.Infof("bootstrapping account balances %d/%d", index+1, len(addresses))
+			}
+		case balanceErr = <-errCh:
+		}
+	}
+	close(indexCh)
+	<-doneCh
+
+	if balanceErr != nil {
+		return nil, balanceErr
+	}
+	select {
+	case err := <-errCh:
+		return nil, err
+	default:
+		return balances, nil
+	}
+}
+
+func (bootstrapper *StateBootstrapper) baseDenomBalance(address string, height int64) (coin.Coin, error) {
+	balances, err := bootstrapper.client.Balances(address, height)
+	if err != nil {
+		return coin.Zero(), fmt.Errorf("error requesting balances of %s at height %d: %v", address, height, err)
+	}
+	for _, balance := range balances {
+		if balance.AccountDenom != bootstrapper.baseDenom {
+			continue
+		}
+		amount, err := coin.NewCoinFromString(balance.AccountAmount)
+		if err != nil {
+			return coin.Zero(), fmt.Errorf("error parsing balance of %s: %v", address, err)
+		}
+		return amount, nil
+	}
+
+	return coin.Zero(), nil
+}
@@ -0,0 +1,100 @@
+package bootstrap_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+var _ = Describe("StateBootstrapper", func() {
+	It("should bootstrap the balance of every account in order", func() {
+		client := newFakeCosmosAppClient(25)
+		bootstrapper := bootstrap.NewStateBootstrapper(NewFakeLogger(), client, "basetcro", "tcro")
+
+		commands, err := bootstrapper.Commands(100)
+		Expect(err).To(BeNil())
+		Expect(commands).To(HaveLen(25))
+		for i, command := range commands {
+			evt, err := command.Exec()
+			Expect(err).To(BeNil())
+			accountBootstrappedEvent := evt.(*event_usecase.AccountBootstrapped)
+			Expect(accountBootstrappedEvent.AccountAddress).To(Equal(fakeAccountAddress(i)))
+			Expect(accountBootstrappedEvent.Balance.String()).To(Equal(strconv.Itoa(i)))
+		}
+	})
+
+	It("should return Error when any balance cannot be requested", func() {
+		client := newFakeCosmosAppClient(25)
+		client.failingAddress = fakeAccountAddress(13)
+		bootstrapper := bootstrap.NewStateBootstrapper(NewFakeLogger(), client, "basetcro", "tcro")
+
+		_, err := bootstrapper.Commands(100)
+		Expect(err).To(MatchError(
+			fmt.Sprintf("error requesting balances of %s at height 100: connection refused", fakeAccountAddress(13)),
+		))
+	})
+})
+
+func fakeAccountAddress(index int) string {
+	return fmt.Sprintf("tcro%d", index)
+}
+
+// fakeCosmosAppClient has accountCount accounts, the base denom balance of each account is its index
+type fakeCosmosAppClient struct {
+	cosmosapp_interface.Client
+
+	accountCount   int
+	failingAddress string
+}
+
+func newFakeCosmosAppClient(accountCount int) *fakeCosmosAppClient {
+	return &fakeCosmosAppClient{
+		accountCount: accountCount,
+	}
+}
+
+func (client *fakeCosmosAppClient) Validators(_ int64) ([]cosmosapp_interface.Validator, error) {
+	return []cosmosapp_interface.Validator{}, nil
+}
+
+func (client *fakeCosmosAppClient) ValidatorSet(_ int64) ([]cosmosapp_interface.ValidatorSetValidator, error) {
+	return []cosmosapp_interface.ValidatorSetValidator{}, nil
+}
+
+func (client *fakeCosmosAppClient) Accounts(_ int64) ([]cosmosapp_interface.Account, error) {
+	accounts := make([]cosmosapp_interface.Account, 0, client.accountCount)
+	for i := 0; i < client.accountCount; i += 1 {
+		accounts = append(accounts, cosmosapp_interface.Account{
+			AccountType:    "/cosmos.auth.v1beta1.BaseAccount",
+			AccountAddress: fakeAccountAddress(i),
+			AccountNumber:  strconv.Itoa(i),
+			SequenceNumber: "0",
+		})
+	}
+	return accounts, nil
+}
+
+func (client *fakeCosmosAppClient) Balances(
+	accountAddress string, _ int64,
+) ([]cosmosapp_interface.AccountBalance, error) {
+	if accountAddress == client.failingAddress {
+		return nil, errors.New("connection refused")
+	}
+
+	var index int
+	if _, err := fmt.Sscanf(accountAddress, "tcro%d", &index); err != nil {
+		return nil, err
+	}
+	return []cosmosapp_interface.AccountBalance{
+		{AccountAmount: "1", AccountDenom: "ibc/ANY_DENOM"},
+		{AccountAmount: strconv.Itoa(index), AccountDenom: "basetcro"},
+	}, nil
+}
@@ -0,0 +1,163 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	eventhandler_interface "github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/infrastructure"
+	cosmosapp_infrastructure "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+	"github.com/crypto-com/chain-indexing/infrastructure/pg"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	"github.com/crypto-com/chain-indexing/usecase/parser"
+)
+
+func (app *App) newSyncCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "sync",
+		Usage: "Fetch a height range from Tendermint into the event store, replacing the stored events",
+		Flags: heightRangeFlags(),
+		Action: func(ctx *cli.Context) error {
+			fromHeight, toHeight, err := parseHeightRange(ctx)
+			if err != nil {
+				return err
+			}
+			rangeCtx, err := app.newHeightRangeContext(ctx)
+			if err != nil {
+				return err
+			}
+			defer rangeCtx.Close()
+
+			shutdownCtx, shutdown := newShutdownContext(rangeCtx.logger)
+			defer shutdown()
+
+			rangeCtx.logger.Infof("going to synchronize blocks from %d to %d", fromHeight, toHeight)
+			if err = rangeCtx.syncManager.FetchRange(
+				shutdownCtx, fromHeight, toHeight, rangeCtx.eventStoreHandler.ReplaceEvents,
+			); err != nil {
+				return err
+			}
+			rangeCtx.logger.Infof("successfully synced blocks from %d to %d", fromHeight, toHeight)
+
+			return nil
+		},
+	}
+}
+
+func heightRangeFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.Int64Flag{
+			Name:     "from",
+			Usage:    "First block height of the range. Height 0 is the genesis",
+			Required: true,
+		},
+		&cli.Int64Flag{
+			Name:     "to",
+			Usage:    "Last block height of the range",
+			Required: true,
+		},
+	}
+}
+
+func parseHeightRange(ctx *cli.Context) (int64, int64, error) {
+	fromHeight := ctx.Int64("from")
+	toHeight := ctx.Int64("to")
+	if fromHeight < 0 {
+		return 0, 0, fmt.Errorf("invalid from height %d", fromHeight)
+	}
+	if fromHeight > toHeight {
+		return 0, 0, fmt.Errorf("from height %d is after to height %d", fromHeight, toHeight)
+	}
+
+	return fromHeight, toHeight, nil
+}
+
+// heightRangeContext holds the dependencies to fetch a height range into the event store without
+// running the index service and HTTP API
+type heightRangeContext struct {
+	logger            applogger.Logger
+	rdbConn           *pg.PgxConn
+	eventRegistry     *event.Registry
+	eventStoreHandler *eventhandler_interface.RDbEventStoreHandler
+	syncManager       *SyncManager
+}
+
+func (app *App) newHeightRangeContext(ctx *cli.Context) (*heightRangeContext, error) {
+	if !ctx.IsSet("dbPassword") {
+		return nil, errors.New("Required flag \"dbPassword\" not set")
+	}
+
+	config, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(parseLogLevel(config.Logger.Level))
+
+	if len(config.Tendermint.AllHTTPRPCURLs()) == 0 && config.Tendermint.ArchivePath == "" {
+		return nil, errors.New("missing tendermint http_rpc_url")
+	}
+	resilientSyncParams, err := parseResilientSyncParams(&config.Sync)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing resilient sync config: %v", err)
+	}
+
+	rdbConn, err := SetupRDbConn(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up RDb connection: %v", err)
+	}
+
+	eventRegistry := app.newEventRegistry()
+	eventStoreHandler := eventhandler_interface.NewRDbEventStoreHandler(logger, rdbConn, eventRegistry)
+
+	var stateBootstrapper *StateBootstrapper
+	if isAfterGenesis(config.Sync.StartHeight) {
+		stateBootstrapper = NewStateBootstrapper(
+			logger,
+			cosmosapp_infrastructure.NewMultiEndpointHTTPClient(config.CosmosApp.AllHTTPRPCURLs()),
+			config.Blockchain.BaseDenom,
+			config.Blockchain.AccountAddressPrefix,
+		)
+	}
+	syncManager, err := NewSyncManager(
+		SyncManagerParams{
+			Logger:            logger,
+			RDbConn:           rdbConn,
+			TxDecoder:         parser.NewTxDecoder(config.Blockchain.BaseDenom),
+			StateBootstrapper: stateBootstrapper,
+			Config: SyncManagerConfig{
+				WindowSize:        config.Sync.WindowSize,
+				Strategy:          config.Sync.Strategy,
+				ResilientParams:   resilientSyncParams,
+				TendermintRPCUrls: config.Tendermint.AllHTTPRPCURLs(),
+				ArchivePath:       config.Tendermint.ArchivePath,
+				CacheDir:          config.Tendermint.CacheDir,
+				CacheMaxSize:      config.Tendermint.CacheMaxSize * 1024 * 1024,
+				StartHeight:       config.Sync.StartHeight,
+				MaxReorgDepth:     config.Sync.MaxReorgDepth,
+			},
+		},
+		eventStoreHandler,
+	)
+	if err != nil {
+		_ = rdbConn.Close()
+		return nil, fmt.Errorf("error creating sync manager: %v", err)
+	}
+
+	return &heightRangeContext{
+		logger:            logger,
+		rdbConn:           rdbConn,
+		eventRegistry:     eventRegistry,
+		eventStoreHandler: eventStoreHandler,
+		syncManager:       syncManager,
+	}, nil
+}
+
+func (rangeCtx *heightRangeContext) Close() {
+	closeRDbConn(rangeCtx.logger, rangeCtx.rdbConn)
+}
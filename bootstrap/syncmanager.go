@@ -0,0 +1,731 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	eventhandler_interface "github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/polling"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	tendermint_interface "github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	command_entity "github.com/crypto-com/chain-indexing/entity/command"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	projection_entity "github.com/crypto-com/chain-indexing/entity/projection"
+	chainfeed "github.com/crypto-com/chain-indexing/infrastructure/feed/chain"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/parser"
+	"github.com/crypto-com/chain-indexing/usecase/syncstrategy"
+)
+
+const DEFAULT_POLLING_INTERVAL = 5 * time.Second
+
+// DEFAULT_MAX_REORG_DEPTH is the maximum number of blocks rolled back on chain reorganisation when it is
+// not configured
+const DEFAULT_MAX_REORG_DEPTH = int64(1000)
+
+// ErrReorgTooDeep is returned when no handled block within the maximum reorg depth is on the chain. The
+// handled blocks are not rolled back automatically, the indexer stops instead.
+var ErrReorgTooDeep = errors.New("chain reorganisation is deeper than the maximum reorg depth")
+
+type SyncManager struct {
+	rdbConn         rdb.Conn
+	client          tendermint_interface.Client
+	logger          applogger.Logger
+	pollingInterval time.Duration
+	blockFeed       string
+	rpcURL          string
+	websocketURL    string
+
+	// chainClient always requests the chain, it is used to verify indexed blocks against the chain
+	chainClient tendermint_interface.Client
+	// cachedClient is nil unless response cache is enabled
+	cachedClient *tendermint.CachedClient
+	// isReplayingArchive is true when the blocks are replayed from an archive instead of Tendermint
+	isReplayingArchive bool
+	// maxReorgDepth is the maximum number of blocks rolled back on chain reorganisation
+	maxReorgDepth int64
+
+	txDecoder    *parser.TxDecoder
+	syncStrategy syncstrategy.Strategy
+
+	// startHeight is the first block to index when it is after genesis, the state at the block before
+	// is bootstrapped by stateBootstrapper
+	startHeight       int64
+	stateBootstrapper *StateBootstrapper
+	statusView        *polling.Status
+
+	eventHandler eventhandler_interface.Handler
+	// rollbackTargets are rolled back together with the event handler on chain reorganisation
+	rollbackTargets []projection_entity.Rollbackable
+
+	// SyncManager state
+	latestBlockHeight *int64
+	shouldSyncCh      chan bool
+	// lastSyncedBlock is the last block handled by this manager, used to verify parent hash continuity
+	lastSyncedBlock *eventhandler_interface.HandledBlock
+}
+
+type SyncManagerParams struct {
+	Logger applogger.Logger
+	// RDbConn is optional, partial history is not recorded without it
+	RDbConn   rdb.Conn
+	TxDecoder *parser.TxDecoder
+	// RollbackTargets are rolled back after the event handler when a chain reorganisation is detected
+	RollbackTargets []projection_entity.Rollbackable
+	// StateBootstrapper is required when Config.StartHeight is after genesis
+	StateBootstrapper *StateBootstrapper
+
+	Config SyncManagerConfig
+}
+
+type SyncManagerConfig struct {
+	WindowSize int
+	// Strategy is either SYNC_STRATEGY_WINDOW or SYNC_STRATEGY_RESILIENT
+	Strategy          string
+	ResilientParams   syncstrategy.ResilientParams
+	TendermintRPCUrls []string
+	// BlockFeed is either BLOCK_FEED_POLLING or BLOCK_FEED_WEBSOCKET
+	BlockFeed string
+	// WebSocketURL is optional, default to the `/websocket` endpoint of the first TendermintRPCUrls
+	WebSocketURL string
+	// ArchivePath replays blocks from the archive instead of requesting Tendermint when set. Block feed
+	// is always polling in this case.
+	ArchivePath string
+	// CacheDir enables caching raw Tendermint responses on disk when set
+	CacheDir string
+	// CacheMaxSize is the size cap of response cache in bytes, non-positive means unlimited
+	CacheMaxSize int64
+	// StartHeight is the first block to index. When it is after the first block, the state at the
+	// block before is bootstrapped instead of indexing from genesis.
+	StartHeight int64
+	// MaxReorgDepth is the maximum number of blocks rolled back on chain reorganisation, non-positive
+	// means DEFAULT_MAX_REORG_DEPTH
+	MaxReorgDepth int64
+}
+
+// NewSyncManager creates a new feed with polling for latest block starts at a specific height
+func NewSyncManager(
+	params SyncManagerParams,
+	eventHandler eventhandler_interface.Handler,
+) (*SyncManager, error) {
+	if isAfterGenesis(params.Config.StartHeight) && params.StateBootstrapper == nil {
+		return nil, fmt.Errorf("state bootstrapper is required to start from height %d", params.Config.StartHeight)
+	}
+
+	var tendermintClient tendermint_interface.Client
+	var chainClient tendermint_interface.Client
+	var cachedClient *tendermint.CachedClient
+	blockFeed := params.Config.BlockFeed
+	rpcURL := ""
+	if params.Config.ArchivePath != "" {
+		fileClient, err := tendermint.NewFileClient(params.Config.ArchivePath)
+		if err != nil {
+			return nil, fmt.Errorf("error opening tendermint archive: %v", err)
+		}
+		manifest := fileClient.Manifest()
+		params.Logger.Infof(
+			"replaying chain %s blocks %d-%d from archive %s",
+			manifest.ChainID, manifest.FromHeight, manifest.ToHeight, params.Config.ArchivePath,
+		)
+
+		tendermintClient = fileClient
+		chainClient = fileClient
+		blockFeed = BLOCK_FEED_POLLING
+	} else {
+		httpClient := tendermint.NewMultiEndpointHTTPClient(params.Config.TendermintRPCUrls)
+		tendermintClient = httpClient
+		chainClient = httpClient
+		rpcURL = params.Config.TendermintRPCUrls[0]
+
+		if params.Config.CacheDir != "" {
+			responseCache, err := tendermint.NewResponseCache(params.Config.CacheDir, params.Config.CacheMaxSize)
+			if err != nil {
+				return nil, fmt.Errorf("error opening tendermint response cache: %v", err)
+			}
+			cachedClient = tendermint.NewCachedClient(params.Logger, httpClient, responseCache)
+			tendermintClient = cachedClient
+		}
+	}
+
+	// partial history is not recorded without database
+	var statusView *polling.Status
+	if params.RDbConn != nil {
+		statusView = polling.NewStatus(params.RDbConn.ToHandle())
+	}
+
+	maxReorgDepth := params.Config.MaxReorgDepth
+	if maxReorgDepth <= 0 {
+		maxReorgDepth = DEFAULT_MAX_REORG_DEPTH
+	}
+
+	return &SyncManager{
+		rdbConn: params.RDbConn,
+		client:  tendermintClient,
+		logger: params.Logger.WithFields(applogger.LogFields{
+			"module": "SyncManager",
+		}),
+		pollingInterval: DEFAULT_POLLING_INTERVAL,
+		blockFeed:       blockFeed,
+		rpcURL:          rpcURL,
+		websocketURL:    params.Config.WebSocketURL,
+
+		shouldSyncCh: make(chan bool, 1),
+
+		chainClient:        chainClient,
+		cachedClient:       cachedClient,
+		isReplayingArchive: params.Config.ArchivePath != "",
+		maxReorgDepth:      maxReorgDepth,
+
+		txDecoder:    params.TxDecoder,
+		syncStrategy: newSyncStrategy(params.Logger, &params.Config),
+
+		startHeight:       params.Config.StartHeight,
+		stateBootstrapper: params.StateBootstrapper,
+		statusView:        statusView,
+
+		eventHandler:    eventHandler,
+		rollbackTargets: params.RollbackTargets,
+	}, nil
+}
+
+func newSyncStrategy(logger applogger.Logger, config *SyncManagerConfig) syncstrategy.Strategy {
+	if config.Strategy == SYNC_STRATEGY_RESILIENT {
+		return syncstrategy.NewResilient(logger, config.WindowSize, config.ResilientParams)
+	}
+
+	return syncstrategy.NewWindow(logger, config.WindowSize)
+}
+
+// SyncBlocks makes request to tendermint, create and dispatch notifications. When the context is
+// cancelled, it returns after the in-flight window is handled.
+func (manager *SyncManager) SyncBlocks(ctx context.Context, latestHeight int64) error {
+	maybeLastIndexedHeight, err := manager.eventHandler.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error running GetLastIndexedBlockHeight %v", err)
+	}
+
+	// if none of the block has been indexed before, start with 0
+	currentIndexingHeight := int64(0)
+	if maybeLastIndexedHeight != nil {
+		currentIndexingHeight = *maybeLastIndexedHeight + 1
+
+		// the node may have been replaced or rolled back since last sync, verify the last indexed
+		// block before appending to it
+		if err = manager.verifyLastIndexedBlock(*maybeLastIndexedHeight); err != nil {
+			var reorgErr *ChainReorganisedError
+			if !errors.As(err, &reorgErr) {
+				return fmt.Errorf("error verifying last indexed block: %w", err)
+			}
+			if err = manager.rollbackTo(reorgErr); err != nil {
+				return err
+			}
+			currentIndexingHeight = reorgErr.LastMatchingHeight + 1
+		}
+	} else if isAfterGenesis(manager.startHeight) {
+		// the blocks before start height may not be available, the state is bootstrapped instead
+		if err = manager.bootstrapState(); err != nil {
+			return err
+		}
+		currentIndexingHeight = manager.startHeight
+	}
+
+	// the latest block of Tendermint is synchronized once the next block is produced, while the last
+	// block of an archive is final and there is no next block to wait for
+	lastSyncHeight := latestHeight - 1
+	if manager.isReplayingArchive {
+		lastSyncHeight = latestHeight
+	}
+
+	manager.logger.Infof("going to synchronized blocks from %d to %d", currentIndexingHeight, latestHeight)
+	for currentIndexingHeight <= lastSyncHeight {
+		if ctx.Err() != nil {
+			manager.logger.Infof("stop synchronizing blocks at height %d on shutdown", currentIndexingHeight)
+			return nil
+		}
+
+		blocksCommands, syncedHeight, err := manager.syncStrategy.Sync(
+			currentIndexingHeight, latestHeight, manager.syncBlockWorker,
+		)
+		if err != nil {
+			return fmt.Errorf("error when synchronizing block with sync strategy: %v", err)
+		}
+
+		var reorgErr *ChainReorganisedError
+		for i, commands := range blocksCommands {
+			blockHeight := currentIndexingHeight + int64(i)
+
+			events, err := execCommands(commands)
+			if err != nil {
+				return err
+			}
+
+			maybeSyncedBlock := findRawBlock(blockHeight, events)
+			if err = manager.verifyParentBlock(blockHeight, maybeSyncedBlock); err != nil {
+				if !errors.As(err, &reorgErr) {
+					return fmt.Errorf("error verifying parent block of height %d: %w", blockHeight, err)
+				}
+				break
+			}
+
+			err = manager.eventHandler.HandleEvents(blockHeight, events)
+			if err != nil {
+				return fmt.Errorf("error handling events: %v", err)
+			}
+			manager.lastSyncedBlock = maybeSyncedBlock
+		}
+
+		if reorgErr != nil {
+			if err = manager.rollbackTo(reorgErr); err != nil {
+				return err
+			}
+			currentIndexingHeight = reorgErr.LastMatchingHeight + 1
+			continue
+		}
+
+		// If there is any error before, short-circuit return in the error handling
+		// while the local currentIndexingHeight won't be incremented and will be retried later
+		manager.logger.Infof("successfully synced to block height %d", syncedHeight)
+		currentIndexingHeight = syncedHeight + 1
+	}
+	return nil
+}
+
+// bootstrapState handles the bootstrapped state at the block before start height in place of the
+// blocks from genesis, and marks the indexed history as partial
+func (manager *SyncManager) bootstrapState() error {
+	if manager.statusView != nil {
+		if err := manager.statusView.Insert(
+			polling.PARTIAL_HISTORY_START_HEIGHT, strconv.FormatInt(manager.startHeight, 10),
+		); err != nil {
+			return fmt.Errorf("error marking partial history: %v", err)
+		}
+	}
+
+	bootstrapHeight := manager.startHeight - 1
+	events, err := manager.FetchBlockEvents(bootstrapHeight)
+	if err != nil {
+		return fmt.Errorf("error bootstrapping state at height %d: %v", bootstrapHeight, err)
+	}
+	if err = manager.eventHandler.HandleEvents(bootstrapHeight, events); err != nil {
+		return fmt.Errorf("error handling bootstrapped state events: %v", err)
+	}
+
+	return nil
+}
+
+// isBootstrapHeight returns true when the height is where the state is bootstrapped
+func (manager *SyncManager) isBootstrapHeight(height int64) bool {
+	return isAfterGenesis(manager.startHeight) && height == manager.startHeight-1
+}
+
+// isAfterGenesis returns true when indexing from the start height requires bootstrapping the state
+func isAfterGenesis(startHeight int64) bool {
+	return startHeight > 1
+}
+
+// verifyLastIndexedBlock compares the last indexed block with the one on the chain. It returns
+// ChainReorganisedError when they differ.
+func (manager *SyncManager) verifyLastIndexedBlock(height int64) error {
+	if height == int64(0) {
+		return nil
+	}
+
+	handledBlock, err := manager.getHandledBlock(height)
+	if err != nil {
+		return err
+	}
+	if handledBlock == nil {
+		return nil
+	}
+
+	matched, err := manager.isBlockOnChain(handledBlock)
+	if err != nil {
+		return err
+	}
+	if matched {
+		return nil
+	}
+
+	return manager.newChainReorganisedError(height)
+}
+
+// verifyParentBlock checks that the block at height is built on top of the last synced block. It
+// returns ChainReorganisedError when the parent hash does not match.
+func (manager *SyncManager) verifyParentBlock(
+	height int64, maybeBlock *eventhandler_interface.HandledBlock,
+) error {
+	if maybeBlock == nil || maybeBlock.ParentHash == "" {
+		return nil
+	}
+
+	parentBlock := manager.lastSyncedBlock
+	if parentBlock == nil || parentBlock.Height != height-1 {
+		var err error
+		if parentBlock, err = manager.getHandledBlock(height - 1); err != nil {
+			return err
+		}
+	}
+	if parentBlock == nil || parentBlock.Hash == maybeBlock.ParentHash {
+		return nil
+	}
+
+	return manager.newChainReorganisedError(height - 1)
+}
+
+// newChainReorganisedError looks for the last handled block which is still on the chain before the
+// divergent height. It steps back exponentially until a handled block is on the chain, then binary
+// searches between it and the last divergent height. It returns ErrReorgTooDeep when no handled block
+// within the maximum reorg depth is on the chain.
+func (manager *SyncManager) newChainReorganisedError(divergentHeight int64) error {
+	minMatchingHeight := divergentHeight - manager.maxReorgDepth
+	if minMatchingHeight < 0 {
+		minMatchingHeight = 0
+	}
+
+	lowestDivergentHeight := divergentHeight
+	var lastMatchingHeight int64
+	for step := int64(1); ; step *= 2 {
+		height := divergentHeight - step
+		if height < minMatchingHeight {
+			height = minMatchingHeight
+		}
+
+		matched, err := manager.isHandledBlockOnChain(height)
+		if err != nil {
+			return err
+		}
+		if matched {
+			lastMatchingHeight = height
+			break
+		}
+		if height == minMatchingHeight {
+			return fmt.Errorf(
+				"%w: chain diverged at block height %d and none of the %d handled blocks before is on the chain",
+				ErrReorgTooDeep, divergentHeight, manager.maxReorgDepth,
+			)
+		}
+		lowestDivergentHeight = height
+	}
+
+	for lowestDivergentHeight-lastMatchingHeight > 1 {
+		height := lastMatchingHeight + (lowestDivergentHeight-lastMatchingHeight)/2
+		matched, err := manager.isHandledBlockOnChain(height)
+		if err != nil {
+			return err
+		}
+		if matched {
+			lastMatchingHeight = height
+		} else {
+			lowestDivergentHeight = height
+		}
+	}
+
+	return &ChainReorganisedError{
+		DivergentHeight:    divergentHeight,
+		LastMatchingHeight: lastMatchingHeight,
+	}
+}
+
+// isHandledBlockOnChain returns true when the handled block at height is on the chain. Heights without
+// handled block have nothing to compare with and are assumed to be consistent.
+func (manager *SyncManager) isHandledBlockOnChain(height int64) (bool, error) {
+	if height <= 0 {
+		return true, nil
+	}
+
+	handledBlock, err := manager.getHandledBlock(height)
+	if err != nil {
+		return false, err
+	}
+	if handledBlock == nil {
+		return true, nil
+	}
+
+	return manager.isBlockOnChain(handledBlock)
+}
+
+func (manager *SyncManager) getHandledBlock(height int64) (*eventhandler_interface.HandledBlock, error) {
+	if manager.lastSyncedBlock != nil && manager.lastSyncedBlock.Height == height {
+		return manager.lastSyncedBlock, nil
+	}
+
+	rollbackableHandler, ok := manager.eventHandler.(eventhandler_interface.RollbackableHandler)
+	if !ok {
+		return nil, nil
+	}
+	handledBlock, err := rollbackableHandler.GetHandledBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("error getting handled block at height %d: %v", height, err)
+	}
+
+	return handledBlock, nil
+}
+
+func (manager *SyncManager) isBlockOnChain(handledBlock *eventhandler_interface.HandledBlock) (bool, error) {
+	block, _, err := manager.chainClient.Block(handledBlock.Height)
+	if err != nil {
+		return false, fmt.Errorf("error requesting chain block at height %d: %v", handledBlock.Height, err)
+	}
+
+	return block.Hash == handledBlock.Hash && block.AppHash == handledBlock.AppHash, nil
+}
+
+// rollbackTo reverts the event handler and all rollback targets to the last matching height
+func (manager *SyncManager) rollbackTo(reorgErr *ChainReorganisedError) error {
+	manager.logger.WithFields(applogger.LogFields{
+		"divergentHeight":    reorgErr.DivergentHeight,
+		"lastMatchingHeight": reorgErr.LastMatchingHeight,
+	}).Errorf("%v, rolling back", reorgErr)
+
+	rollbackableHandler, ok := manager.eventHandler.(eventhandler_interface.RollbackableHandler)
+	if !ok {
+		return fmt.Errorf("%v: event handler does not support rollback", reorgErr)
+	}
+	if err := rollbackableHandler.RollbackTo(reorgErr.LastMatchingHeight); err != nil {
+		return fmt.Errorf("%v: error rolling back event handler: %v", reorgErr, err)
+	}
+	manager.lastSyncedBlock = nil
+
+	// cached blocks after the last matching height are from the abandoned chain
+	if manager.cachedClient != nil {
+		if err := manager.cachedClient.DeleteAfterHeight(reorgErr.LastMatchingHeight); err != nil {
+			return fmt.Errorf("%v: error deleting cached blocks: %v", reorgErr, err)
+		}
+	}
+
+	for _, target := range manager.rollbackTargets {
+		if err := target.RollbackTo(reorgErr.LastMatchingHeight); err != nil {
+			return fmt.Errorf("%v: error rolling back: %v", reorgErr, err)
+		}
+	}
+
+	manager.logger.Infof("rolled back to block height %d", reorgErr.LastMatchingHeight)
+	return nil
+}
+
+// findRawBlock returns the synced block from the RawBlockCreated event, nil if there is none
+func findRawBlock(height int64, events []event.Event) *eventhandler_interface.HandledBlock {
+	for _, evt := range events {
+		if rawBlockCreatedEvent, ok := evt.(*event_usecase.RawBlockCreated); ok {
+			rawBlock := rawBlockCreatedEvent.RawBlock
+			return &eventhandler_interface.HandledBlock{
+				Height:     height,
+				Hash:       rawBlock.BlockID.Hash,
+				AppHash:    rawBlock.Block.Header.AppHash,
+				ParentHash: rawBlock.Block.Header.LastBlockID.Hash,
+			}
+		}
+	}
+
+	return nil
+}
+
+// ChainReorganisedError is returned when the synced chain diverges from the handled blocks
+type ChainReorganisedError struct {
+	DivergentHeight    int64
+	LastMatchingHeight int64
+}
+
+func (err *ChainReorganisedError) Error() string {
+	return fmt.Sprintf(
+		"chain diverged at block height %d, last matching block height is %d",
+		err.DivergentHeight, err.LastMatchingHeight,
+	)
+}
+
+// FetchBlockEvents fetches the block at height and returns the parsed events. At the bootstrap
+// height, it returns the bootstrapped state events instead.
+func (manager *SyncManager) FetchBlockEvents(blockHeight int64) ([]event.Event, error) {
+	commands, err := manager.fetchBlockCommands(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return execCommands(commands)
+}
+
+// FetchRange fetches the blocks from fromHeight to toHeight inclusively with the sync strategy and
+// calls handle with the events of each block in height order. It stops when the context is cancelled.
+func (manager *SyncManager) FetchRange(
+	ctx context.Context,
+	fromHeight int64,
+	toHeight int64,
+	handle func(blockHeight int64, events []event.Event) error,
+) error {
+	currentHeight := fromHeight
+	for currentHeight <= toHeight {
+		if ctx.Err() != nil {
+			return fmt.Errorf("stopped fetching blocks at height %d: %v", currentHeight, ctx.Err())
+		}
+
+		blocksCommands, syncedHeight, err := manager.syncStrategy.Sync(
+			currentHeight, toHeight, manager.fetchBlockCommands,
+		)
+		if err != nil {
+			return fmt.Errorf("error when synchronizing block with sync strategy: %v", err)
+		}
+
+		for i, commands := range blocksCommands {
+			blockHeight := currentHeight + int64(i)
+
+			events, err := execCommands(commands)
+			if err != nil {
+				return err
+			}
+			if err = handle(blockHeight, events); err != nil {
+				return fmt.Errorf("error handling events at height %d: %v", blockHeight, err)
+			}
+		}
+
+		currentHeight = syncedHeight + 1
+	}
+
+	return nil
+}
+
+// fetchBlockCommands returns the commands of the block at height, or the bootstrap commands at the
+// bootstrap height
+func (manager *SyncManager) fetchBlockCommands(blockHeight int64) ([]command_entity.Command, error) {
+	if manager.isBootstrapHeight(blockHeight) {
+		return manager.stateBootstrapper.Commands(blockHeight)
+	}
+
+	return manager.syncBlockWorker(blockHeight)
+}
+
+func execCommands(commands []command_entity.Command) ([]event.Event, error) {
+	events := make([]event.Event, 0, len(commands))
+	for _, command := range commands {
+		event, err := command.Exec()
+		if err != nil {
+			return nil, fmt.Errorf("error generating event: %v", err)
+		}
+		events = append(events, event)
+	}
+	// events are identified by chain data, so that re-syncing a block produces the same events
+	event.AssignDeterministicIDs(events)
+
+	return events, nil
+}
+
+func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity.Command, error) {
+	logger := manager.logger.WithFields(applogger.LogFields{
+		"submodule":   "SyncBlockWorker",
+		"blockHeight": blockHeight,
+	})
+
+	logger.Info("synchronizing block")
+
+	if blockHeight == int64(0) {
+		genesis, err := manager.client.Genesis()
+		if err != nil {
+			return nil, fmt.Errorf("error requesting chain genesis: %v", err)
+		}
+
+		return parser.ParseGenesisCommands(genesis)
+	}
+
+	// Request tendermint RPC
+	block, rawBlock, err := manager.client.Block(blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting chain block at height %d: %v", blockHeight, err)
+	}
+
+	blockResults, err := manager.client.BlockResults(blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting chain block_results at height %d: %v", blockHeight, err)
+	}
+
+	commands, err := parser.ParseBlockToCommands(
+		manager.txDecoder,
+		block,
+		rawBlock,
+		blockResults,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing block data to commands %v", err)
+	}
+
+	return commands, nil
+}
+
+// Run starts the polling service for blocks. It returns after the context is cancelled and the
+// in-flight window is handled, or with ErrReorgTooDeep when the chain reorganisation cannot be rolled
+// back.
+func (manager *SyncManager) Run(ctx context.Context) error {
+	tracker, err := manager.newBlockHeightFeed()
+	if err != nil {
+		return fmt.Errorf("error creating block height feed: %v", err)
+	}
+	blockHeightCh := make(chan int64, 1)
+	go func() {
+		for {
+			select {
+			case latestBlockHeight := <-blockHeightCh:
+				manager.latestBlockHeight = &latestBlockHeight
+				manager.drainShouldSyncCh()
+				manager.shouldSyncCh <- true
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	tracker.Subscribe(blockHeightCh)
+	// the latest block height is known once the tracker publishes it
+	go tracker.Run(ctx)
+
+	// blocks are synchronized on every new block notified over WebSocket, polling is only needed when
+	// the feed is polling as well
+	isPolling := manager.blockFeed != BLOCK_FEED_WEBSOCKET
+	for {
+		if manager.latestBlockHeight == nil {
+			manager.logger.Info("the chain has no block yet")
+		} else {
+			if err := manager.SyncBlocks(ctx, *manager.latestBlockHeight); err != nil {
+				if errors.Is(err, ErrReorgTooDeep) {
+					return err
+				}
+				manager.logger.Errorf("error synchronizing blocks to latest height %d: %v", *manager.latestBlockHeight, err)
+			}
+		}
+
+		var pollingCh <-chan time.Time
+		if isPolling {
+			pollingCh = time.After(manager.pollingInterval)
+		}
+		select {
+		case <-manager.shouldSyncCh:
+		case <-pollingCh:
+		case <-ctx.Done():
+			manager.logger.Info("sync manager stopped")
+			return nil
+		}
+	}
+}
+
+func (manager *SyncManager) newBlockHeightFeed() (chainfeed.BlockHeightFeed, error) {
+	if manager.blockFeed != BLOCK_FEED_WEBSOCKET {
+		return chainfeed.NewBlockHeightTracker(manager.logger, manager.client), nil
+	}
+
+	websocketURL := manager.websocketURL
+	if websocketURL == "" {
+		var err error
+		if websocketURL, err = chainfeed.WebSocketURLFromHTTPRPCURL(manager.rpcURL); err != nil {
+			return nil, err
+		}
+	}
+	return chainfeed.NewWebSocketBlockHeightTracker(manager.logger, manager.client, websocketURL), nil
+}
+
+func (manager *SyncManager) drainShouldSyncCh() {
+	select {
+	case <-manager.shouldSyncCh:
+	default:
+	}
+}
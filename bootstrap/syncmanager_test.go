@@ -0,0 +1,235 @@
+package bootstrap_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/eventhandler"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/inmemoryblock"
+	"github.com/crypto-com/chain-indexing/bootstrap"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/entity/projection"
+	"github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	infrastructure_tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+	"github.com/crypto-com/chain-indexing/usecase/parser"
+	usecase_parser_test "github.com/crypto-com/chain-indexing/usecase/parser/test"
+)
+
+var _ = Describe("SyncManager", func() {
+	var archivePath string
+
+	BeforeEach(func() {
+		var err error
+		archivePath, err = ioutil.TempDir("", "tendermint-archive")
+		Expect(err).To(BeNil())
+
+		writer, err := tendermint.NewArchiveWriter(archivePath, false)
+		Expect(err).To(BeNil())
+		Expect(writer.WriteFile(
+			tendermint.ARCHIVE_GENESIS_FILE, strings.NewReader(usecase_parser_test.GENESIS_RESP),
+		)).To(Succeed())
+		Expect(writer.WriteFile(
+			tendermint.ArchiveBlockFile(100), strings.NewReader(infrastructure_tendermint_test.BLOCK_JSON),
+		)).To(Succeed())
+		Expect(writer.WriteFile(
+			tendermint.ArchiveBlockResultsFile(100),
+			strings.NewReader(infrastructure_tendermint_test.BLOCK_RESULTS_JSON),
+		)).To(Succeed())
+		Expect(writer.WriteManifest(&tendermint.ArchiveManifest{
+			Version:    tendermint.ARCHIVE_VERSION,
+			ChainID:    "testnet-croeseid-1",
+			FromHeight: 0,
+			ToHeight:   100,
+		})).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(archivePath)
+	})
+
+	It("should feed the synced blocks to in-memory projections without database", func() {
+		eventStore := event_interface.NewInMemoryStore()
+		eventStoreHandler := eventhandler.NewInMemoryEventStoreHandler(NewFakeLogger(), eventStore)
+
+		blockProjection := inmemoryblock.NewInMemoryBlock(NewFakeLogger())
+		projectionManager := projection.NewStoreBasedManager(NewFakeLogger(), eventStore).WithStartHeight(99)
+		Expect(projectionManager.RegisterProjection(blockProjection)).To(BeNil())
+
+		syncManager, err := bootstrap.NewSyncManager(bootstrap.SyncManagerParams{
+			Logger:          NewFakeLogger(),
+			TxDecoder:       parser.NewTxDecoder("basetcro"),
+			RollbackTargets: []projection.Rollbackable{projectionManager},
+			Config: bootstrap.SyncManagerConfig{
+				WindowSize:  1,
+				Strategy:    bootstrap.SYNC_STRATEGY_WINDOW,
+				ArchivePath: archivePath,
+			},
+		}, eventStoreHandler)
+		Expect(err).To(BeNil())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go projectionManager.Run(ctx)
+
+		Expect(syncManager.FetchRange(ctx, 100, 100, eventStoreHandler.HandleEvents)).To(Succeed())
+
+		Eventually(func() *int64 {
+			height, _ := blockProjection.GetLastHandledEventHeight()
+			return height
+		}, 10*time.Second).Should(Equal(primptr.Int64(100)))
+		block := blockProjection.FindByHeight(100)
+		Expect(block).NotTo(BeNil())
+		Expect(block.Hash).To(Equal("82C25937191D1CF73BE9222CB04CE35B7A1366CC5BB08D9BB9AB457712E4F2D1"))
+		Expect(blockProjection.Count()).To(Equal(int64(1)))
+	})
+
+	It("should synchronize up to the last archived block", func() {
+		eventStore := event_interface.NewInMemoryStore()
+		eventStoreHandler := eventhandler.NewInMemoryEventStoreHandler(NewFakeLogger(), eventStore)
+		Expect(eventStoreHandler.HandleEvents(99, nil)).To(Succeed())
+
+		syncManager, err := bootstrap.NewSyncManager(bootstrap.SyncManagerParams{
+			Logger:    NewFakeLogger(),
+			TxDecoder: parser.NewTxDecoder("basetcro"),
+			Config: bootstrap.SyncManagerConfig{
+				WindowSize:  1,
+				Strategy:    bootstrap.SYNC_STRATEGY_WINDOW,
+				ArchivePath: archivePath,
+			},
+		}, eventStoreHandler)
+		Expect(err).To(BeNil())
+
+		Expect(syncManager.SyncBlocks(context.Background(), 100)).To(Succeed())
+
+		Expect(eventStoreHandler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(100)))
+		events, err := eventStore.GetAllByHeight(100)
+		Expect(err).To(BeNil())
+		Expect(events).NotTo(BeEmpty())
+	})
+
+	Describe("on chain reorganisation", func() {
+		var reorgArchivePath string
+
+		BeforeEach(func() {
+			var err error
+			reorgArchivePath, err = ioutil.TempDir("", "tendermint-archive")
+			Expect(err).To(BeNil())
+
+			writer, err := tendermint.NewArchiveWriter(reorgArchivePath, false)
+			Expect(err).To(BeNil())
+			for height := int64(1); height <= 100; height += 1 {
+				Expect(writer.WriteFile(
+					tendermint.ArchiveBlockFile(height), strings.NewReader(infrastructure_tendermint_test.BLOCK_JSON),
+				)).To(Succeed())
+			}
+			Expect(writer.WriteManifest(&tendermint.ArchiveManifest{
+				Version:    tendermint.ARCHIVE_VERSION,
+				ChainID:    "testnet-croeseid-1",
+				FromHeight: 1,
+				ToHeight:   100,
+			})).To(Succeed())
+			Expect(writer.Close()).To(Succeed())
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(reorgArchivePath)
+		})
+
+		newSyncManager := func(maxReorgDepth int64, handler eventhandler.Handler) *bootstrap.SyncManager {
+			syncManager, err := bootstrap.NewSyncManager(bootstrap.SyncManagerParams{
+				Logger:    NewFakeLogger(),
+				TxDecoder: parser.NewTxDecoder("basetcro"),
+				Config: bootstrap.SyncManagerConfig{
+					WindowSize:    10,
+					Strategy:      bootstrap.SYNC_STRATEGY_WINDOW,
+					ArchivePath:   reorgArchivePath,
+					MaxReorgDepth: maxReorgDepth,
+				},
+			}, handler)
+			Expect(err).To(BeNil())
+
+			return syncManager
+		}
+
+		It("should search and roll back to the last handled block on the chain", func() {
+			handler := newFakeRollbackableHandler(100, 60)
+			syncManager := newSyncManager(0, handler)
+
+			// blocks in the archive are copies of the same block, stop before syncing after rollback
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(syncManager.SyncBlocks(ctx, 100)).To(Succeed())
+
+			Expect(handler.rolledBackTo).To(Equal(primptr.Int64(60)))
+			// exponential steps followed by binary search instead of walking back block by block
+			Expect(handler.lookups).To(BeNumerically("<=", 16))
+		})
+
+		It("should return ErrReorgTooDeep without rolling back beyond the maximum reorg depth", func() {
+			handler := newFakeRollbackableHandler(100, 60)
+			syncManager := newSyncManager(10, handler)
+
+			err := syncManager.SyncBlocks(context.Background(), 100)
+
+			Expect(errors.Is(err, bootstrap.ErrReorgTooDeep)).To(BeTrue())
+			Expect(handler.rolledBackTo).To(BeNil())
+		})
+	})
+})
+
+// fakeRollbackableHandler has handled the blocks up to lastHandledHeight, of which the blocks after
+// onChainUntilHeight are from an abandoned chain
+type fakeRollbackableHandler struct {
+	lastHandledHeight  int64
+	onChainUntilHeight int64
+
+	rolledBackTo *int64
+	lookups      int
+}
+
+func newFakeRollbackableHandler(lastHandledHeight int64, onChainUntilHeight int64) *fakeRollbackableHandler {
+	return &fakeRollbackableHandler{
+		lastHandledHeight:  lastHandledHeight,
+		onChainUntilHeight: onChainUntilHeight,
+	}
+}
+
+func (handler *fakeRollbackableHandler) GetLastHandledEventHeight() (*int64, error) {
+	return primptr.Int64(handler.lastHandledHeight), nil
+}
+
+func (handler *fakeRollbackableHandler) HandleEvents(blockHeight int64, _ []entity_event.Event) error {
+	handler.lastHandledHeight = blockHeight
+	return nil
+}
+
+func (handler *fakeRollbackableHandler) GetHandledBlock(height int64) (*eventhandler.HandledBlock, error) {
+	handler.lookups += 1
+
+	handledBlock := &eventhandler.HandledBlock{
+		Height:  height,
+		Hash:    "82C25937191D1CF73BE9222CB04CE35B7A1366CC5BB08D9BB9AB457712E4F2D1",
+		AppHash: "6AE0920938F76727054BC2531247632C5C0521E2B91EA3A9864EA4FF55023D77",
+	}
+	if height > handler.onChainUntilHeight {
+		handledBlock.Hash = "ABANDONED_BLOCK_HASH"
+	}
+	return handledBlock, nil
+}
+
+func (handler *fakeRollbackableHandler) RollbackTo(height int64) error {
+	handler.rolledBackTo = primptr.Int64(height)
+	handler.lastHandledHeight = height
+	return nil
+}
@@ -0,0 +1,73 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/entity/event"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+func (app *App) newVerifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Re-fetch a height range from Tendermint and report differences with the events in the event store",
+		Flags: heightRangeFlags(),
+		Action: func(ctx *cli.Context) error {
+			fromHeight, toHeight, err := parseHeightRange(ctx)
+			if err != nil {
+				return err
+			}
+			rangeCtx, err := app.newHeightRangeContext(ctx)
+			if err != nil {
+				return err
+			}
+			defer rangeCtx.Close()
+
+			shutdownCtx, shutdown := newShutdownContext(rangeCtx.logger)
+			defer shutdown()
+
+			eventStore := event_interface.NewRDbStore(rangeCtx.rdbConn.ToHandle(), rangeCtx.eventRegistry)
+			mismatchedHeights := make([]int64, 0)
+			rangeCtx.logger.Infof("going to verify blocks from %d to %d", fromHeight, toHeight)
+			if err = rangeCtx.syncManager.FetchRange(
+				shutdownCtx, fromHeight, toHeight,
+				func(blockHeight int64, events []event.Event) error {
+					storedEvents, err := eventStore.GetAllByHeight(blockHeight)
+					if err != nil {
+						return fmt.Errorf("error getting stored events: %v", err)
+					}
+					diffs, err := event.DiffEvents(storedEvents, events)
+					if err != nil {
+						return fmt.Errorf("error comparing events: %v", err)
+					}
+					if len(diffs) == 0 {
+						return nil
+					}
+
+					mismatchedHeights = append(mismatchedHeights, blockHeight)
+					logger := rangeCtx.logger.WithFields(applogger.LogFields{
+						"blockHeight": blockHeight,
+					})
+					for _, diff := range diffs {
+						logger.Errorf("event mismatched: %s", diff.String())
+					}
+					return nil
+				},
+			); err != nil {
+				return err
+			}
+
+			if len(mismatchedHeights) > 0 {
+				return fmt.Errorf(
+					"events of %d block(s) differ from the event store: %v", len(mismatchedHeights), mismatchedHeights,
+				)
+			}
+			rangeCtx.logger.Infof("events of blocks from %d to %d match the event store", fromHeight, toHeight)
+
+			return nil
+		},
+	}
+}
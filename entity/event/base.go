@@ -38,6 +38,11 @@ func (event *Base) UUID() string {
 	return event.EventUUID
 }
 
+// AssignUUID replaces the random UUID assigned on creation, see AssignDeterministicIDs
+func (event *Base) AssignUUID(uuid string) {
+	event.EventUUID = uuid
+}
+
 type BaseParams struct {
 	Name        string
 	Version     int
@@ -0,0 +1,70 @@
+package event
+
+// HeightRangeCursor iterates the events of a height range in height order. Every height in the range
+// is visited, heights without any event have no events.
+type HeightRangeCursor interface {
+	// Next advances the cursor to the next height. It returns false when the range is exhausted or an
+	// error occurred, which is returned by Err().
+	Next() bool
+	// Height returns the height the cursor is at
+	Height() int64
+	// Events returns the events at the current height
+	Events() []Event
+	Err() error
+	Close()
+}
+
+// GetAllByHeightFn returns all events at a height
+type GetAllByHeightFn = func(height int64) ([]Event, error)
+
+var _ HeightRangeCursor = &HeightByHeightCursor{}
+
+// HeightByHeightCursor is a cursor requesting the events one height at a time. It is for stores
+// without a more efficient way to stream a height range.
+type HeightByHeightCursor struct {
+	getAllByHeight GetAllByHeightFn
+	toHeight       int64
+
+	height int64
+	events []Event
+	err    error
+}
+
+func NewHeightByHeightCursor(getAllByHeight GetAllByHeightFn, fromHeight int64, toHeight int64) *HeightByHeightCursor {
+	return &HeightByHeightCursor{
+		getAllByHeight: getAllByHeight,
+		toHeight:       toHeight,
+
+		height: fromHeight - 1,
+	}
+}
+
+func (cursor *HeightByHeightCursor) Next() bool {
+	if cursor.err != nil || cursor.height >= cursor.toHeight {
+		return false
+	}
+
+	events, err := cursor.getAllByHeight(cursor.height + 1)
+	if err != nil {
+		cursor.err = err
+		return false
+	}
+	cursor.height += 1
+	cursor.events = events
+
+	return true
+}
+
+func (cursor *HeightByHeightCursor) Height() int64 {
+	return cursor.height
+}
+
+func (cursor *HeightByHeightCursor) Events() []Event {
+	return cursor.events
+}
+
+func (cursor *HeightByHeightCursor) Err() error {
+	return cursor.err
+}
+
+func (cursor *HeightByHeightCursor) Close() {}
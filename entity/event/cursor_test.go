@@ -0,0 +1,46 @@
+package event_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+)
+
+var _ = Describe("HeightByHeightCursor", func() {
+	It("should visit every height in the range", func() {
+		requestedHeights := make([]int64, 0)
+		cursor := event.NewHeightByHeightCursor(func(height int64) ([]event.Event, error) {
+			requestedHeights = append(requestedHeights, height)
+			return []event.Event{newBaseJSONEvent("value")}, nil
+		}, 3, 5)
+		defer cursor.Close()
+
+		visitedHeights := make([]int64, 0)
+		for cursor.Next() {
+			visitedHeights = append(visitedHeights, cursor.Height())
+			Expect(cursor.Events()).To(HaveLen(1))
+		}
+		Expect(cursor.Err()).To(BeNil())
+		Expect(visitedHeights).To(Equal([]int64{3, 4, 5}))
+		Expect(requestedHeights).To(Equal([]int64{3, 4, 5}))
+	})
+
+	It("should stop at the height with error", func() {
+		cursor := event.NewHeightByHeightCursor(func(height int64) ([]event.Event, error) {
+			if height == 2 {
+				return nil, errors.New("any error")
+			}
+			return []event.Event{}, nil
+		}, 1, 3)
+		defer cursor.Close()
+
+		Expect(cursor.Next()).To(BeTrue())
+		Expect(cursor.Height()).To(Equal(int64(1)))
+		Expect(cursor.Next()).To(BeFalse())
+		Expect(cursor.Err()).To(MatchError("any error"))
+		Expect(cursor.Next()).To(BeFalse())
+	})
+})
@@ -0,0 +1,85 @@
+package event
+
+import (
+	"fmt"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Diff is a difference between the expected and actual events at the same position. Either of the
+// events is nil when it is missing.
+type Diff struct {
+	Index         int
+	MaybeExpected Event
+	MaybeActual   Event
+}
+
+func (diff *Diff) String() string {
+	if diff.MaybeActual == nil {
+		return fmt.Sprintf("#%d: missing %s", diff.Index, diff.MaybeExpected.Name())
+	}
+	if diff.MaybeExpected == nil {
+		return fmt.Sprintf("#%d: unexpected %s", diff.Index, diff.MaybeActual.Name())
+	}
+	return fmt.Sprintf(
+		"#%d: expected %s, got %s", diff.Index, diff.MaybeExpected.String(), diff.MaybeActual.String(),
+	)
+}
+
+// DiffEvents compares the events in order and returns the differences. Events are compared by their
+// JSON payload except the UUID, which is random for the events stored before the IDs are derived from
+// chain data.
+func DiffEvents(expected []Event, actual []Event) ([]Diff, error) {
+	diffs := make([]Diff, 0)
+	for i := 0; i < len(expected) || i < len(actual); i += 1 {
+		if i >= len(actual) {
+			diffs = append(diffs, Diff{Index: i, MaybeExpected: expected[i]})
+			continue
+		}
+		if i >= len(expected) {
+			diffs = append(diffs, Diff{Index: i, MaybeActual: actual[i]})
+			continue
+		}
+
+		isEqual, err := isEventEqual(expected[i], actual[i])
+		if err != nil {
+			return nil, err
+		}
+		if !isEqual {
+			diffs = append(diffs, Diff{Index: i, MaybeExpected: expected[i], MaybeActual: actual[i]})
+		}
+	}
+
+	return diffs, nil
+}
+
+func isEventEqual(expected Event, actual Event) (bool, error) {
+	expectedPayload, err := payloadWithoutUUID(expected)
+	if err != nil {
+		return false, err
+	}
+	actualPayload, err := payloadWithoutUUID(actual)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(expectedPayload, actualPayload), nil
+}
+
+func payloadWithoutUUID(event Event) (interface{}, error) {
+	encoded, err := event.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event %s to json: %v", event.Name(), err)
+	}
+
+	var payload interface{}
+	if err = jsoniter.UnmarshalFromString(encoded, &payload); err != nil {
+		return nil, fmt.Errorf("error decoding event %s json: %v", event.Name(), err)
+	}
+	if fields, ok := payload.(map[string]interface{}); ok {
+		delete(fields, "uuid")
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,58 @@
+package event_test
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+)
+
+var _ = Describe("DiffEvents", func() {
+	It("should ignore the UUID of events", func() {
+		expected := []event.Event{newBaseJSONEvent("value")}
+		actual := []event.Event{newBaseJSONEvent("value")}
+		Expect(expected[0].UUID()).NotTo(Equal(actual[0].UUID()))
+
+		diffs, err := event.DiffEvents(expected, actual)
+		Expect(err).To(BeNil())
+		Expect(diffs).To(BeEmpty())
+	})
+
+	It("should return the mismatched, missing and unexpected events", func() {
+		expected := []event.Event{newBaseJSONEvent("value"), newBaseJSONEvent("missing")}
+		actual := []event.Event{newBaseJSONEvent("other value")}
+
+		diffs, err := event.DiffEvents(expected, actual)
+		Expect(err).To(BeNil())
+		Expect(diffs).To(Equal([]event.Diff{
+			{Index: 0, MaybeExpected: expected[0], MaybeActual: actual[0]},
+			{Index: 1, MaybeExpected: expected[1]},
+		}))
+
+		diffs, err = event.DiffEvents(actual, expected)
+		Expect(err).To(BeNil())
+		Expect(diffs[1]).To(Equal(event.Diff{Index: 1, MaybeActual: expected[1]}))
+	})
+})
+
+type baseJSONEvent struct {
+	event.Base
+
+	Key string `json:"key"`
+}
+
+func newBaseJSONEvent(key string) *baseJSONEvent {
+	return &baseJSONEvent{
+		event.NewBase(event.BaseParams{
+			Name:        "BaseJSONEvent",
+			Version:     1,
+			BlockHeight: 1,
+		}),
+		key,
+	}
+}
+func (event *baseJSONEvent) ToJSON() (string, error) {
+	return jsoniter.MarshalToString(event)
+}
+func (event *baseJSONEvent) String() string { return event.Key }
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ComputeDigest returns the digest of the events at a height chained with the digest of the previous
+// height, which is empty for the first digested height. Events are digested by their canonical JSON,
+// so that the digest depends neither on the order of events nor on how the store formats the JSON.
+func ComputeDigest(previousDigest string, encodedEvents []string) (string, error) {
+	canonicalEvents := make([]string, 0, len(encodedEvents))
+	for _, encodedEvent := range encodedEvents {
+		canonicalEvent, err := canonicalJSON(encodedEvent)
+		if err != nil {
+			return "", fmt.Errorf("error canonicalizing event JSON: %v", err)
+		}
+		canonicalEvents = append(canonicalEvents, canonicalEvent)
+	}
+	sort.Strings(canonicalEvents)
+
+	hash := sha256.New()
+	hash.Write([]byte(previousDigest))
+	for _, canonicalEvent := range canonicalEvents {
+		hash.Write([]byte{'\n'})
+		hash.Write([]byte(canonicalEvent))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ComputeEventsDigest returns the digest of the events at a height, see ComputeDigest
+func ComputeEventsDigest(previousDigest string, events []Event) (string, error) {
+	encodedEvents := make([]string, 0, len(events))
+	for _, event := range events {
+		encodedEvent, err := event.ToJSON()
+		if err != nil {
+			return "", fmt.Errorf("error encoding event %s to JSON: %v", event.UUID(), err)
+		}
+		encodedEvents = append(encodedEvents, encodedEvent)
+	}
+
+	return ComputeDigest(previousDigest, encodedEvents)
+}
+
+// canonicalJSON re-encodes the JSON without insignificant whitespaces and with object keys sorted.
+// Numbers are kept as is.
+func canonicalJSON(encoded string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(encoded)))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(canonical), nil
+}
@@ -0,0 +1,59 @@
+package event_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+)
+
+var _ = Describe("Digest", func() {
+	Describe("ComputeDigest", func() {
+		It("should not depend on the event order and JSON formatting", func() {
+			digest, err := event.ComputeDigest("previous", []string{
+				`{"name":"A","height":1,"amount":"10"}`,
+				`{"name":"B","height":1}`,
+			})
+			Expect(err).To(BeNil())
+
+			Expect(event.ComputeDigest("previous", []string{
+				`{ "height": 1, "name": "B" }`,
+				`{"amount":"10", "height":1,"name":"A"}`,
+			})).To(Equal(digest))
+		})
+
+		It("should chain with the previous digest", func() {
+			encodedEvents := []string{`{"name":"A","height":1}`}
+
+			digest, err := event.ComputeDigest("", encodedEvents)
+			Expect(err).To(BeNil())
+
+			Expect(event.ComputeDigest("previous", encodedEvents)).NotTo(Equal(digest))
+		})
+
+		It("should change when any event is altered", func() {
+			digest, err := event.ComputeDigest("", []string{`{"name":"A","height":1,"amount":"10"}`})
+			Expect(err).To(BeNil())
+
+			Expect(event.ComputeDigest("", []string{`{"name":"A","height":1,"amount":"11"}`})).NotTo(Equal(digest))
+		})
+
+		It("should return error when the event is not JSON", func() {
+			_, err := event.ComputeDigest("", []string{"invalid"})
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("ComputeEventsDigest", func() {
+		It("should digest the events JSON", func() {
+			anyEvent := newBaseJSONEvent("key")
+			encodedEvent, err := anyEvent.ToJSON()
+			Expect(err).To(BeNil())
+			expected, err := event.ComputeDigest("", []string{encodedEvent})
+			Expect(err).To(BeNil())
+
+			Expect(event.ComputeEventsDigest("", []event.Event{anyEvent})).To(Equal(expected))
+		})
+	})
+})
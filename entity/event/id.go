@@ -0,0 +1,80 @@
+package event
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ID_NAMESPACE is the UUID namespace of event IDs derived from chain data
+var ID_NAMESPACE = uuid.MustParse("5c1b2d4e-3a5f-4c7e-9b8d-2f6a1e0c7d93")
+
+// NO_MSG_INDEX is the message index of events which are not generated from a transaction message
+const NO_MSG_INDEX = -1
+
+// IDParams is the chain data from which an event ID is derived
+type IDParams struct {
+	BlockHeight int64
+	// TxHash is empty for events not generated from a transaction
+	TxHash string
+	// MsgIndex is NO_MSG_INDEX for events not generated from a transaction message
+	MsgIndex int
+	Name     string
+	// Position is the number of events with the same height, transaction, message and name generated
+	// before the event in the block
+	Position int
+}
+
+// NewID returns the deterministic ID of an event, so that the same event always has the same ID no
+// matter how many times or in which environment the block is synced
+func NewID(params IDParams) string {
+	data := strings.Join([]string{
+		strconv.FormatInt(params.BlockHeight, 10),
+		params.TxHash,
+		strconv.Itoa(params.MsgIndex),
+		params.Name,
+		strconv.Itoa(params.Position),
+	}, "/")
+
+	return uuid.NewSHA1(ID_NAMESPACE, []byte(data)).String()
+}
+
+// TxScoped is implemented by events generated from a transaction
+type TxScoped interface {
+	// TxScope returns the transaction hash and message index of the event. The message index is
+	// NO_MSG_INDEX when the event is not specific to a message.
+	TxScope() (txHash string, msgIndex int)
+}
+
+// UUIDAssignable is implemented by events of which the UUID can be assigned after creation
+type UUIDAssignable interface {
+	AssignUUID(uuid string)
+}
+
+// AssignDeterministicIDs replaces the UUIDs of all events generated from a block with the IDs derived
+// from chain data. The events must be in the order they are generated from the block. Events which are
+// not UUIDAssignable keep their UUIDs.
+func AssignDeterministicIDs(events []Event) {
+	positions := make(map[IDParams]int)
+	for _, event := range events {
+		assignable, ok := event.(UUIDAssignable)
+		if !ok {
+			continue
+		}
+
+		params := IDParams{
+			BlockHeight: event.Height(),
+			MsgIndex:    NO_MSG_INDEX,
+			Name:        event.Name(),
+		}
+		if txScoped, ok := event.(TxScoped); ok {
+			params.TxHash, params.MsgIndex = txScoped.TxScope()
+		}
+		position := positions[params]
+		positions[params] += 1
+		params.Position = position
+
+		assignable.AssignUUID(NewID(params))
+	}
+}
@@ -0,0 +1,95 @@
+package event_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/event"
+)
+
+var _ = Describe("Deterministic ID", func() {
+	Describe("NewID", func() {
+		It("should return the same ID for the same chain data", func() {
+			params := event.IDParams{
+				BlockHeight: 1,
+				TxHash:      "TxHash",
+				MsgIndex:    0,
+				Name:        "MsgSendCreated",
+				Position:    0,
+			}
+
+			Expect(event.NewID(params)).To(Equal(event.NewID(params)))
+		})
+
+		It("should return different IDs for different chain data", func() {
+			params := event.IDParams{
+				BlockHeight: 1,
+				MsgIndex:    event.NO_MSG_INDEX,
+				Name:        "BlockCreated",
+			}
+			anotherParams := params
+			anotherParams.Position = 1
+
+			Expect(event.NewID(params)).NotTo(Equal(event.NewID(anotherParams)))
+		})
+	})
+
+	Describe("AssignDeterministicIDs", func() {
+		It("should assign the same IDs to the events re-generated from the same block", func() {
+			generateEvents := func() []event.Event {
+				return []event.Event{
+					newBaseJSONEvent("first"),
+					newTxScopedEvent("TxHash", 0),
+					newBaseJSONEvent("second"),
+					newTxScopedEvent("TxHash", 1),
+				}
+			}
+
+			events := generateEvents()
+			event.AssignDeterministicIDs(events)
+			regeneratedEvents := generateEvents()
+			event.AssignDeterministicIDs(regeneratedEvents)
+
+			uuids := make(map[string]bool)
+			for i := range events {
+				Expect(events[i].UUID()).To(Equal(regeneratedEvents[i].UUID()))
+				uuids[events[i].UUID()] = true
+			}
+			Expect(uuids).To(HaveLen(len(events)))
+		})
+
+		It("should derive the IDs from the transaction scope of the events", func() {
+			events := []event.Event{newTxScopedEvent("TxHash", 1)}
+
+			event.AssignDeterministicIDs(events)
+
+			Expect(events[0].UUID()).To(Equal(event.NewID(event.IDParams{
+				BlockHeight: 1,
+				TxHash:      "TxHash",
+				MsgIndex:    1,
+				Name:        "BaseJSONEvent",
+				Position:    0,
+			})))
+		})
+	})
+})
+
+type txScopedEvent struct {
+	*baseJSONEvent
+
+	txHash   string
+	msgIndex int
+}
+
+func newTxScopedEvent(txHash string, msgIndex int) *txScopedEvent {
+	return &txScopedEvent{
+		newBaseJSONEvent(txHash),
+
+		txHash,
+		msgIndex,
+	}
+}
+
+func (event *txScopedEvent) TxScope() (string, int) {
+	return event.txHash, event.msgIndex
+}
@@ -9,11 +9,14 @@ var ErrMismatchEvent = errors.New("mismatched event")
 
 type Registry struct {
 	decoders map[string]Decoder
+	// upcasters maps "event name and version" to the Upcaster migrating the payload to the next version
+	upcasters map[string]Upcaster
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		decoders: make(map[string]Decoder),
+		decoders:  make(map[string]Decoder),
+		upcasters: make(map[string]Upcaster),
 	}
 }
 
@@ -23,20 +26,45 @@ func (registry *Registry) Register(eventName string, eventVersion int, decoder D
 	registry.decoders[eventType(eventName, eventVersion)] = decoder
 }
 
+// RegisterUpcaster add a mapping of "event name and version" to the Upcaster migrating the encoded
+// event from `fromVersion` to `fromVersion+1`. Upcasters of consecutive versions are chained so that
+// an event of any older version is decoded as the latest version. It will overwrite existing
+// registration if any.
+func (registry *Registry) RegisterUpcaster(eventName string, fromVersion int, upcaster Upcaster) {
+	registry.upcasters[eventType(eventName, fromVersion)] = upcaster
+}
+
 // IsRegister returns true when the event to decoder mapping is already registered
 func (registry *Registry) IsRegistered(eventName string, eventVersion int) bool {
 	_, exist := registry.decoders[eventType(eventName, eventVersion)]
 	return exist
 }
 
+// DecodeByType decodes the encoded event. When there are upcasters registered for the event version,
+// the encoded event is upcasted to the latest version before decoding.
 func (registry *Registry) DecodeByType(eventName string, eventVersion int, encoded []byte) (Event, error) {
 	var err error
 
-	if !registry.IsRegistered(eventName, eventVersion) {
-		return nil, fmt.Errorf("unrecognized event type `%s`", eventType(eventName, eventVersion))
+	latestVersion := eventVersion
+	for {
+		upcaster, exist := registry.upcasters[eventType(eventName, latestVersion)]
+		if !exist {
+			break
+		}
+		if encoded, err = upcaster(encoded); err != nil {
+			return nil, fmt.Errorf(
+				"error upcasting event `%s` to version %d: %v",
+				eventType(eventName, latestVersion), latestVersion+1, err,
+			)
+		}
+		latestVersion += 1
 	}
 
-	decoder := registry.decoders[eventType(eventName, eventVersion)]
+	if !registry.IsRegistered(eventName, latestVersion) {
+		return nil, fmt.Errorf("unrecognized event type `%s`", eventType(eventName, latestVersion))
+	}
+
+	decoder := registry.decoders[eventType(eventName, latestVersion)]
 	var event Event
 	if event, err = decoder(encoded); err != nil {
 		return nil, fmt.Errorf("error decoding event: %v", err)
@@ -50,3 +78,7 @@ func eventType(name string, version int) string {
 }
 
 type Decoder = func([]byte) (Event, error)
+
+// Upcaster migrates an encoded event to the next version. The migrated event must have the next
+// version, see NewJSONUpcaster.
+type Upcaster = func([]byte) ([]byte, error)
@@ -3,6 +3,7 @@ package event_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -61,9 +62,115 @@ var _ = Describe("eventRegistry", func() {
 			Expect(ok).To(BeTrue())
 			Expect(typedEvent).To(Equal(newSimpleJSONEvent()))
 		})
+
+		It("should upcast the encoded event of an older version to the latest version", func() {
+			registry := event.NewRegistry()
+			registry.Register(simpleJSONEventName, upcastedJSONEventVersion, decodeUpcastedJSONEvent)
+			registry.RegisterUpcaster(
+				simpleJSONEventName, upcastedJSONEventVersion-1, event.NewJSONUpcaster(
+					upcastedJSONEventVersion, func(payload map[string]interface{}) error {
+						payload["keys"] = []interface{}{payload["key"]}
+						delete(payload, "key")
+						return nil
+					},
+				),
+			)
+			anyEvent := newSimpleJSONEvent()
+			encoded, _ := json.Marshal(anyEvent)
+
+			actual, err := registry.DecodeByType(anyEvent.Name(), upcastedJSONEventVersion-1, encoded)
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(&upcastedJSONEvent{
+				EventVersion: upcastedJSONEventVersion,
+				Keys:         []string{"value"},
+			}))
+		})
+
+		It("should chain upcasters of consecutive versions", func() {
+			registry := event.NewRegistry()
+			registry.Register(simpleJSONEventName, upcastedJSONEventVersion, decodeUpcastedJSONEvent)
+			registry.RegisterUpcaster(
+				simpleJSONEventName, simpleJSONEventVersion, event.NewJSONUpcaster(
+					simpleJSONEventVersion+1, func(payload map[string]interface{}) error {
+						payload["key"] = payload["key"].(string) + "-v1"
+						return nil
+					},
+				),
+			)
+			registry.RegisterUpcaster(
+				simpleJSONEventName, simpleJSONEventVersion+1, event.NewJSONUpcaster(
+					upcastedJSONEventVersion, func(payload map[string]interface{}) error {
+						payload["keys"] = []interface{}{payload["key"]}
+						delete(payload, "key")
+						return nil
+					},
+				),
+			)
+
+			actual, err := registry.DecodeByType(simpleJSONEventName, simpleJSONEventVersion, []byte(`{"key":"value"}`))
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(&upcastedJSONEvent{
+				EventVersion: upcastedJSONEventVersion,
+				Keys:         []string{"value-v1"},
+			}))
+
+			// event stored in the intermediate version is upcasted as well
+			actual, err = registry.DecodeByType(simpleJSONEventName, simpleJSONEventVersion+1, []byte(`{"key":"value"}`))
+			Expect(err).To(BeNil())
+			Expect(actual).To(Equal(&upcastedJSONEvent{
+				EventVersion: upcastedJSONEventVersion,
+				Keys:         []string{"value"},
+			}))
+		})
+
+		It("should keep the precision of numbers in the upcasted event", func() {
+			upcaster := event.NewJSONUpcaster(1, func(payload map[string]interface{}) error {
+				return nil
+			})
+
+			upcasted, err := upcaster([]byte(`{"amount":123456789012345678901234567890}`))
+			Expect(err).To(BeNil())
+			Expect(upcasted).To(MatchJSON(`{"amount":123456789012345678901234567890,"version":1}`))
+		})
+
+		It("should return error when the upcaster fails", func() {
+			registry := event.NewRegistry()
+			registry.Register(simpleJSONEventName, upcastedJSONEventVersion, decodeUpcastedJSONEvent)
+			registry.RegisterUpcaster(simpleJSONEventName, simpleJSONEventVersion, func(_ []byte) ([]byte, error) {
+				return nil, errors.New("any error")
+			})
+
+			_, err := registry.DecodeByType(simpleJSONEventName, simpleJSONEventVersion, []byte(`{"key":"value"}`))
+			Expect(err).To(MatchError("error upcasting event `SimpleJSONEventV0` to version 1: any error"))
+		})
 	})
 })
 
+const upcastedJSONEventVersion = 2
+
+type upcastedJSONEvent struct {
+	EventVersion int      `json:"version"`
+	Keys         []string `json:"keys"`
+}
+
+func (event *upcastedJSONEvent) Height() int64 { return 1 }
+func (event *upcastedJSONEvent) Name() string  { return simpleJSONEventName }
+func (event *upcastedJSONEvent) Version() int  { return event.EventVersion }
+func (event *upcastedJSONEvent) UUID() string  { return "upcasted-json-event-id" }
+func (event *upcastedJSONEvent) ToJSON() (string, error) {
+	return event.UUID(), nil
+}
+func (event *upcastedJSONEvent) String() string { return simpleJSONEventName }
+func decodeUpcastedJSONEvent(eventBytes []byte) (event.Event, error) {
+	var event *upcastedJSONEvent
+	jsonDecoder := json.NewDecoder(bytes.NewReader(eventBytes))
+	jsonDecoder.DisallowUnknownFields()
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
 const simpleJSONEventName = "SimpleJSONEvent"
 const simpleJSONEventVersion = 0
 
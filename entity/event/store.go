@@ -1,13 +1,26 @@
 package event
 
+import "context"
+
 type Store interface {
 	// GetLatestEventHeight returns latest event height, nil if no event is stored
 	GetLatestHeight() (*int64, error)
 
 	GetAllByHeight(height int64) ([]Event, error)
 
+	// GetAllByHeightRange returns a cursor streaming the events from `fromHeight` to `toHeight`
+	// inclusively. The cursor must be closed after use.
+	GetAllByHeightRange(fromHeight int64, toHeight int64) (HeightRangeCursor, error)
+
 	Insert(evt Event) error
 
 	// InsertAll insert all events into store. It will rollback when the insert fails at any point.
 	InsertAll(evt []Event) error
 }
+
+// HeightNotifier is implemented by stores which can notify the height of newly inserted events
+type HeightNotifier interface {
+	// SubscribeHeights sends the height of every newly inserted events to heightCh until the context
+	// is cancelled. It returns an error when the subscription is interrupted.
+	SubscribeHeights(ctx context.Context, heightCh chan<- int64) error
+}
@@ -25,3 +25,9 @@ func (manager *FakeEventStore) Insert(evt entity_event.Event) error {
 func (manager *FakeEventStore) InsertAll(evts []entity_event.Event) error {
 	return nil
 }
+
+func (manager *FakeEventStore) GetAllByHeightRange(
+	fromHeight int64, toHeight int64,
+) (entity_event.HeightRangeCursor, error) {
+	return entity_event.NewHeightByHeightCursor(manager.GetAllByHeight, fromHeight, toHeight), nil
+}
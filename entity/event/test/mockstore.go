@@ -36,3 +36,11 @@ func (manager *MockEventStore) InsertAll(evts []entity_event.Event) error {
 
 	return mockArgs.Error(0)
 }
+
+// GetAllByHeightRange requests GetAllByHeight one height at a time, so that the expectations are set
+// on GetAllByHeight
+func (manager *MockEventStore) GetAllByHeightRange(
+	fromHeight int64, toHeight int64,
+) (entity_event.HeightRangeCursor, error) {
+	return entity_event.NewHeightByHeightCursor(manager.GetAllByHeight, fromHeight, toHeight), nil
+}
@@ -0,0 +1,31 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONPayloadMigration migrates the decoded JSON payload of an event in place
+type JSONPayloadMigration = func(payload map[string]interface{}) error
+
+// NewJSONUpcaster returns an Upcaster which applies the migration to the JSON payload of an event and
+// sets its version to `toVersion`
+func NewJSONUpcaster(toVersion int, migrate JSONPayloadMigration) Upcaster {
+	return func(encoded []byte) ([]byte, error) {
+		// numbers are kept as they are, big amounts would otherwise lose precision as float64
+		jsonDecoder := json.NewDecoder(bytes.NewReader(encoded))
+		jsonDecoder.UseNumber()
+
+		var payload map[string]interface{}
+		if err := jsonDecoder.Decode(&payload); err != nil {
+			return nil, err
+		}
+
+		if err := migrate(payload); err != nil {
+			return nil, err
+		}
+		payload["version"] = toVersion
+
+		return json.Marshal(payload)
+	}
+}
@@ -0,0 +1,42 @@
+package projection
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+// Status of a dead letter. A quarantined height waits for an operator to request a retry or a skip,
+// and is resolved or skipped once the projection has handled it.
+const DEAD_LETTER_STATUS_QUARANTINED = "QUARANTINED"
+const DEAD_LETTER_STATUS_RETRY = "RETRY"
+const DEAD_LETTER_STATUS_SKIP = "SKIP"
+const DEAD_LETTER_STATUS_RESOLVED = "RESOLVED"
+const DEAD_LETTER_STATUS_SKIPPED = "SKIPPED"
+
+// DEAD_LETTER_STATUS_HALTED is the status of a projection which cannot be rolled back after chain
+// reorganisation. The height is the one it failed to roll back to, and it stays halted until it is reset.
+const DEAD_LETTER_STATUS_HALTED = "HALTED"
+
+// DeadLetterQueue keeps the events of the heights projections fail to handle after the retry budget.
+// The projection is degraded and stops at the quarantined height until an operator requests to retry
+// or skip it.
+type DeadLetterQueue interface {
+	// Record the events of the quarantined height together with the last handling error. A height
+	// quarantined again after a retry is overwritten.
+	Quarantine(projectionId string, height int64, events []entity_event.Event, handleErr error) error
+
+	// Returns the status of the dead letter of the height, one of DEAD_LETTER_STATUS_*
+	GetStatus(projectionId string, height int64) (string, error)
+
+	// Update the status of the dead letter of the height
+	UpdateStatus(projectionId string, height int64, status string) error
+
+	// Record the projection is halted after failing to roll back to the height, so that it stays halted
+	// after restart
+	Halt(projectionId string, height int64, reason error) error
+
+	// Returns the height the projection is halted at, nil when it is not halted
+	FindHaltedHeight(projectionId string) (*int64, error)
+
+	// Resolve the halt of the projection after it is reset
+	ResolveHalt(projectionId string) error
+}
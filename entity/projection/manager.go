@@ -1,21 +1,54 @@
 package projection
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	entity_event "github.com/crypto-com/chain-indexing/entity/event"
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
 )
 
+// MAX_CURSOR_HEIGHT_RANGE is the maximum number of heights streamed from the event store at a time
+const MAX_CURSOR_HEIGHT_RANGE = 1000
+
+// BATCH_HANDLING_MIN_LAG is how many heights a projection implementing BatchHandler has to lag behind the
+// latest event height before its events are handled in batches
+const BATCH_HANDLING_MIN_LAG = 100
+
+// MAX_BATCH_HEIGHTS is the maximum number of heights handled in one batch
+const MAX_BATCH_HEIGHTS = 100
+
 // StoreBasedManager is a projection manager relies on replaying events from EventStore
 type StoreBasedManager struct {
 	logger     applogger.Logger
 	eventStore entity_event.Store
 
 	projections []Projection
+	// startHeight is the height projections without any handled event start from
+	startHeight int64
+
+	// rwMutex guards projection event handling against rollback. Runners hold the read lock while
+	// handling a height and rollback takes the write lock.
+	rwMutex sync.RWMutex
+	// rollbackGeneration is incremented on every rollback, reset and version swap so that runners know
+	// their next event height is outdated
+	rollbackGeneration int64
+	// haltedProjections are projections which cannot be rolled back and have to be rebuilt
+	haltedProjections map[string]bool
+
+	// deadLetterQueue receives the heights still failing after maxHandleRetries attempts, and the
+	// projections halted so that they stay halted after restart. Failing heights are retried forever
+	// when it is nil or maxHandleRetries is non-positive.
+	deadLetterQueue  DeadLetterQueue
+	maxHandleRetries int
 }
 
+var _ Rollbackable = &StoreBasedManager{}
+var _ Resetter = &StoreBasedManager{}
+
 func NewStoreBasedManager(logger applogger.Logger, eventStore entity_event.Store) *StoreBasedManager {
 	return &StoreBasedManager{
 		logger: logger.WithFields(applogger.LogFields{
@@ -24,9 +57,31 @@ func NewStoreBasedManager(logger applogger.Logger, eventStore entity_event.Store
 		eventStore: eventStore,
 
 		projections: make([]Projection, 0),
+
+		haltedProjections: make(map[string]bool),
 	}
 }
 
+// WithStartHeight makes projections without any handled event start from the height instead of
+// genesis. It is used when the chain is indexed from a height after genesis.
+func (manager *StoreBasedManager) WithStartHeight(height int64) *StoreBasedManager {
+	manager.startHeight = height
+	return manager
+}
+
+// WithDeadLetterQueue quarantines a height to the dead letter queue after the projection fails to
+// handle it `maxHandleRetries` times. The projection is degraded and waits at the height until an
+// operator requests to retry or skip it. Non-positive `maxHandleRetries` retries forever. Projections
+// halted after chain reorganisation are recorded to the queue as well, so that they stay halted after
+// restart until they are reset.
+func (manager *StoreBasedManager) WithDeadLetterQueue(
+	queue DeadLetterQueue, maxHandleRetries int,
+) *StoreBasedManager {
+	manager.deadLetterQueue = queue
+	manager.maxHandleRetries = maxHandleRetries
+	return manager
+}
+
 func (manager *StoreBasedManager) RegisterProjection(projection Projection) error {
 	if manager.IsProjectionRegistered(projection) {
 		return fmt.Errorf("projection `%s` already registered", projection.Id())
@@ -46,67 +101,257 @@ func (manager *StoreBasedManager) IsProjectionRegistered(projection Projection)
 
 // Starts projectionManager by running all registered projection.
 func (manager *StoreBasedManager) RunInBackground() {
+	go manager.Run(context.Background())
+}
+
+// Run runs all registered projections until the context is cancelled. It returns after every
+// projection has finished handling its current height.
+func (manager *StoreBasedManager) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	newEventsChs := make([]chan struct{}, 0, len(manager.projections))
 	for _, projection := range manager.projections {
-		go manager.projectionRunner(projection)
+		newEventsCh := make(chan struct{}, 1)
+		newEventsChs = append(newEventsChs, newEventsCh)
+
+		wg.Add(1)
+		go func(projection Projection) {
+			defer wg.Done()
+			manager.projectionRunner(ctx, projection, newEventsCh)
+		}(projection)
 	}
+	if notifier, ok := manager.eventStore.(entity_event.HeightNotifier); ok {
+		go manager.subscribeNewEvents(ctx, notifier, newEventsChs)
+	}
+	wg.Wait()
 }
 
-func (manager *StoreBasedManager) projectionRunner(projection Projection) {
+// RollbackTo reverts all registered projections to `height` after the event store is rolled back on
+// chain reorganisation. Projections not supporting rollback are reset and replayed from the event store.
+// Projections supporting neither are halted because they can no longer be replayed consistently, and
+// an error listing them is returned.
+func (manager *StoreBasedManager) RollbackTo(height int64) error {
+	manager.rwMutex.Lock()
+	defer manager.rwMutex.Unlock()
+
+	manager.rollbackGeneration += 1
+
+	haltedProjectionIds := make([]string, 0)
+	for _, projection := range manager.projections {
+		lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+		if err != nil {
+			return fmt.Errorf("error getting last handled event height of projection `%s`: %v", projection.Id(), err)
+		}
+		if lastHandledEventHeight == nil || *lastHandledEventHeight <= height {
+			continue
+		}
+
+		rollbackableProjection, ok := projection.(Rollbackable)
+		if !ok {
+			if resettableProjection, isResettable := projection.(Resettable); isResettable {
+				if err := manager.resetProjection(projection.Id(), resettableProjection); err != nil {
+					return err
+				}
+				manager.logger.WithFields(applogger.LogFields{
+					"projection": projection.Id(),
+					"height":     height,
+				}).Info("projection does not support rollback, it is reset and replayed from the start")
+				continue
+			}
+
+			manager.haltedProjections[projection.Id()] = true
+			haltedProjectionIds = append(haltedProjectionIds, projection.Id())
+			if manager.deadLetterQueue != nil {
+				if err := manager.deadLetterQueue.Halt(projection.Id(), height, fmt.Errorf(
+					"projection does not support rollback to height %d after chain reorganisation", height,
+				)); err != nil {
+					return fmt.Errorf("error recording halt of projection `%s`: %v", projection.Id(), err)
+				}
+			}
+			continue
+		}
+		if err := rollbackableProjection.RollbackTo(height); err != nil {
+			return fmt.Errorf("error rolling back projection `%s` to height %d: %v", projection.Id(), height, err)
+		}
+		manager.logger.WithFields(applogger.LogFields{
+			"projection": projection.Id(),
+			"height":     height,
+		}).Info("projection rolled back")
+	}
+
+	if len(haltedProjectionIds) > 0 {
+		return fmt.Errorf(
+			"projections [%s] do not support rollback and are halted, they have to be rebuilt",
+			strings.Join(haltedProjectionIds, ", "),
+		)
+	}
+	return nil
+}
+
+// ResetProjection resets the registered projection so that it replays events from the start. Event
+// handling is paused while the projection is reset. A projection halted after chain reorganisation
+// resumes after it is reset.
+func (manager *StoreBasedManager) ResetProjection(projectionId string) error {
+	manager.rwMutex.Lock()
+	defer manager.rwMutex.Unlock()
+
+	projection := manager.findProjection(projectionId)
+	if projection == nil {
+		return fmt.Errorf("projection `%s` is not registered", projectionId)
+	}
+	resettableProjection, ok := projection.(Resettable)
+	if !ok {
+		return fmt.Errorf("projection `%s` does not support reset", projectionId)
+	}
+
+	manager.rollbackGeneration += 1
+	if err := manager.resetProjection(projectionId, resettableProjection); err != nil {
+		return err
+	}
+
+	manager.logger.WithFields(applogger.LogFields{
+		"projection": projectionId,
+	}).Info("projection reset")
+	return nil
+}
+
+// resetProjection resets the projection and resumes it if it is halted. Caller must hold the write lock
+// and bump the rollback generation.
+func (manager *StoreBasedManager) resetProjection(projectionId string, projection Resettable) error {
+	if err := projection.Reset(); err != nil {
+		return fmt.Errorf("error resetting projection `%s`: %v", projectionId, err)
+	}
+	delete(manager.haltedProjections, projectionId)
+	if manager.deadLetterQueue != nil {
+		if err := manager.deadLetterQueue.ResolveHalt(projectionId); err != nil {
+			return fmt.Errorf("error resolving halt of reset projection `%s`: %v", projectionId, err)
+		}
+	}
+	return nil
+}
+
+func (manager *StoreBasedManager) projectionRunner(
+	ctx context.Context, projection Projection, newEventsCh <-chan struct{},
+) {
 	eventsToListen := projection.GetEventsToListen()
 	logger := manager.logger.WithFields(applogger.LogFields{
 		"projection": projection.Id(),
 	})
+	versionBuilder, isVersionBuilder := projection.(VersionBuilder)
+	_, isBatchHandler := projection.(BatchHandler)
 
 	logger.WithFields(applogger.LogFields{
 		"eventsToListen": eventsToListen,
 	}).Infof("projection start running")
 
-	var lastHandledEventHeight *int64
-	for {
-		var err error
-		lastHandledEventHeight, err = projection.GetLastHandledEventHeight()
-		if err == nil {
-			break
-		}
-
-		logger.Infof("error getting last handled event height from projection")
-		<-waitToRetry(5 * time.Second)
+	if !manager.mustLoadHalt(ctx, logger, projection) {
+		return
 	}
-
-	var nextEventHeight int64
-	if lastHandledEventHeight == nil {
-		nextEventHeight = 0
-	} else {
-		nextEventHeight = *lastHandledEventHeight + 1
+	nextEventHeight, rollbackGeneration, ok := manager.mustGetNextEventHeight(ctx, logger, projection)
+	if !ok {
+		return
 	}
 
+	// failedHeight and failureCount track the consecutive failures of handling the same height
+	var failedHeight int64
+	var failureCount int
+	// retriedHeight is the quarantined height requested to retry, its dead letter is resolved once it
+	// is handled
+	var retriedHeight *int64
+	// heights up to unbatchedUntilHeight are handled one by one after a failed batch, so that the
+	// failing height is retried and quarantined on its own
+	var unbatchedUntilHeight int64 = -1
+
 	for {
+		if ctx.Err() != nil {
+			logger.Infof("projection stopped")
+			return
+		}
+
 		latestEventHeight, _ := manager.eventStore.GetLatestHeight()
-		if latestEventHeight == nil {
-			logger.Debugf("no event in in the system yet")
-			<-waitToRetry(5 * time.Second)
+		if latestEventHeight == nil || nextEventHeight > *latestEventHeight {
+			if latestEventHeight == nil {
+				logger.Debugf("no event in in the system yet")
+			}
+			if !waitForNewEvents(ctx, newEventsCh, 5*time.Second) {
+				logger.Infof("projection stopped")
+				return
+			}
 			continue
 		}
-		for nextEventHeight <= *latestEventHeight {
-			var err error
 
-			eventLogger := logger.WithFields(applogger.LogFields{
-				"height": nextEventHeight,
-			})
+		if isBatchHandler && nextEventHeight > unbatchedUntilHeight &&
+			*latestEventHeight-nextEventHeight >= BATCH_HANDLING_MIN_LAG {
+			toBatchHeight := nextEventHeight + MAX_BATCH_HEIGHTS - 1
+			if toBatchHeight > *latestEventHeight {
+				toBatchHeight = *latestEventHeight
+			}
+			handled, err := manager.handleBatch(
+				projection, eventsToListen, nextEventHeight, toBatchHeight, rollbackGeneration,
+			)
+			if err != nil {
+				logger.WithFields(applogger.LogFields{
+					"fromHeight": nextEventHeight,
+					"toHeight":   toBatchHeight,
+				}).Errorf("error handling events in batch, handling the heights one by one: %v", err)
+				unbatchedUntilHeight = toBatchHeight
+				continue
+			}
+			if handled {
+				logger.WithFields(applogger.LogFields{
+					"fromHeight": nextEventHeight,
+					"toHeight":   toBatchHeight,
+				}).Infof("successfully handled events in batch")
+				nextEventHeight = toBatchHeight + 1
 
-			var eventsAtHeight []entity_event.Event
-			if eventsAtHeight, err = manager.eventStore.GetAllByHeight(nextEventHeight); err != nil {
-				eventLogger.Errorf("error getting all events by height: %v", err)
-				<-waitToRetry(time.Second)
+				if isVersionBuilder && nextEventHeight > *latestEventHeight &&
+					manager.swapInIfCaughtUp(logger, versionBuilder, toBatchHeight) {
+					return
+				}
 				continue
 			}
+			// halted or rolled back, which is picked up below
+		}
+
+		// events are streamed in bounded ranges so that a catching up projection does not hold the
+		// cursor for too long
+		toEventHeight := nextEventHeight + MAX_CURSOR_HEIGHT_RANGE - 1
+		if toEventHeight > *latestEventHeight {
+			toEventHeight = *latestEventHeight
+		}
+		cursor, err := manager.eventStore.GetAllByHeightRange(nextEventHeight, toEventHeight)
+		if err != nil {
+			logger.Errorf("error getting all events by height range: %v", err)
+			waitToRetry(ctx, time.Second)
+			continue
+		}
+
+		halted := false
+		for ctx.Err() == nil && cursor.Next() {
+			manager.rwMutex.RLock()
+			if manager.haltedProjections[projection.Id()] {
+				manager.rwMutex.RUnlock()
+				halted = true
+				break
+			}
+			if rollbackGeneration != manager.rollbackGeneration {
+				manager.rwMutex.RUnlock()
+				logger.Infof("projection was rolled back, reloading last handled event height")
+				if nextEventHeight, rollbackGeneration, ok = manager.mustGetNextEventHeight(
+					ctx, logger, projection,
+				); !ok {
+					cursor.Close()
+					return
+				}
+				break
+			}
+
+			eventLogger := logger.WithFields(applogger.LogFields{
+				"height": nextEventHeight,
+			})
 
 			var events = make([]entity_event.Event, 0)
-			for _, event := range eventsAtHeight {
+			for _, event := range cursor.Events() {
 				if !isListeningEvent(event, eventsToListen) {
-					//eventLogger.WithFields(applogger.LogFields{
-					//	"eventName": event.Name(),
-					//}).Debugf("skipping because event is not one of the listening events")
 					continue
 				}
 				events = append(events, event)
@@ -115,21 +360,364 @@ func (manager *StoreBasedManager) projectionRunner(projection Projection) {
 			eventLogger = eventLogger.WithFields(applogger.LogFields{
 				"eventCount": len(events),
 			})
-			if err = projection.HandleEvents(nextEventHeight, events); err != nil {
+			err = projection.HandleEvents(nextEventHeight, events)
+			manager.rwMutex.RUnlock()
+			if err != nil {
 				eventLogger.WithFields(applogger.LogFields{
 					"events": events,
 				}).Errorf("error handling events: %v", err)
-				<-waitToRetry(time.Second)
-				continue
+
+				if failedHeight != nextEventHeight {
+					failedHeight = nextEventHeight
+					failureCount = 0
+				}
+				failureCount += 1
+				if manager.deadLetterQueue == nil || manager.maxHandleRetries <= 0 ||
+					failureCount < manager.maxHandleRetries {
+					waitToRetry(ctx, time.Second)
+					break
+				}
+
+				failureCount = 0
+				quarantinedHeight := nextEventHeight
+				if nextEventHeight, rollbackGeneration, ok = manager.quarantine(
+					ctx, eventLogger, projection, quarantinedHeight, events, err, rollbackGeneration,
+				); !ok {
+					cursor.Close()
+					return
+				}
+				retriedHeight = &quarantinedHeight
+				break
 			}
 
 			eventLogger.Infof("successfully handled events")
+			if retriedHeight != nil && *retriedHeight == nextEventHeight {
+				if err := manager.deadLetterQueue.UpdateStatus(
+					projection.Id(), nextEventHeight, DEAD_LETTER_STATUS_RESOLVED,
+				); err != nil {
+					eventLogger.Errorf("error resolving dead letter of retried height: %v", err)
+				}
+				retriedHeight = nil
+			}
 			nextEventHeight += 1
+
+			if isVersionBuilder && nextEventHeight > *latestEventHeight &&
+				manager.swapInIfCaughtUp(logger, versionBuilder, nextEventHeight-1) {
+				cursor.Close()
+				return
+			}
+		}
+		cursor.Close()
+
+		if halted {
+			logger.Errorf("projection is halted after chain reorganisation, it has to be rebuilt")
+			if nextEventHeight, rollbackGeneration, ok = manager.waitForReset(ctx, logger, projection); !ok {
+				return
+			}
+			continue
+		}
+		if err := cursor.Err(); err != nil {
+			logger.WithFields(applogger.LogFields{
+				"height": nextEventHeight,
+			}).Errorf("error getting all events by height range: %v", err)
+			waitToRetry(ctx, time.Second)
+		}
+	}
+}
+
+// handleBatch handles the heights from `fromHeight` to `toHeight` in one batch by the projection
+// implementing BatchHandler. It returns false without handling any height when the projection is halted
+// or rolled back in the meantime.
+func (manager *StoreBasedManager) handleBatch(
+	projection Projection,
+	eventsToListen []string,
+	fromHeight int64,
+	toHeight int64,
+	rollbackGeneration int64,
+) (bool, error) {
+	cursor, err := manager.eventStore.GetAllByHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return false, fmt.Errorf("error getting all events by height range: %v", err)
+	}
+	defer cursor.Close()
+
+	batch := make([]HeightEvents, 0, toHeight-fromHeight+1)
+	for cursor.Next() {
+		events := make([]entity_event.Event, 0)
+		for _, event := range cursor.Events() {
+			if isListeningEvent(event, eventsToListen) {
+				events = append(events, event)
+			}
+		}
+		batch = append(batch, HeightEvents{
+			Height: cursor.Height(),
+			Events: events,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return false, fmt.Errorf("error getting all events by height range: %v", err)
+	}
+	if int64(len(batch)) != toHeight-fromHeight+1 {
+		return false, fmt.Errorf("expected %d heights from event store, got %d", toHeight-fromHeight+1, len(batch))
+	}
+
+	manager.rwMutex.RLock()
+	defer manager.rwMutex.RUnlock()
+	if manager.haltedProjections[projection.Id()] || rollbackGeneration != manager.rollbackGeneration {
+		return false, nil
+	}
+
+	if err := projection.(BatchHandler).HandleEventsBatch(batch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// swapInIfCaughtUp swaps in the version builder when it has handled all the heights handled by the live
+// projection. The live projection reloads its last handled event height afterwards. It returns true
+// when the builder is swapped in.
+func (manager *StoreBasedManager) swapInIfCaughtUp(
+	logger applogger.Logger, versionBuilder VersionBuilder, handledHeight int64,
+) bool {
+	manager.rwMutex.Lock()
+	defer manager.rwMutex.Unlock()
+
+	if liveProjection := manager.findProjection(versionBuilder.LiveProjectionId()); liveProjection != nil {
+		liveHeight, err := liveProjection.GetLastHandledEventHeight()
+		if err != nil {
+			logger.Errorf("error getting last handled event height of live projection: %v", err)
+			return false
+		}
+		if liveHeight != nil && *liveHeight > handledHeight {
+			return false
+		}
+	}
+
+	if err := versionBuilder.SwapIn(); err != nil {
+		logger.Errorf("error swapping in projection version: %v", err)
+		return false
+	}
+	manager.rollbackGeneration += 1
+
+	logger.WithFields(applogger.LogFields{
+		"height": handledHeight,
+	}).Infof("projection version swapped in, replacing `%s`", versionBuilder.LiveProjectionId())
+	return true
+}
+
+// quarantine records the failing height to the dead letter queue and waits until an operator requests
+// to retry or skip it, or the projection is rolled back or reset. It returns the next event height to
+// handle afterwards, see mustGetNextEventHeight.
+func (manager *StoreBasedManager) quarantine(
+	ctx context.Context,
+	logger applogger.Logger,
+	projection Projection,
+	height int64,
+	events []entity_event.Event,
+	handleErr error,
+	rollbackGeneration int64,
+) (int64, int64, bool) {
+	for {
+		err := manager.deadLetterQueue.Quarantine(projection.Id(), height, events, handleErr)
+		if err == nil {
+			break
+		}
+		logger.Errorf("error quarantining height to dead letter queue, will retry in 5 seconds: %v", err)
+		if !waitToRetry(ctx, 5*time.Second) {
+			logger.Infof("projection stopped")
+			return 0, 0, false
+		}
+	}
+	logger.Errorf(
+		"projection is degraded, height is quarantined after %d failed attempts: %v",
+		manager.maxHandleRetries, handleErr,
+	)
+
+	for {
+		if !waitToRetry(ctx, 5*time.Second) {
+			logger.Infof("projection stopped")
+			return 0, 0, false
+		}
+
+		manager.rwMutex.RLock()
+		rolledBack := rollbackGeneration != manager.rollbackGeneration
+		manager.rwMutex.RUnlock()
+		if rolledBack {
+			logger.Infof("projection was rolled back, reloading last handled event height")
+			return manager.mustGetNextEventHeight(ctx, logger, projection)
+		}
+
+		status, err := manager.deadLetterQueue.GetStatus(projection.Id(), height)
+		if err != nil {
+			logger.Errorf("error getting status of quarantined height: %v", err)
+			continue
+		}
+		switch status {
+		case DEAD_LETTER_STATUS_RETRY:
+			logger.Infof("retrying quarantined height")
+			return manager.mustGetNextEventHeight(ctx, logger, projection)
+		case DEAD_LETTER_STATUS_SKIP:
+			skipped, err := manager.skipHeight(projection, height, rollbackGeneration)
+			if err != nil {
+				logger.Errorf("error skipping quarantined height: %v", err)
+				continue
+			}
+			if !skipped {
+				// rolled back, the height is reloaded on next check
+				continue
+			}
+			if err := manager.deadLetterQueue.UpdateStatus(
+				projection.Id(), height, DEAD_LETTER_STATUS_SKIPPED,
+			); err != nil {
+				logger.Errorf("error updating status of skipped height: %v", err)
+			}
+			logger.Infof("quarantined height skipped")
+			return manager.mustGetNextEventHeight(ctx, logger, projection)
+		}
+	}
+}
+
+// skipHeight advances the projection over the quarantined height by handling it without any event. It
+// returns false when the projection is rolled back or reset in the meantime.
+func (manager *StoreBasedManager) skipHeight(
+	projection Projection, height int64, rollbackGeneration int64,
+) (bool, error) {
+	manager.rwMutex.RLock()
+	defer manager.rwMutex.RUnlock()
+
+	if rollbackGeneration != manager.rollbackGeneration {
+		return false, nil
+	}
+	if err := projection.HandleEvents(height, []entity_event.Event{}); err != nil {
+		return false, fmt.Errorf("error handling height without events: %v", err)
+	}
+	return true, nil
+}
+
+func (manager *StoreBasedManager) findProjection(projectionId string) Projection {
+	for _, projection := range manager.projections {
+		if projection.Id() == projectionId {
+			return projection
+		}
+	}
+	return nil
+}
+
+// subscribeNewEvents wakes up all projection runners when new events are inserted to the event store,
+// so that they do not wait for the next polling. It resubscribes when the subscription is interrupted.
+func (manager *StoreBasedManager) subscribeNewEvents(
+	ctx context.Context, notifier entity_event.HeightNotifier, newEventsChs []chan struct{},
+) {
+	for {
+		heightCh := make(chan int64)
+		subscribeErrCh := make(chan error, 1)
+		go func() {
+			subscribeErrCh <- notifier.SubscribeHeights(ctx, heightCh)
+		}()
+
+	subscription:
+		for {
+			select {
+			case <-heightCh:
+				for _, newEventsCh := range newEventsChs {
+					select {
+					case newEventsCh <- struct{}{}:
+					default:
+					}
+				}
+			case err := <-subscribeErrCh:
+				if ctx.Err() != nil {
+					return
+				}
+				manager.logger.Errorf("error subscribing new events, will retry in 5 seconds: %v", err)
+				break subscription
+			}
+		}
+
+		if !waitToRetry(ctx, 5*time.Second) {
+			return
 		}
-		<-waitToRetry(5 * time.Second)
 	}
 }
 
+// waitForReset waits until the halted projection is reset and returns its next event height, see
+// mustGetNextEventHeight
+func (manager *StoreBasedManager) waitForReset(
+	ctx context.Context, logger applogger.Logger, projection Projection,
+) (int64, int64, bool) {
+	for {
+		if !waitToRetry(ctx, 5*time.Second) {
+			logger.Infof("projection stopped")
+			return 0, 0, false
+		}
+
+		manager.rwMutex.RLock()
+		halted := manager.haltedProjections[projection.Id()]
+		manager.rwMutex.RUnlock()
+		if !halted {
+			logger.Infof("projection is reset, replaying from the start")
+			return manager.mustGetNextEventHeight(ctx, logger, projection)
+		}
+	}
+}
+
+// mustLoadHalt marks the projection halted when the halt is recorded in the dead letter queue before
+// restart. It retries until the halt is loaded, or returns false when the context is cancelled.
+func (manager *StoreBasedManager) mustLoadHalt(
+	ctx context.Context, logger applogger.Logger, projection Projection,
+) bool {
+	if manager.deadLetterQueue == nil {
+		return true
+	}
+
+	for {
+		maybeHaltedHeight, err := manager.deadLetterQueue.FindHaltedHeight(projection.Id())
+		if err == nil {
+			if maybeHaltedHeight != nil {
+				manager.rwMutex.Lock()
+				manager.haltedProjections[projection.Id()] = true
+				manager.rwMutex.Unlock()
+			}
+			return true
+		}
+
+		logger.Errorf("error finding halted height of projection: %v", err)
+		if !waitToRetry(ctx, 5*time.Second) {
+			return false
+		}
+	}
+}
+
+// mustGetNextEventHeight returns the next event height to handle for the projection together with the
+// rollback generation it is read at. It retries until the projection returns its last handled event
+// height, or returns false when the context is cancelled.
+func (manager *StoreBasedManager) mustGetNextEventHeight(
+	ctx context.Context, logger applogger.Logger, projection Projection,
+) (int64, int64, bool) {
+	var lastHandledEventHeight *int64
+	var rollbackGeneration int64
+	for {
+		var err error
+		manager.rwMutex.RLock()
+		rollbackGeneration = manager.rollbackGeneration
+		lastHandledEventHeight, err = projection.GetLastHandledEventHeight()
+		manager.rwMutex.RUnlock()
+		if err == nil {
+			break
+		}
+
+		logger.Infof("error getting last handled event height from projection")
+		if !waitToRetry(ctx, 5*time.Second) {
+			return 0, 0, false
+		}
+	}
+
+	if lastHandledEventHeight == nil {
+		return manager.startHeight, rollbackGeneration, true
+	}
+	return *lastHandledEventHeight + 1, rollbackGeneration, true
+}
+
 func isListeningEvent(event entity_event.Event, eventsToListen []string) bool {
 	targetEventName := event.Name()
 	for _, eventName := range eventsToListen {
@@ -141,6 +729,25 @@ func isListeningEvent(event entity_event.Event, eventsToListen []string) bool {
 	return false
 }
 
-func waitToRetry(wait time.Duration) <-chan time.Time {
-	return time.After(wait)
+// waitForNewEvents waits until new events are notified or the timeout. It returns false immediately
+// when the context is cancelled.
+func waitForNewEvents(ctx context.Context, newEventsCh <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-newEventsCh:
+		return true
+	case <-time.After(timeout):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitToRetry waits for the duration. It returns false immediately when the context is cancelled.
+func waitToRetry(ctx context.Context, wait time.Duration) bool {
+	select {
+	case <-time.After(wait):
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
@@ -1,6 +1,9 @@
 package projection_test
 
 import (
+	"context"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	. "github.com/crypto-com/chain-indexing/entity/event/test"
@@ -242,6 +245,439 @@ var _ = Describe("StoreBasedManager", func() {
 			mockProjection.AssertExpectations(GinkgoT())
 		})
 	})
+
+	Describe("WithStartHeight", func() {
+		It("should start projections without handled event from the start height", func() {
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore).WithStartHeight(99)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(99)), nil)
+			mockEventStore.On("GetAllByHeight", int64(99)).Return([]entity_event.Event{anyEvent}, nil)
+
+			handledCh := make(chan struct{})
+			mockProjection.On("HandleEvents", int64(99), []entity_event.Event{anyEvent}).Once().Return(nil).Run(
+				func(_ mock.Arguments) {
+					close(handledCh)
+				},
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(handledCh).Should(BeClosed())
+			mockEventStore.AssertNotCalled(GinkgoT(), "GetAllByHeight", int64(0))
+		})
+	})
+
+	Describe("Run with HeightNotifier", func() {
+		It("should handle new events immediately when they are notified", func() {
+			notifyingEventStore := newNotifyingEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), notifyingEventStore)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			notifyingEventStore.latestHeight = 1
+			notifyingEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			handledCh := make(chan int64, 2)
+			mockProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				handledCh <- args.Get(0).(int64)
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(handledCh).Should(Receive(Equal(int64(1))))
+
+			atomic.StoreInt64(&notifyingEventStore.latestHeight, 2)
+			notifyingEventStore.heightCh <- 2
+			// well before the polling interval
+			Eventually(handledCh, time.Second).Should(Receive(Equal(int64(2))))
+		})
+	})
+
+	Describe("RollbackTo", func() {
+		It("should rollback projections which have handled events after the height", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			anyProjection := NewMockRollbackableProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			anyProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(int64(10)), nil)
+			anyProjection.On("RollbackTo", int64(5)).Once().Return(nil)
+
+			anyUpToDateProjection := NewMockRollbackableProjection()
+			anyUpToDateProjection.On("Id").Return("ANY_UP_TO_DATE_PROJECTION_ID")
+			anyUpToDateProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(int64(5)), nil)
+
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+			Expect(manager.RegisterProjection(anyUpToDateProjection)).To(BeNil())
+
+			Expect(manager.RollbackTo(int64(5))).To(BeNil())
+
+			anyProjection.AssertExpectations(GinkgoT())
+			anyUpToDateProjection.AssertNotCalled(GinkgoT(), "RollbackTo", mock.Anything)
+		})
+
+		It("should reset projections which do not support rollback so that they are replayed", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			anyProjection := NewMockResettableProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			anyProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(int64(10)), nil)
+			anyProjection.On("Reset").Once().Return(nil)
+
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+
+			Expect(manager.RollbackTo(int64(5))).To(BeNil())
+
+			anyProjection.AssertExpectations(GinkgoT())
+		})
+
+		It("should return Error when a projection supports neither rollback nor reset", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			anyProjection := NewMockProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			anyProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(int64(10)), nil)
+
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+
+			Expect(manager.RollbackTo(int64(5))).To(MatchError(
+				"projections [ANY_PROJECTION_ID] do not support rollback and are halted, they have to be rebuilt",
+			))
+		})
+	})
+
+	Describe("ResetProjection", func() {
+		It("should reset the registered projection", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			anyProjection := NewMockResettableProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			anyProjection.On("Reset").Once().Return(nil)
+			anyOtherProjection := NewMockResettableProjection()
+			anyOtherProjection.On("Id").Return("ANY_OTHER_PROJECTION_ID")
+
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+			Expect(manager.RegisterProjection(anyOtherProjection)).To(BeNil())
+
+			Expect(manager.ResetProjection("ANY_PROJECTION_ID")).To(BeNil())
+
+			anyProjection.AssertExpectations(GinkgoT())
+			anyOtherProjection.AssertNotCalled(GinkgoT(), "Reset")
+		})
+
+		It("should return Error when the projection is not registered", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			Expect(manager.ResetProjection("ANY_PROJECTION_ID")).To(MatchError(
+				"projection `ANY_PROJECTION_ID` is not registered",
+			))
+		})
+
+		It("should return Error when the projection does not support reset", func() {
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), NewFakeEventStore())
+
+			anyProjection := NewMockProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+
+			Expect(manager.ResetProjection("ANY_PROJECTION_ID")).To(MatchError(
+				"projection `ANY_PROJECTION_ID` does not support reset",
+			))
+		})
+	})
+
+	Describe("Run with VersionBuilder", func() {
+		It("should swap in the version builder once it has caught up with the live projection", func() {
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+
+			anyEvent := newAnyEvent()
+			liveProjection := NewMockProjection()
+			liveProjection.On("Id").Return("ANY_PROJECTION_ID")
+			liveProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			liveProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(2), nil)
+			Expect(manager.RegisterProjection(liveProjection)).To(BeNil())
+
+			versionBuilder := NewMockVersionBuilder()
+			versionBuilder.On("Id").Return("ANY_PROJECTION_ID_v2")
+			versionBuilder.On("LiveProjectionId").Return("ANY_PROJECTION_ID")
+			versionBuilder.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			versionBuilder.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+			versionBuilder.On("HandleEvents", mock.Anything, mock.Anything).Return(nil)
+			swappedInCh := make(chan struct{})
+			versionBuilder.On("SwapIn").Once().Return(nil).Run(func(_ mock.Arguments) {
+				close(swappedInCh)
+			})
+			Expect(manager.RegisterProjection(versionBuilder)).To(BeNil())
+
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(swappedInCh).Should(BeClosed())
+			<-time.After(100 * time.Millisecond)
+			versionBuilder.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 3)
+			liveProjection.AssertNotCalled(GinkgoT(), "HandleEvents", mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("Run with BatchHandler", func() {
+		It("should handle the heights in batches while lagging far behind", func() {
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockBatchHandlerProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			latestHeight := int64(projection.BATCH_HANDLING_MIN_LAG + projection.MAX_BATCH_HEIGHTS)
+			mockEventStore.On("GetLatestHeight").Return(&latestHeight, nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			var batchedHeights []int64
+			mockProjection.On("HandleEventsBatch", mock.Anything).Once().Return(nil).Run(func(args mock.Arguments) {
+				for _, heightEvents := range args.Get(0).([]projection.HeightEvents) {
+					Expect(heightEvents.Events).To(Equal([]entity_event.Event{anyEvent}))
+					batchedHeights = append(batchedHeights, heightEvents.Height)
+				}
+			})
+			handledCh := make(chan struct{})
+			mockProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				if args.Get(0).(int64) == latestHeight {
+					close(handledCh)
+				}
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(handledCh).Should(BeClosed())
+			Expect(batchedHeights).To(HaveLen(projection.MAX_BATCH_HEIGHTS))
+			Expect(batchedHeights[0]).To(Equal(int64(1)))
+			// the remaining heights are within the lag and handled one by one
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", projection.BATCH_HANDLING_MIN_LAG)
+			mockProjection.AssertCalled(GinkgoT(), "HandleEvents", int64(projection.MAX_BATCH_HEIGHTS+1), mock.Anything)
+		})
+
+		It("should handle the heights one by one when the batch fails", func() {
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockBatchHandlerProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			latestHeight := int64(projection.BATCH_HANDLING_MIN_LAG + projection.MAX_BATCH_HEIGHTS)
+			mockEventStore.On("GetLatestHeight").Return(&latestHeight, nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			mockProjection.On("HandleEventsBatch", mock.Anything).Once().Return(errors.New("malformed event"))
+			handledCh := make(chan struct{})
+			mockProjection.On("HandleEvents", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				if args.Get(0).(int64) == latestHeight {
+					close(handledCh)
+				}
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(handledCh).Should(BeClosed())
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", int(latestHeight))
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEventsBatch", 1)
+		})
+	})
+
+	Describe("Run with DeadLetterQueue", func() {
+		It("should quarantine the height after the retry budget and skip it when requested", func() {
+			mockEventStore := NewMockEventStore()
+			mockDeadLetterQueue := NewMockDeadLetterQueue()
+			manager := projection.NewStoreBasedManager(
+				NewFakeLogger(), mockEventStore,
+			).WithDeadLetterQueue(mockDeadLetterQueue, 2)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Once().Return(primptr.Int64(0), nil)
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(1), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			handleErr := errors.New("malformed event")
+			mockProjection.On("HandleEvents", int64(1), []entity_event.Event{anyEvent}).Return(handleErr)
+			mockProjection.On("HandleEvents", int64(1), []entity_event.Event{}).Once().Return(nil)
+			handledCh := make(chan struct{})
+			mockProjection.On("HandleEvents", int64(2), []entity_event.Event{anyEvent}).Once().Return(nil).Run(
+				func(_ mock.Arguments) {
+					close(handledCh)
+				},
+			)
+
+			mockDeadLetterQueue.On("FindHaltedHeight", "ANY_PROJECTION_ID").Return((*int64)(nil), nil)
+			mockDeadLetterQueue.On(
+				"Quarantine", "ANY_PROJECTION_ID", int64(1), []entity_event.Event{anyEvent}, handleErr,
+			).Once().Return(nil)
+			mockDeadLetterQueue.On("GetStatus", "ANY_PROJECTION_ID", int64(1)).Return(
+				projection.DEAD_LETTER_STATUS_SKIP, nil,
+			)
+			mockDeadLetterQueue.On(
+				"UpdateStatus", "ANY_PROJECTION_ID", int64(1), projection.DEAD_LETTER_STATUS_SKIPPED,
+			).Once().Return(nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(handledCh, 10*time.Second).Should(BeClosed())
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 4)
+			mockDeadLetterQueue.AssertExpectations(GinkgoT())
+		})
+
+		It("should record the projection halted after chain reorganisation", func() {
+			mockDeadLetterQueue := NewMockDeadLetterQueue()
+			manager := projection.NewStoreBasedManager(
+				NewFakeLogger(), NewFakeEventStore(),
+			).WithDeadLetterQueue(mockDeadLetterQueue, 0)
+
+			anyProjection := NewMockProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			anyProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(int64(10)), nil)
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+
+			mockDeadLetterQueue.On("Halt", "ANY_PROJECTION_ID", int64(5), mock.Anything).Once().Return(nil)
+
+			Expect(manager.RollbackTo(int64(5))).NotTo(BeNil())
+
+			mockDeadLetterQueue.AssertExpectations(GinkgoT())
+		})
+
+		It("should resolve the halt when the projection is reset after chain reorganisation", func() {
+			mockDeadLetterQueue := NewMockDeadLetterQueue()
+			manager := projection.NewStoreBasedManager(
+				NewFakeLogger(), NewFakeEventStore(),
+			).WithDeadLetterQueue(mockDeadLetterQueue, 0)
+
+			anyProjection := NewMockResettableProjection()
+			anyProjection.On("Id").Return("ANY_PROJECTION_ID")
+			anyProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(int64(10)), nil)
+			anyProjection.On("Reset").Once().Return(nil)
+			Expect(manager.RegisterProjection(anyProjection)).To(BeNil())
+
+			mockDeadLetterQueue.On("ResolveHalt", "ANY_PROJECTION_ID").Once().Return(nil)
+
+			Expect(manager.RollbackTo(int64(5))).To(BeNil())
+
+			anyProjection.AssertExpectations(GinkgoT())
+			mockDeadLetterQueue.AssertExpectations(GinkgoT())
+			mockDeadLetterQueue.AssertNotCalled(GinkgoT(), "Halt", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should keep the projection halted after restart", func() {
+			mockEventStore := NewMockEventStore()
+			mockDeadLetterQueue := NewMockDeadLetterQueue()
+			manager := projection.NewStoreBasedManager(
+				NewFakeLogger(), mockEventStore,
+			).WithDeadLetterQueue(mockDeadLetterQueue, 0)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(1)), nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			loadedCh := make(chan struct{})
+			mockDeadLetterQueue.On("FindHaltedHeight", "ANY_PROJECTION_ID").Once().Return(
+				primptr.Int64(0), nil,
+			).Run(func(_ mock.Arguments) {
+				close(loadedCh)
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go manager.Run(ctx)
+
+			Eventually(loadedCh, 10*time.Second).Should(BeClosed())
+			Consistently(func() bool {
+				return mockProjection.AssertNotCalled(GinkgoT(), "HandleEvents", mock.Anything, mock.Anything)
+			}, 2*time.Second).Should(BeTrue())
+		})
+	})
+
+	Describe("Run", func() {
+		It("should return after the current height is handled when context is cancelled", func() {
+			mockEventStore := NewMockEventStore()
+			manager := projection.NewStoreBasedManager(NewFakeLogger(), mockEventStore)
+
+			anyEvent := newAnyEvent()
+			mockProjection := NewMockProjection()
+			mockProjection.On("Id").Return("ANY_PROJECTION_ID")
+			mockProjection.On("GetEventsToListen").Return([]string{anyEvent.Name()})
+			mockProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(0), nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(BeNil())
+
+			mockEventStore.On("GetLatestHeight").Return(primptr.Int64(int64(2)), nil)
+			mockEventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{anyEvent}, nil)
+
+			handlingCh := make(chan struct{})
+			releaseCh := make(chan struct{})
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Once().Return(nil).Run(func(_ mock.Arguments) {
+				close(handlingCh)
+				<-releaseCh
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			doneCh := make(chan struct{})
+			go func() {
+				manager.Run(ctx)
+				close(doneCh)
+			}()
+
+			<-handlingCh
+			cancel()
+			Consistently(doneCh, 100*time.Millisecond).ShouldNot(BeClosed())
+
+			close(releaseCh)
+			Eventually(doneCh).Should(BeClosed())
+			// height 2 is never handled after cancel
+			mockProjection.AssertExpectations(GinkgoT())
+			mockProjection.AssertNumberOfCalls(GinkgoT(), "HandleEvents", 1)
+		})
+	})
 })
 
 func newAnyEvent() entity_event.Event {
@@ -259,3 +695,35 @@ func newAnyOtherEvent() entity_event.Event {
 
 	return anyOtherEvent
 }
+
+// notifyingEventStore notifies the heights sent to heightCh and returns latestHeight as the latest
+// event height
+type notifyingEventStore struct {
+	*MockEventStore
+
+	latestHeight int64
+	heightCh     chan int64
+}
+
+func newNotifyingEventStore() *notifyingEventStore {
+	return &notifyingEventStore{
+		MockEventStore: NewMockEventStore(),
+
+		heightCh: make(chan int64),
+	}
+}
+
+func (store *notifyingEventStore) GetLatestHeight() (*int64, error) {
+	return primptr.Int64(atomic.LoadInt64(&store.latestHeight)), nil
+}
+
+func (store *notifyingEventStore) SubscribeHeights(ctx context.Context, heightCh chan<- int64) error {
+	for {
+		select {
+		case height := <-store.heightCh:
+			heightCh <- height
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
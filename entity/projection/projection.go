@@ -26,3 +26,60 @@ type Projection interface {
 	// projection. It is also responsible to update the last handled event height.
 	HandleEvents(height int64, events []entity_event.Event) error
 }
+
+// Rollbackable is an optional interface of Projection. A projection implementing it is able to revert
+// its projected states to a previous height, which is required to recover from chain reorganisation.
+type Rollbackable interface {
+	// Remove all projected states after `height` and update the last handled event height to
+	// `height`. It must be a no-op when the last handled event height is not after `height`.
+	RollbackTo(height int64) error
+}
+
+// Resettable is an optional interface of Projection. A projection implementing it is able to remove all
+// its projected states, so that it is rebuilt by replaying from the start.
+type Resettable interface {
+	// Remove all projected states and the last handled event height.
+	Reset() error
+}
+
+// Resetter resets a running projection and replays it from the start without interrupting the other
+// projections
+type Resetter interface {
+	ResetProjection(projectionId string) error
+}
+
+// Versioned is an optional interface of Projection. A projection implementing it builds a new version of
+// its states alongside the live ones when its version is bumped, so that the live version keeps being
+// served until the new version has caught up.
+type Versioned interface {
+	// Returns the projection building the latest version, or nil when the live states are already of
+	// the latest version.
+	NewVersionBuilder() (VersionBuilder, error)
+}
+
+// VersionBuilder is a projection building a new version of the states of a live projection. It is
+// swapped in once it has handled all the heights handled by the live projection.
+type VersionBuilder interface {
+	Projection
+
+	// Id of the live projection to be replaced
+	LiveProjectionId() string
+
+	// Atomically replace the live states with the built ones. The live projection takes over the last
+	// handled event height of the builder, which must not handle any event afterwards.
+	SwapIn() error
+}
+
+// BatchHandler is an optional interface of Projection. A projection implementing it handles a contiguous
+// range of heights at once, which speeds up catching up when it lags far behind.
+type BatchHandler interface {
+	// Handle the events of each height in order as `HandleEvents()` does and update the last handled
+	// event height to the last height of the batch. Either all or none of the heights are handled.
+	HandleEventsBatch(batch []HeightEvents) error
+}
+
+// HeightEvents are the events of a height that match `GetEventsToListen()`
+type HeightEvents struct {
+	Height int64
+	Events []entity_event.Event
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+)
+
+type MockDeadLetterQueue struct {
+	mock.Mock
+}
+
+func NewMockDeadLetterQueue() *MockDeadLetterQueue {
+	return &MockDeadLetterQueue{}
+}
+
+func (queue *MockDeadLetterQueue) Quarantine(
+	projectionId string, height int64, events []entity_event.Event, handleErr error,
+) error {
+	mockArgs := queue.Called(projectionId, height, events, handleErr)
+
+	return mockArgs.Error(0)
+}
+
+func (queue *MockDeadLetterQueue) GetStatus(projectionId string, height int64) (string, error) {
+	mockArgs := queue.Called(projectionId, height)
+
+	return mockArgs.String(0), mockArgs.Error(1)
+}
+
+func (queue *MockDeadLetterQueue) UpdateStatus(projectionId string, height int64, status string) error {
+	mockArgs := queue.Called(projectionId, height, status)
+
+	return mockArgs.Error(0)
+}
+
+func (queue *MockDeadLetterQueue) Halt(projectionId string, height int64, reason error) error {
+	mockArgs := queue.Called(projectionId, height, reason)
+
+	return mockArgs.Error(0)
+}
+
+func (queue *MockDeadLetterQueue) FindHaltedHeight(projectionId string) (*int64, error) {
+	mockArgs := queue.Called(projectionId)
+
+	return mockArgs.Get(0).(*int64), mockArgs.Error(1)
+}
+
+func (queue *MockDeadLetterQueue) ResolveHalt(projectionId string) error {
+	mockArgs := queue.Called(projectionId)
+
+	return mockArgs.Error(0)
+}
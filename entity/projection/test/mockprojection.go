@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/entity/projection"
 )
 
 type MockProjection struct {
@@ -43,3 +44,65 @@ func (projection *MockProjection) HandleEvents(height int64, events []entity_eve
 
 	return mockArgs.Error(0)
 }
+
+type MockRollbackableProjection struct {
+	MockProjection
+}
+
+func NewMockRollbackableProjection() *MockRollbackableProjection {
+	return &MockRollbackableProjection{}
+}
+
+func (projection *MockRollbackableProjection) RollbackTo(height int64) error {
+	mockArgs := projection.Called(height)
+
+	return mockArgs.Error(0)
+}
+
+type MockResettableProjection struct {
+	MockProjection
+}
+
+func NewMockResettableProjection() *MockResettableProjection {
+	return &MockResettableProjection{}
+}
+
+func (projection *MockResettableProjection) Reset() error {
+	mockArgs := projection.Called()
+
+	return mockArgs.Error(0)
+}
+
+type MockVersionBuilder struct {
+	MockProjection
+}
+
+func NewMockVersionBuilder() *MockVersionBuilder {
+	return &MockVersionBuilder{}
+}
+
+func (projection *MockVersionBuilder) LiveProjectionId() string {
+	mockArgs := projection.Called()
+
+	return mockArgs.String(0)
+}
+
+func (projection *MockVersionBuilder) SwapIn() error {
+	mockArgs := projection.Called()
+
+	return mockArgs.Error(0)
+}
+
+type MockBatchHandlerProjection struct {
+	MockProjection
+}
+
+func NewMockBatchHandlerProjection() *MockBatchHandlerProjection {
+	return &MockBatchHandlerProjection{}
+}
+
+func (projection *MockBatchHandlerProjection) HandleEventsBatch(batch []projection.HeightEvents) error {
+	mockArgs := projection.Called(batch)
+
+	return mockArgs.Error(0)
+}
@@ -7,31 +7,55 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
 
 	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	"github.com/crypto-com/chain-indexing/infrastructure/endpointpool"
 )
 
 var _ cosmosapp_interface.Client = &HTTPClient{}
 
+// BLOCK_HEIGHT_HEADER is the gRPC gateway header to query the state at a block height
+const BLOCK_HEIGHT_HEADER = "x-cosmos-block-height"
+
 type HTTPClient struct {
 	httpClient *http.Client
-	rpcUrl     string
+	endpoints  *endpointpool.Pool
 }
 
 // NewHTTPClient returns a new HTTPClient for tendermint request
 func NewHTTPClient(rpcUrl string) *HTTPClient {
+	return NewMultiEndpointHTTPClient([]string{rpcUrl})
+}
+
+// NewMultiEndpointHTTPClient returns a new HTTPClient which fails over among the Cosmos app endpoints.
+// All endpoints must report the same chain id.
+func NewMultiEndpointHTTPClient(rpcUrls []string) *HTTPClient {
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	return &HTTPClient{
-		httpClient,
-		strings.TrimSuffix(rpcUrl, "/"),
+	client := &HTTPClient{
+		httpClient: httpClient,
 	}
+	client.endpoints = endpointpool.NewPool(rpcUrls, client.chainID)
+
+	return client
+}
+
+// WithChainID pins the chain id which every endpoint has to report, see endpointpool.Pool.WithChainID
+func (client *HTTPClient) WithChainID(chainID string) *HTTPClient {
+	client.endpoints.WithChainID(chainID)
+	return client
+}
+
+// VerifyChainID checks that the endpoints serve the same chain, see endpointpool.Pool.VerifyChainID
+func (client *HTTPClient) VerifyChainID() error {
+	return client.endpoints.VerifyChainID()
 }
 
 func (client *HTTPClient) Account(accountAddress string) (*cosmosapp_interface.Account, error) {
@@ -54,14 +78,18 @@ func (client *HTTPClient) Account(accountAddress string) (*cosmosapp_interface.A
 		return nil, fmt.Errorf("GetAccountInfo error json.Unmarshal %v", err)
 	}
 
+	thisAccount := myjson["account"].(map[string]interface{})
+	return parseAccount(thisAccount), nil
+}
+
+// parseAccount parses normal or module account from the account JSON
+func parseAccount(thisAccount map[string]interface{}) *cosmosapp_interface.Account {
 	var thisAccountType string
 	var thisAddress string
-	var thisPubkeyContainer map[string]interface{}
 	var thisPubkey string
 	var thisAccountNumber string
 	var thisSequenceNumber string
 
-	thisAccount := myjson["account"].(map[string]interface{})
 	thisAccountTypeMeta := thisAccount["@type"].(string)
 	thisAccountTypeName, thisAccountTypeNameOk := thisAccount["name"].(string)
 	if !thisAccountTypeNameOk {
@@ -73,8 +101,10 @@ func (client *HTTPClient) Account(accountAddress string) (*cosmosapp_interface.A
 	if !thisBaseAccountOk {
 		// normal account
 		thisAddress = thisAccount["address"].(string)
-		thisPubkeyContainer = thisAccount["pub_key"].(map[string]interface{})
-		thisPubkey = thisPubkeyContainer["key"].(string)
+		// pubkey is null until the account has sent a transaction
+		if thisPubkeyContainer, ok := thisAccount["pub_key"].(map[string]interface{}); ok {
+			thisPubkey = thisPubkeyContainer["key"].(string)
+		}
 		thisAccountNumber = thisAccount["account_number"].(string)
 		thisSequenceNumber = thisAccount["sequence"].(string)
 
@@ -93,7 +123,7 @@ func (client *HTTPClient) Account(accountAddress string) (*cosmosapp_interface.A
 	accountResp.Account.AccountNumber = thisAccountNumber
 	accountResp.Account.SequenceNumber = thisSequenceNumber
 
-	return &accountResp.Account, nil
+	return &accountResp.Account
 }
 
 func (client *HTTPClient) Balance(targetAddress string, targetDenom string) (*cosmosapp_interface.AccountBalance, error) {
@@ -181,35 +211,202 @@ func (client *HTTPClient) Delegation(
 	return nil, nil
 }
 
+func (client *HTTPClient) Validators(height int64) ([]cosmosapp_interface.Validator, error) {
+	validators := make([]cosmosapp_interface.Validator, 0)
+	var maybeNextKey *string
+	for {
+		var resp ValidatorsResp
+		if err := client.requestPageAtHeight(
+			&resp, height, client.url("staking", "validators"), maybeNextKey,
+		); err != nil {
+			return nil, err
+		}
+		validators = append(validators, resp.Validators...)
+
+		if maybeNextKey = resp.Pagination.MaybeNextKey; maybeNextKey == nil {
+			break
+		}
+	}
+
+	return validators, nil
+}
+
+func (client *HTTPClient) ValidatorSet(height int64) ([]cosmosapp_interface.ValidatorSetValidator, error) {
+	method := "cosmos/base/tendermint/v1beta1/validatorsets/latest"
+	if height > 0 {
+		method = fmt.Sprintf("cosmos/base/tendermint/v1beta1/validatorsets/%d", height)
+	}
+
+	validators := make([]cosmosapp_interface.ValidatorSetValidator, 0)
+	var maybeNextKey *string
+	for {
+		var resp ValidatorSetResp
+		if err := client.requestPageAtHeight(&resp, 0, method, maybeNextKey); err != nil {
+			return nil, err
+		}
+		validators = append(validators, resp.Validators...)
+
+		if maybeNextKey = resp.Pagination.MaybeNextKey; maybeNextKey == nil {
+			break
+		}
+	}
+
+	return validators, nil
+}
+
+func (client *HTTPClient) Accounts(height int64) ([]cosmosapp_interface.Account, error) {
+	accounts := make([]cosmosapp_interface.Account, 0)
+	var maybeNextKey *string
+	for {
+		var resp AccountsResp
+		if err := client.requestPageAtHeight(
+			&resp, height, client.url("auth", "accounts"), maybeNextKey,
+		); err != nil {
+			return nil, err
+		}
+		for _, rawAccount := range resp.Accounts {
+			accounts = append(accounts, *parseAccount(rawAccount))
+		}
+
+		if maybeNextKey = resp.Pagination.MaybeNextKey; maybeNextKey == nil {
+			break
+		}
+	}
+
+	return accounts, nil
+}
+
+func (client *HTTPClient) Balances(
+	accountAddress string, height int64,
+) ([]cosmosapp_interface.AccountBalance, error) {
+	balances := make([]cosmosapp_interface.AccountBalance, 0)
+	var maybeNextKey *string
+	for {
+		var resp BalancesResp
+		if err := client.requestPageAtHeight(
+			&resp, height, fmt.Sprintf("%s/%s", client.url("bank", "balances"), accountAddress), maybeNextKey,
+		); err != nil {
+			return nil, err
+		}
+		for _, balance := range resp.Balances {
+			balances = append(balances, cosmosapp_interface.AccountBalance{
+				AccountAmount: balance.Amount,
+				AccountDenom:  balance.Denom,
+			})
+		}
+
+		if maybeNextKey = resp.Pagination.MaybeNextKey; maybeNextKey == nil {
+			break
+		}
+	}
+
+	return balances, nil
+}
+
+// requestPageAtHeight requests a page of the paginated method at the height and decodes the response
+// into resp
+func (client *HTTPClient) requestPageAtHeight(
+	resp interface{}, height int64, method string, maybeNextKey *string,
+) error {
+	queryString := make([]string, 0)
+	if maybeNextKey != nil {
+		queryString = append(queryString, "pagination.key="+url.QueryEscape(*maybeNextKey))
+	}
+
+	rawRespBody, err := client.requestAtHeight(height, method, queryString...)
+	if err != nil {
+		return err
+	}
+	defer rawRespBody.Close()
+
+	if err := jsoniter.NewDecoder(rawRespBody).Decode(resp); err != nil {
+		return fmt.Errorf("error decoding %s response: %v", method, err)
+	}
+	return nil
+}
+
 func (client *HTTPClient) url(module string, method string) string {
 	return fmt.Sprintf("cosmos/%s/v1beta1/%s", module, method)
 }
 
-// request construct tendermint url and issues an HTTP request
+// request issues an HTTP request to the healthiest endpoint and fails over to the others on
+// connection error or unavailable endpoint.
 // returns the success http Body
 func (client *HTTPClient) request(method string, queryString ...string) (io.ReadCloser, error) {
+	return client.requestAtHeight(0, method, queryString...)
+}
+
+// requestAtHeight issues an HTTP request querying the state at the block height, 0 means the latest
+// state
+func (client *HTTPClient) requestAtHeight(height int64, method string, queryString ...string) (io.ReadCloser, error) {
+	return client.endpoints.Request(func(endpointURL string) (io.ReadCloser, bool, error) {
+		return client.requestEndpointAtHeight(endpointURL, height, method, queryString...)
+	})
+}
+
+// requestEndpoint construct tendermint url and issues an HTTP request to the endpoint. It returns
+// whether the error is caused by the endpoint being unavailable.
+func (client *HTTPClient) requestEndpoint(
+	endpointURL string, method string, queryString ...string,
+) (io.ReadCloser, bool, error) {
+	return client.requestEndpointAtHeight(endpointURL, 0, method, queryString...)
+}
+
+func (client *HTTPClient) requestEndpointAtHeight(
+	endpointURL string, height int64, method string, queryString ...string,
+) (io.ReadCloser, bool, error) {
 	var err error
 
-	url := client.rpcUrl + "/" + method
+	url := endpointURL + "/" + method
 	if len(queryString) > 0 {
 		url += "?" + queryString[0]
 	}
 
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating HTTP request with context: %v", err)
+		return nil, false, fmt.Errorf("error creating HTTP request with context: %v", err)
+	}
+	if height > 0 {
+		req.Header.Set(BLOCK_HEIGHT_HEADER, strconv.FormatInt(height, 10))
 	}
 	rawResp, err := client.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %v", url, err)
+		return nil, true, fmt.Errorf("error requesting Tendermint %s endpoint: %v", url, err)
 	}
 
 	if rawResp.StatusCode != 200 {
 		rawResp.Body.Close()
-		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %s", method, rawResp.Status)
+		return nil, endpointpool.IsUnavailableStatus(rawResp.StatusCode), fmt.Errorf(
+			"error requesting Tendermint %s endpoint: %s", method, rawResp.Status,
+		)
+	}
+
+	return rawResp.Body, false, nil
+}
+
+// chainID requests the node info of the endpoint and returns the chain id it reports
+func (client *HTTPClient) chainID(endpointURL string) (string, error) {
+	rawRespBody, _, err := client.requestEndpoint(endpointURL, "cosmos/base/tendermint/v1beta1/node_info")
+	if err != nil {
+		return "", err
+	}
+	defer rawRespBody.Close()
+
+	var nodeInfoResp NodeInfoResp
+	if err := jsoniter.NewDecoder(rawRespBody).Decode(&nodeInfoResp); err != nil {
+		return "", fmt.Errorf("error decoding node info response: %v", err)
+	}
+	if nodeInfoResp.DefaultNodeInfo.Network == "" {
+		return "", fmt.Errorf("missing chain id in node info response of %s", endpointURL)
 	}
 
-	return rawResp.Body, nil
+	return nodeInfoResp.DefaultNodeInfo.Network, nil
+}
+
+type NodeInfoResp struct {
+	DefaultNodeInfo struct {
+		Network string `json:"network"`
+	} `json:"default_node_info"`
 }
 
 type ValidatorResp struct {
@@ -221,6 +418,32 @@ type DelegationResp struct {
 	Pagination          cosmosapp_interface.Pagination           `json:"pagination"`
 }
 
+type ValidatorsResp struct {
+	Validators []cosmosapp_interface.Validator `json:"validators"`
+	Pagination cosmosapp_interface.Pagination  `json:"pagination"`
+}
+
+type ValidatorSetResp struct {
+	BlockHeight string                                      `json:"block_height"`
+	Validators  []cosmosapp_interface.ValidatorSetValidator `json:"validators"`
+	Pagination  cosmosapp_interface.Pagination              `json:"pagination"`
+}
+
+type AccountsResp struct {
+	Accounts   []map[string]interface{}       `json:"accounts"`
+	Pagination cosmosapp_interface.Pagination `json:"pagination"`
+}
+
+type BalancesResp struct {
+	Balances   []Coin                         `json:"balances"`
+	Pagination cosmosapp_interface.Pagination `json:"pagination"`
+}
+
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
 type AccountResp struct {
 	Account cosmosapp_interface.Account
 }
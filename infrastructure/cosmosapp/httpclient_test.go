@@ -0,0 +1,192 @@
+package cosmosapp_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	cosmosapp_interface "github.com/crypto-com/chain-indexing/appinterface/cosmosapp"
+	. "github.com/crypto-com/chain-indexing/infrastructure/cosmosapp"
+)
+
+const NODE_INFO_JSON = `{"default_node_info":{"network":"testnet-croeseid-2"}}`
+
+const VALIDATORS_PAGE_1_JSON = `{
+  "validators": [
+    {
+      "operator_address": "tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus",
+      "consensus_pubkey": {
+        "@type": "/cosmos.crypto.ed25519.PubKey",
+        "key": "na51D8RmKXyWrid9I6wtdxgP6f1Nl3EyNNEzqxVquoM="
+      },
+      "jailed": false,
+      "status": "BOND_STATUS_BONDED",
+      "tokens": "100000000",
+      "delegator_shares": "100000000.000000000000000000",
+      "description": {"moniker": "node0"},
+      "unbonding_height": "0",
+      "unbonding_time": "1970-01-01T00:00:00Z",
+      "commission": {
+        "commission_rates": {"rate": "0.1", "max_rate": "0.2", "max_change_rate": "0.01"},
+        "update_time": "2020-11-01T00:00:00Z"
+      },
+      "min_self_delegation": "1"
+    }
+  ],
+  "pagination": {"next_key": "AQI=", "total": "2"}
+}`
+
+const VALIDATORS_PAGE_2_JSON = `{
+  "validators": [
+    {
+      "operator_address": "tcrocncl1j7pej8kplem4wt50p4hfvndhuw5jprxxn5625q",
+      "consensus_pubkey": {
+        "@type": "/cosmos.crypto.ed25519.PubKey",
+        "key": "tWY6qzpOg/6HFj2X3a8+tzIAehW7k2MWOgrjotcWCuI="
+      },
+      "jailed": true,
+      "status": "BOND_STATUS_UNBONDED",
+      "tokens": "0",
+      "delegator_shares": "0",
+      "description": {"moniker": "node1"},
+      "unbonding_height": "0",
+      "unbonding_time": "1970-01-01T00:00:00Z",
+      "commission": {
+        "commission_rates": {"rate": "0.1", "max_rate": "0.2", "max_change_rate": "0.01"},
+        "update_time": "2020-11-01T00:00:00Z"
+      },
+      "min_self_delegation": "1"
+    }
+  ],
+  "pagination": {"next_key": null, "total": "2"}
+}`
+
+const ACCOUNTS_JSON = `{
+  "accounts": [
+    {
+      "@type": "/cosmos.auth.v1beta1.BaseAccount",
+      "address": "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn",
+      "pub_key": null,
+      "account_number": "1",
+      "sequence": "0"
+    },
+    {
+      "@type": "/cosmos.auth.v1beta1.ModuleAccount",
+      "base_account": {
+        "address": "tcro1jv65s3grqf6v6jl3dp4t6c9t9rk99cd8lyv94w",
+        "pub_key": null,
+        "account_number": "2",
+        "sequence": "0"
+      },
+      "name": "distribution",
+      "permissions": []
+    }
+  ],
+  "pagination": {"next_key": null, "total": "2"}
+}`
+
+const BALANCES_JSON = `{
+  "balances": [{"denom": "basetcro", "amount": "100000000"}],
+  "pagination": {"next_key": null, "total": "1"}
+}`
+
+var _ = Describe("HTTPClient", func() {
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.RouteToHandler("GET", "/cosmos/base/tendermint/v1beta1/node_info", ghttp.RespondWith(
+			http.StatusOK, NODE_INFO_JSON,
+		))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Validators", func() {
+		It("should request all pages of validators at the height", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/cosmos/staking/v1beta1/validators"),
+					ghttp.VerifyHeaderKV(BLOCK_HEIGHT_HEADER, "100"),
+					ghttp.RespondWith(http.StatusOK, VALIDATORS_PAGE_1_JSON),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/cosmos/staking/v1beta1/validators", "pagination.key=AQI%3D"),
+					ghttp.VerifyHeaderKV(BLOCK_HEIGHT_HEADER, "100"),
+					ghttp.RespondWith(http.StatusOK, VALIDATORS_PAGE_2_JSON),
+				),
+			)
+
+			client := NewHTTPClient(server.URL())
+			validators, err := client.Validators(100)
+			Expect(err).To(BeNil())
+			Expect(validators).To(HaveLen(2))
+			Expect(validators[0].OperatorAddress).To(Equal("tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus"))
+			Expect(validators[0].ConsensusPubkey).To(Equal(cosmosapp_interface.PubKey{
+				Type: "/cosmos.crypto.ed25519.PubKey",
+				Key:  "na51D8RmKXyWrid9I6wtdxgP6f1Nl3EyNNEzqxVquoM=",
+			}))
+			Expect(validators[1].Jailed).To(BeTrue())
+		})
+	})
+
+	Describe("Accounts", func() {
+		It("should parse normal and module accounts", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/cosmos/auth/v1beta1/accounts"),
+					ghttp.VerifyHeaderKV(BLOCK_HEIGHT_HEADER, "100"),
+					ghttp.RespondWith(http.StatusOK, ACCOUNTS_JSON),
+				),
+			)
+
+			client := NewHTTPClient(server.URL())
+			accounts, err := client.Accounts(100)
+			Expect(err).To(BeNil())
+			Expect(accounts).To(Equal([]cosmosapp_interface.Account{
+				{
+					AccountType:    "/cosmos.auth.v1beta1.BaseAccount ",
+					AccountAddress: "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn",
+					Pubkey:         "",
+					AccountNumber:  "1",
+					SequenceNumber: "0",
+				},
+				{
+					AccountType:    "/cosmos.auth.v1beta1.ModuleAccount distribution",
+					AccountAddress: "tcro1jv65s3grqf6v6jl3dp4t6c9t9rk99cd8lyv94w",
+					Pubkey:         "",
+					AccountNumber:  "2",
+					SequenceNumber: "0",
+				},
+			}))
+		})
+	})
+
+	Describe("Balances", func() {
+		It("should request the latest balances when height is 0", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/cosmos/bank/v1beta1/balances/tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn"),
+					func(_ http.ResponseWriter, req *http.Request) {
+						Expect(req.Header.Get(BLOCK_HEIGHT_HEADER)).To(BeEmpty())
+					},
+					ghttp.RespondWith(http.StatusOK, BALANCES_JSON),
+				),
+			)
+
+			client := NewHTTPClient(server.URL())
+			balances, err := client.Balances("tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn", 0)
+			Expect(err).To(BeNil())
+			Expect(balances).To(Equal([]cosmosapp_interface.AccountBalance{
+				{
+					AccountAmount: "100000000",
+					AccountDenom:  "basetcro",
+				},
+			}))
+		})
+	})
+})
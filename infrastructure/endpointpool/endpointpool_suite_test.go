@@ -0,0 +1,13 @@
+package endpointpool_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEndpointPool(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EndpointPool Suite")
+}
@@ -0,0 +1,340 @@
+package endpointpool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DEFAULT_HEALTH_CHECK_INTERVAL = 30 * time.Second
+
+// UNVERIFIED_RECHECK_INTERVAL is the minimum interval between chain id checks of an endpoint not yet
+// verified or serving a different chain, so that an unreachable endpoint does not slow down every request
+const UNVERIFIED_RECHECK_INTERVAL = 10 * time.Second
+
+// MAX_CONSECUTIVE_FAILURES is the number of consecutive failures before an endpoint is considered
+// unhealthy. An unhealthy endpoint is only used when no healthy endpoint is available, and becomes
+// healthy again after passing a health check.
+const MAX_CONSECUTIVE_FAILURES = 3
+
+// LATENCY_SMOOTHING_FACTOR is the weight of the latest latency in the moving average
+const LATENCY_SMOOTHING_FACTOR = 0.3
+
+var ErrNoAvailableEndpoint = errors.New("no available endpoint")
+
+// ChainIDChecker requests the endpoint and returns the chain id it reports
+type ChainIDChecker = func(url string) (string, error)
+
+// Pool is a pool of endpoints serving the same chain. Endpoints are ordered by their health score,
+// which is calculated from the request latency and failures. With multiple endpoints, an endpoint is
+// only used after it reports the chain id of the pool, which is either pinned or the chain id reported
+// by the majority of the endpoints that have responded.
+type Pool struct {
+	chainIDChecker ChainIDChecker
+
+	mutex     sync.RWMutex
+	endpoints []*Endpoint
+	chainID   string
+	// isChainIDPinned is true when the chain id is provided instead of decided by the endpoints
+	isChainIDPinned bool
+}
+
+type Endpoint struct {
+	URL string
+
+	verified bool
+	// reportedChainID is the chain id reported by the endpoint, empty when it has never answered
+	reportedChainID string
+	// mismatchedChainID is set when the endpoint serves a different chain. It is not used until it
+	// reports the chain id of the pool when rechecked.
+	mismatchedChainID   string
+	consecutiveFailures int
+	avgLatency          time.Duration
+	lastCheckedAt       time.Time
+}
+
+// NewPool creates a pool of endpoints. Chain id check and health check are disabled when there is
+// only one endpoint.
+func NewPool(urls []string, chainIDChecker ChainIDChecker) *Pool {
+	endpoints := make([]*Endpoint, 0, len(urls))
+	for _, url := range urls {
+		endpoints = append(endpoints, &Endpoint{
+			URL: strings.TrimSuffix(url, "/"),
+
+			verified: len(urls) == 1,
+		})
+	}
+
+	pool := &Pool{
+		chainIDChecker: chainIDChecker,
+
+		endpoints: endpoints,
+	}
+	if len(endpoints) > 1 {
+		go pool.runHealthCheck(DEFAULT_HEALTH_CHECK_INTERVAL)
+	}
+
+	return pool
+}
+
+// WithChainID pins the chain id of the pool, every endpoint including a single one has to report it
+// before it is used. The chain id is decided by the endpoints when it is empty.
+func (pool *Pool) WithChainID(chainID string) *Pool {
+	if chainID == "" {
+		return pool
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.chainID = chainID
+	pool.isChainIDPinned = true
+	for _, endpoint := range pool.endpoints {
+		endpoint.verified = false
+	}
+
+	return pool
+}
+
+// VerifyChainID checks the chain id of every endpoint. It returns Error when any endpoint reports a
+// chain id different from the pool, or when the endpoints report different chain ids without a
+// majority. It is meant to detect misconfigured endpoints on start up.
+func (pool *Pool) VerifyChainID() error {
+	if len(pool.endpoints) == 1 && !pool.isChainIDPinned {
+		return nil
+	}
+	pool.verifyEndpoints(true)
+
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	if pool.chainID == "" {
+		reports := make([]string, 0)
+		reportedChainIDs := make(map[string]bool)
+		for _, endpoint := range pool.endpoints {
+			if endpoint.reportedChainID == "" {
+				continue
+			}
+			reports = append(reports, fmt.Sprintf("%s reports chain id %s", endpoint.URL, endpoint.reportedChainID))
+			reportedChainIDs[endpoint.reportedChainID] = true
+		}
+		if len(reportedChainIDs) > 1 {
+			return fmt.Errorf("endpoints report different chain ids: %s", strings.Join(reports, "; "))
+		}
+		return nil
+	}
+
+	if mismatches := pool.mismatches(); len(mismatches) > 0 {
+		return fmt.Errorf("endpoints report different chain ids: %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// Candidates returns the usable endpoints ordered by preference. Endpoints not yet verified are
+// checked for their chain id first.
+func (pool *Pool) Candidates() ([]*Endpoint, error) {
+	pool.verifyEndpoints(false)
+
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	healthyEndpoints := make([]*Endpoint, 0, len(pool.endpoints))
+	unhealthyEndpoints := make([]*Endpoint, 0)
+	for _, endpoint := range pool.endpoints {
+		if !endpoint.verified {
+			continue
+		}
+
+		if endpoint.consecutiveFailures >= MAX_CONSECUTIVE_FAILURES {
+			unhealthyEndpoints = append(unhealthyEndpoints, endpoint)
+		} else {
+			healthyEndpoints = append(healthyEndpoints, endpoint)
+		}
+	}
+	sort.SliceStable(healthyEndpoints, func(i, j int) bool {
+		return healthyEndpoints[i].isPreferredTo(healthyEndpoints[j])
+	})
+
+	candidates := append(healthyEndpoints, unhealthyEndpoints...)
+	if len(candidates) == 0 {
+		if mismatches := pool.mismatches(); len(mismatches) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNoAvailableEndpoint, strings.Join(mismatches, "; "))
+		}
+		return nil, ErrNoAvailableEndpoint
+	}
+	return candidates, nil
+}
+
+// mismatches describes the endpoints serving a different chain. It should be called with the lock held.
+func (pool *Pool) mismatches() []string {
+	mismatches := make([]string, 0)
+	for _, endpoint := range pool.endpoints {
+		if endpoint.mismatchedChainID != "" {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"%s reports chain id %s instead of %s", endpoint.URL, endpoint.mismatchedChainID, pool.chainID,
+			))
+		}
+	}
+	return mismatches
+}
+
+// EndpointRequest issues request to the endpoint. It returns whether the error is caused by the
+// endpoint being unavailable and the request should be retried on another endpoint.
+type EndpointRequest = func(endpointURL string) (io.ReadCloser, bool, error)
+
+// Request issues the request to the endpoints in order of preference until one of them is available
+func (pool *Pool) Request(request EndpointRequest) (io.ReadCloser, error) {
+	endpoints, err := pool.Candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	var rawRespBody io.ReadCloser
+	for _, endpoint := range endpoints {
+		startTime := time.Now()
+		var shouldFailover bool
+		rawRespBody, shouldFailover, err = request(endpoint.URL)
+		if !shouldFailover {
+			pool.ReportSuccess(endpoint, time.Since(startTime))
+			return rawRespBody, err
+		}
+		pool.ReportFailure(endpoint)
+	}
+
+	return nil, err
+}
+
+// ReportSuccess records a successful request to the endpoint
+func (pool *Pool) ReportSuccess(endpoint *Endpoint, latency time.Duration) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	endpoint.consecutiveFailures = 0
+	if endpoint.avgLatency == 0 {
+		endpoint.avgLatency = latency
+	} else {
+		endpoint.avgLatency = time.Duration(
+			LATENCY_SMOOTHING_FACTOR*float64(latency) + (1-LATENCY_SMOOTHING_FACTOR)*float64(endpoint.avgLatency),
+		)
+	}
+}
+
+// ReportFailure records a failed request to the endpoint
+func (pool *Pool) ReportFailure(endpoint *Endpoint) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	endpoint.consecutiveFailures += 1
+}
+
+// ChainID returns the chain id of the pool, empty when it is not pinned and no majority of the endpoints
+// has reported it yet
+func (pool *Pool) ChainID() string {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	return pool.chainID
+}
+
+func (pool *Pool) runHealthCheck(interval time.Duration) {
+	for {
+		<-time.After(interval)
+		pool.verifyEndpoints(true)
+	}
+}
+
+// verifyEndpoints checks the chain id of endpoints. When `all` is false, only unverified endpoints are
+// checked. Otherwise all endpoints are checked and the result updates their health.
+func (pool *Pool) verifyEndpoints(all bool) {
+	now := time.Now()
+	pool.mutex.Lock()
+	targets := make([]*Endpoint, 0)
+	for _, endpoint := range pool.endpoints {
+		if all || (!endpoint.verified && now.Sub(endpoint.lastCheckedAt) >= UNVERIFIED_RECHECK_INTERVAL) {
+			endpoint.lastCheckedAt = now
+			targets = append(targets, endpoint)
+		}
+	}
+	pool.mutex.Unlock()
+
+	for _, endpoint := range targets {
+		startTime := time.Now()
+		chainID, err := pool.chainIDChecker(endpoint.URL)
+		if err != nil {
+			pool.ReportFailure(endpoint)
+			continue
+		}
+		latency := time.Since(startTime)
+
+		pool.mutex.Lock()
+		endpoint.reportedChainID = chainID
+		pool.mutex.Unlock()
+
+		pool.ReportSuccess(endpoint, latency)
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if pool.chainID == "" {
+		pool.chainID = pool.majorityChainID()
+		if pool.chainID == "" {
+			return
+		}
+	}
+	for _, endpoint := range pool.endpoints {
+		if endpoint.reportedChainID == "" {
+			continue
+		}
+		if endpoint.reportedChainID == pool.chainID {
+			endpoint.verified = true
+			endpoint.mismatchedChainID = ""
+		} else {
+			endpoint.mismatchedChainID = endpoint.reportedChainID
+			endpoint.verified = false
+		}
+	}
+}
+
+// majorityChainID returns the chain id reported by more than half of the endpoints that have responded,
+// empty when there is none. Unreachable endpoints are not counted, so that the endpoints are still
+// verified when some of them are down. It should be called with the lock held.
+func (pool *Pool) majorityChainID() string {
+	reportCounts := make(map[string]int)
+	respondedCount := 0
+	for _, endpoint := range pool.endpoints {
+		if endpoint.reportedChainID == "" {
+			continue
+		}
+		reportCounts[endpoint.reportedChainID] += 1
+		respondedCount += 1
+	}
+	for chainID, count := range reportCounts {
+		if count*2 > respondedCount {
+			return chainID
+		}
+	}
+	return ""
+}
+
+// isPreferredTo compares the health score of endpoints. An endpoint with less recent failures is
+// preferred, followed by the one with lower average latency.
+func (endpoint *Endpoint) isPreferredTo(other *Endpoint) bool {
+	if endpoint.consecutiveFailures != other.consecutiveFailures {
+		return endpoint.consecutiveFailures < other.consecutiveFailures
+	}
+	return endpoint.avgLatency < other.avgLatency
+}
+
+// IsUnavailableStatus returns true when the HTTP status code indicates the endpoint is unavailable and
+// the request should be retried on another endpoint
+func IsUnavailableStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
@@ -0,0 +1,184 @@
+package endpointpool_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/endpointpool"
+)
+
+var _ = Describe("Pool", func() {
+	chainIDCheckerOf := func(chainIDs map[string]string) endpointpool.ChainIDChecker {
+		return func(url string) (string, error) {
+			// endpoints listed later are slower
+			if url == "http://node-b" {
+				<-time.After(10 * time.Millisecond)
+			}
+			chainID, ok := chainIDs[url]
+			if !ok {
+				return "", errors.New("connection refused")
+			}
+			return chainID, nil
+		}
+	}
+	respondWith := func(body string) io.ReadCloser {
+		return ioutil.NopCloser(strings.NewReader(body))
+	}
+
+	It("should not check chain id when there is only one endpoint", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a/"}, chainIDCheckerOf(map[string]string{}))
+
+		endpoints, err := pool.Candidates()
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(HaveLen(1))
+		Expect(endpoints[0].URL).To(Equal("http://node-a"))
+	})
+
+	It("should exclude endpoints reporting a chain id different from the majority", func() {
+		pool := endpointpool.NewPool(
+			[]string{"http://node-a", "http://node-b", "http://node-c"},
+			chainIDCheckerOf(map[string]string{
+				"http://node-a": "crypto-org-chain-mainnet-1",
+				"http://node-b": "testnet-croeseid-1",
+				"http://node-c": "testnet-croeseid-1",
+			}),
+		)
+
+		endpoints, err := pool.Candidates()
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(HaveLen(2))
+		Expect(endpoints[0].URL).To(Equal("http://node-c"))
+		Expect(endpoints[1].URL).To(Equal("http://node-b"))
+		Expect(pool.ChainID()).To(Equal("testnet-croeseid-1"))
+	})
+
+	It("should not use the endpoints until a majority reports the same chain id", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(map[string]string{
+			"http://node-a": "crypto-org-chain-mainnet-1",
+			"http://node-b": "testnet-croeseid-1",
+		}))
+
+		_, err := pool.Candidates()
+		Expect(errors.Is(err, endpointpool.ErrNoAvailableEndpoint)).To(BeTrue())
+		Expect(pool.ChainID()).To(BeEmpty())
+		Expect(pool.VerifyChainID()).To(MatchError(
+			"endpoints report different chain ids: " +
+				"http://node-a reports chain id crypto-org-chain-mainnet-1; " +
+				"http://node-b reports chain id testnet-croeseid-1",
+		))
+	})
+
+	It("should decide the chain id from the endpoints that have responded", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(map[string]string{
+			"http://node-a": "testnet-croeseid-1",
+		}))
+
+		Expect(pool.VerifyChainID()).To(Succeed())
+		Expect(pool.ChainID()).To(Equal("testnet-croeseid-1"))
+		endpoints, err := pool.Candidates()
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(HaveLen(1))
+		Expect(endpoints[0].URL).To(Equal("http://node-a"))
+	})
+
+	It("should use the endpoint serving a different chain once it reports the chain id of the pool", func() {
+		chainIDs := map[string]string{
+			"http://node-a": "testnet-croeseid-1",
+			"http://node-b": "crypto-org-chain-mainnet-1",
+		}
+		pool := endpointpool.NewPool(
+			[]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(chainIDs),
+		).WithChainID("testnet-croeseid-1")
+
+		Expect(pool.VerifyChainID()).NotTo(Succeed())
+		endpoints, err := pool.Candidates()
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(HaveLen(1))
+
+		chainIDs["http://node-b"] = "testnet-croeseid-1"
+		Expect(pool.VerifyChainID()).To(Succeed())
+		endpoints, err = pool.Candidates()
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(HaveLen(2))
+	})
+
+	It("should only use the endpoints reporting the pinned chain id", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a"}, chainIDCheckerOf(map[string]string{
+			"http://node-a": "crypto-org-chain-mainnet-1",
+		})).WithChainID("testnet-croeseid-1")
+
+		_, err := pool.Candidates()
+		Expect(errors.Is(err, endpointpool.ErrNoAvailableEndpoint)).To(BeTrue())
+		Expect(pool.VerifyChainID()).To(MatchError(
+			"endpoints report different chain ids: " +
+				"http://node-a reports chain id crypto-org-chain-mainnet-1 instead of testnet-croeseid-1",
+		))
+	})
+
+	It("should verify the endpoints reporting the same chain id", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(map[string]string{
+			"http://node-a": "testnet-croeseid-1",
+			"http://node-b": "testnet-croeseid-1",
+		})).WithChainID("testnet-croeseid-1")
+
+		Expect(pool.VerifyChainID()).To(Succeed())
+		endpoints, err := pool.Candidates()
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(HaveLen(2))
+	})
+
+	It("should return Error when no endpoint is reachable", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(map[string]string{}))
+
+		_, err := pool.Candidates()
+		Expect(errors.Is(err, endpointpool.ErrNoAvailableEndpoint)).To(BeTrue())
+	})
+
+	It("should fail over to the next endpoint when an endpoint is unavailable", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(map[string]string{
+			"http://node-a": "testnet-croeseid-1",
+			"http://node-b": "testnet-croeseid-1",
+		}))
+
+		requestedURLs := make([]string, 0)
+		request := func(endpointURL string) (io.ReadCloser, bool, error) {
+			requestedURLs = append(requestedURLs, endpointURL)
+			if endpointURL == "http://node-a" {
+				return nil, true, errors.New("connection refused")
+			}
+			return respondWith("OK"), false, nil
+		}
+
+		rawRespBody, err := pool.Request(request)
+		Expect(err).To(BeNil())
+		Expect(ioutil.ReadAll(rawRespBody)).To(Equal([]byte("OK")))
+		Expect(requestedURLs).To(ConsistOf("http://node-a", "http://node-b"))
+
+		// node-a has failed recently and is no longer tried first
+		requestedURLs = make([]string, 0)
+		_, err = pool.Request(request)
+		Expect(err).To(BeNil())
+		Expect(requestedURLs).To(Equal([]string{"http://node-b"}))
+	})
+
+	It("should not fail over when the endpoint responds with a request error", func() {
+		pool := endpointpool.NewPool([]string{"http://node-a", "http://node-b"}, chainIDCheckerOf(map[string]string{
+			"http://node-a": "testnet-croeseid-1",
+			"http://node-b": "testnet-croeseid-1",
+		}))
+
+		requestCount := 0
+		_, err := pool.Request(func(endpointURL string) (io.ReadCloser, bool, error) {
+			requestCount += 1
+			return nil, false, errors.New("height is not available")
+		})
+		Expect(err).To(MatchError("height is not available"))
+		Expect(requestCount).To(Equal(1))
+	})
+})
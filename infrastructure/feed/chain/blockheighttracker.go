@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -12,6 +13,8 @@ import (
 
 const DEFAULT_POLLING_INTERVAL = 5 * time.Second
 
+var _ BlockHeightFeed = &BlockHeightTracker{}
+
 type BlockHeightTracker struct {
 	logger applogger.Logger
 	client tendermint.Client
@@ -38,38 +41,39 @@ func NewBlockHeightTracker(logger applogger.Logger, client tendermint.Client) *B
 		latestBlockHeight: primptr.Int64Nil(),
 	}
 
-	go tracker.Run()
-
 	return tracker
 }
 
-func (tracker *BlockHeightTracker) Run() {
+func (tracker *BlockHeightTracker) Run(ctx context.Context) {
 	for {
+		interval := tracker.pollingInterval
 		height, err := tracker.client.LatestBlockHeight()
 		if err != nil {
 			tracker.logger.Errorf("error getting chain latest block height: %v", err)
-			<-time.After(1 * time.Second)
-			continue
-		}
+			interval = 1 * time.Second
+		} else {
+			tracker.rwMutex.Lock()
+			tracker.latestBlockHeight = &height
+			subscriptions := tracker.subscriptions
+			tracker.rwMutex.Unlock()
 
-		for _, subscription := range tracker.subscriptions {
-			select {
-			case subscription <- height:
-			default:
-				tracker.logger.Info("block subscription channel is blocked, maybe busy?")
-			}
-		}
+			publishBlockHeight(tracker.logger, subscriptions, height)
 
-		tracker.rwMutex.Lock()
-		tracker.latestBlockHeight = &height
-		tracker.rwMutex.Unlock()
+			tracker.logger.Infof("updated chain latest block height: %d", height)
+		}
 
-		tracker.logger.Infof("updated chain latest block height: %d", height)
-		<-time.After(tracker.pollingInterval)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
 func (tracker *BlockHeightTracker) Subscribe(ch chan<- int64) {
+	tracker.rwMutex.Lock()
+	defer tracker.rwMutex.Unlock()
+
 	tracker.subscriptions = append(tracker.subscriptions, ch)
 }
 
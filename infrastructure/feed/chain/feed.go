@@ -0,0 +1,25 @@
+package chain
+
+import (
+	"context"
+
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+// BlockHeightFeed notifies subscribers of the chain latest block height
+type BlockHeightFeed interface {
+	// Run tracks the latest block height until the context is cancelled
+	Run(ctx context.Context)
+	Subscribe(ch chan<- int64)
+	GetLatestBlockHeight() *int64
+}
+
+func publishBlockHeight(logger applogger.Logger, subscriptions []chan<- int64, height int64) {
+	for _, subscription := range subscriptions {
+		select {
+		case subscription <- height:
+		default:
+			logger.Info("block subscription channel is blocked, maybe busy?")
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	"github.com/crypto-com/chain-indexing/internal/primptr"
+)
+
+const DEFAULT_RECONNECT_INTERVAL = 10 * time.Second
+const DEFAULT_WEBSOCKET_READ_TIMEOUT = 60 * time.Second
+
+const NEW_BLOCK_SUBSCRIBE_QUERY = "tm.event='NewBlock'"
+
+var _ BlockHeightFeed = &WebSocketBlockHeightTracker{}
+
+// WebSocketBlockHeightTracker tracks the chain latest block height by subscribing to Tendermint
+// NewBlock events over WebSocket. When the socket drops, it falls back to polling until reconnected.
+type WebSocketBlockHeightTracker struct {
+	logger applogger.Logger
+	client tendermint.Client
+
+	websocketURL      string
+	pollingInterval   time.Duration
+	reconnectInterval time.Duration
+	readTimeout       time.Duration
+
+	subscriptions []chan<- int64
+
+	latestBlockHeight *int64
+	rwMutex           sync.RWMutex
+}
+
+func NewWebSocketBlockHeightTracker(
+	logger applogger.Logger,
+	client tendermint.Client,
+	websocketURL string,
+) *WebSocketBlockHeightTracker {
+	tracker := &WebSocketBlockHeightTracker{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "WebSocketBlockHeightTracker",
+		}),
+		client: client,
+
+		websocketURL:      websocketURL,
+		pollingInterval:   DEFAULT_POLLING_INTERVAL,
+		reconnectInterval: DEFAULT_RECONNECT_INTERVAL,
+		readTimeout:       DEFAULT_WEBSOCKET_READ_TIMEOUT,
+
+		subscriptions: make([]chan<- int64, 0),
+
+		latestBlockHeight: primptr.Int64Nil(),
+	}
+
+	return tracker
+}
+
+// Run subscribes new blocks until the context is cancelled. It polls the latest block height while
+// the WebSocket is disconnected.
+func (tracker *WebSocketBlockHeightTracker) Run(ctx context.Context) {
+	// WebSocket only notifies new blocks, poll once so that latest height is known on start
+	tracker.pollLatestBlockHeight()
+
+	for {
+		if err := tracker.subscribeNewBlock(ctx); err != nil && ctx.Err() == nil {
+			tracker.logger.Errorf(
+				"error subscribing new block from %s, falling back to polling: %v", tracker.websocketURL, err,
+			)
+		}
+
+		reconnectAfter := time.After(tracker.reconnectInterval)
+	PollingLoop:
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			tracker.pollLatestBlockHeight()
+
+			select {
+			case <-reconnectAfter:
+				break PollingLoop
+			case <-time.After(tracker.pollingInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+		tracker.logger.Infof("reconnecting to %s", tracker.websocketURL)
+	}
+}
+
+// subscribeNewBlock blocks and publishes the height of every new block until the connection drops or
+// the context is cancelled
+func (tracker *WebSocketBlockHeightTracker) subscribeNewBlock(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, tracker.websocketURL, nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to websocket: %v", err)
+	}
+	defer conn.Close()
+
+	// unblock reading on cancellation
+	connDoneCh := make(chan struct{})
+	defer close(connDoneCh)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-connDoneCh:
+		}
+	}()
+
+	if err = conn.WriteJSON(newBlockSubscribeRequest()); err != nil {
+		return fmt.Errorf("error sending subscribe request: %v", err)
+	}
+	tracker.logger.Infof("subscribed new block from %s", tracker.websocketURL)
+
+	conn.SetPingHandler(func(appData string) error {
+		if err := conn.SetReadDeadline(time.Now().Add(tracker.readTimeout)); err != nil {
+			return err
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(time.Second))
+	})
+	for {
+		if err = conn.SetReadDeadline(time.Now().Add(tracker.readTimeout)); err != nil {
+			return fmt.Errorf("error setting read deadline: %v", err)
+		}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("error reading message: %v", err)
+		}
+
+		height, err := ParseNewBlockEventHeight(message)
+		if err != nil {
+			return err
+		}
+		// subscribe response and other non NewBlock messages
+		if height == nil {
+			continue
+		}
+
+		tracker.updateLatestBlockHeight(*height)
+	}
+}
+
+func (tracker *WebSocketBlockHeightTracker) pollLatestBlockHeight() {
+	height, err := tracker.client.LatestBlockHeight()
+	if err != nil {
+		tracker.logger.Errorf("error getting chain latest block height: %v", err)
+		return
+	}
+
+	tracker.updateLatestBlockHeight(height)
+}
+
+func (tracker *WebSocketBlockHeightTracker) updateLatestBlockHeight(height int64) {
+	tracker.rwMutex.Lock()
+	if tracker.latestBlockHeight != nil && *tracker.latestBlockHeight >= height {
+		tracker.rwMutex.Unlock()
+		return
+	}
+	tracker.latestBlockHeight = &height
+	subscriptions := tracker.subscriptions
+	tracker.rwMutex.Unlock()
+
+	publishBlockHeight(tracker.logger, subscriptions, height)
+
+	tracker.logger.Infof("updated chain latest block height: %d", height)
+}
+
+func (tracker *WebSocketBlockHeightTracker) Subscribe(ch chan<- int64) {
+	tracker.rwMutex.Lock()
+	defer tracker.rwMutex.Unlock()
+
+	tracker.subscriptions = append(tracker.subscriptions, ch)
+}
+
+func (tracker *WebSocketBlockHeightTracker) GetLatestBlockHeight() *int64 {
+	tracker.rwMutex.RLock()
+	defer tracker.rwMutex.RUnlock()
+
+	return tracker.latestBlockHeight
+}
+
+// WebSocketURLFromHTTPRPCURL returns the Tendermint WebSocket endpoint of a HTTP RPC URL
+func WebSocketURLFromHTTPRPCURL(httpRPCURL string) (string, error) {
+	rpcURL, err := url.Parse(httpRPCURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing HTTP RPC URL: %v", err)
+	}
+
+	switch rpcURL.Scheme {
+	case "http":
+		rpcURL.Scheme = "ws"
+	case "https":
+		rpcURL.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("unsupported HTTP RPC URL scheme: %s", rpcURL.Scheme)
+	}
+	rpcURL.Path = strings.TrimSuffix(rpcURL.Path, "/") + "/websocket"
+
+	return rpcURL.String(), nil
+}
+
+// ParseNewBlockEventHeight returns the block height of a NewBlock event message, nil when the
+// message is not a NewBlock event
+func ParseNewBlockEventHeight(message []byte) (*int64, error) {
+	var resp NewBlockEventResp
+	if err := jsoniter.Unmarshal(message, &resp); err != nil {
+		return nil, fmt.Errorf("error decoding websocket message: %v", err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("error from websocket: %s", string(*resp.Error))
+	}
+	if resp.Result.Data.Type != "tendermint/event/NewBlock" {
+		return nil, nil
+	}
+
+	height, err := strconv.ParseInt(resp.Result.Data.Value.Block.Header.Height, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing NewBlock event height: %v", err)
+	}
+	return &height, nil
+}
+
+func newBlockSubscribeRequest() map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "subscribe",
+		"id":      0,
+		"params": map[string]interface{}{
+			"query": NEW_BLOCK_SUBSCRIBE_QUERY,
+		},
+	}
+}
+
+type NewBlockEventResp struct {
+	Error  *jsoniter.RawMessage `json:"error"`
+	Result struct {
+		Data struct {
+			Type  string `json:"type"`
+			Value struct {
+				Block struct {
+					Header struct {
+						Height string `json:"height"`
+					} `json:"header"`
+				} `json:"block"`
+			} `json:"value"`
+		} `json:"data"`
+	} `json:"result"`
+}
@@ -0,0 +1,170 @@
+package chain_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	. "github.com/crypto-com/chain-indexing/infrastructure/feed/chain"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+)
+
+const NEW_BLOCK_EVENT_JSON = `{
+  "jsonrpc": "2.0",
+  "id": 0,
+  "result": {
+    "query": "tm.event='NewBlock'",
+    "data": {
+      "type": "tendermint/event/NewBlock",
+      "value": {
+        "block": {
+          "header": {
+            "chain_id": "testnet-croeseid-1",
+            "height": "100"
+          }
+        }
+      }
+    }
+  }
+}`
+
+const SUBSCRIBE_RESPONSE_JSON = `{"jsonrpc": "2.0", "id": 0, "result": {}}`
+
+var _ = Describe("WebSocketBlockHeightTracker", func() {
+	Describe("WebSocketURLFromHTTPRPCURL", func() {
+		It("should return WebSocket endpoint of the HTTP RPC URL", func() {
+			Expect(WebSocketURLFromHTTPRPCURL("http://localhost:26657")).To(Equal("ws://localhost:26657/websocket"))
+			Expect(WebSocketURLFromHTTPRPCURL("https://localhost:26657/")).To(Equal("wss://localhost:26657/websocket"))
+		})
+
+		It("should return Error when the URL scheme is unsupported", func() {
+			_, err := WebSocketURLFromHTTPRPCURL("tcp://localhost:26657")
+			Expect(err).To(MatchError("unsupported HTTP RPC URL scheme: tcp"))
+		})
+	})
+
+	Describe("ParseNewBlockEventHeight", func() {
+		It("should return nil when the message is not a NewBlock event", func() {
+			Expect(ParseNewBlockEventHeight([]byte(SUBSCRIBE_RESPONSE_JSON))).To(BeNil())
+		})
+
+		It("should return the block height of NewBlock event", func() {
+			height, err := ParseNewBlockEventHeight([]byte(NEW_BLOCK_EVENT_JSON))
+			Expect(err).To(BeNil())
+			Expect(*height).To(Equal(int64(100)))
+		})
+	})
+
+	It("should publish block height from NewBlock event", func() {
+		upgrader := websocket.Upgrader{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			// subscribe request
+			if _, _, err = conn.ReadMessage(); err != nil {
+				return
+			}
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(SUBSCRIBE_RESPONSE_JSON))
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(NEW_BLOCK_EVENT_JSON))
+			<-time.After(time.Second)
+		}))
+		defer server.Close()
+
+		websocketURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/websocket"
+		tracker := NewWebSocketBlockHeightTracker(NewFakeLogger(), &fakeTendermintClient{1}, websocketURL)
+		blockHeightCh := make(chan int64, 2)
+		tracker.Subscribe(blockHeightCh)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		Eventually(func() *int64 {
+			return tracker.GetLatestBlockHeight()
+		}).Should(PointTo(Equal(int64(100))))
+		Expect(<-blockHeightCh).To(Equal(int64(1)))
+		Expect(<-blockHeightCh).To(Equal(int64(100)))
+	})
+
+	It("should fallback to polling when WebSocket is unavailable", func() {
+		tracker := NewWebSocketBlockHeightTracker(
+			NewFakeLogger(), &fakeTendermintClient{10}, "ws://127.0.0.1:1/websocket",
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		Eventually(func() *int64 {
+			return tracker.GetLatestBlockHeight()
+		}).Should(PointTo(Equal(int64(10))))
+	})
+
+	It("should stop when the context is cancelled while subscribing", func() {
+		upgrader := websocket.Upgrader{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			// keep the connection open without new block
+			for {
+				if _, _, err = conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}))
+		defer server.Close()
+
+		websocketURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/websocket"
+		tracker := NewWebSocketBlockHeightTracker(NewFakeLogger(), &fakeTendermintClient{1}, websocketURL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		doneCh := make(chan struct{})
+		go func() {
+			tracker.Run(ctx)
+			close(doneCh)
+		}()
+		Eventually(func() *int64 {
+			return tracker.GetLatestBlockHeight()
+		}).Should(PointTo(Equal(int64(1))))
+
+		cancel()
+		Eventually(doneCh).Should(BeClosed())
+	})
+})
+
+type fakeTendermintClient struct {
+	latestBlockHeight int64
+}
+
+func (client *fakeTendermintClient) Genesis() (*genesis.Genesis, error) {
+	return nil, nil
+}
+
+func (client *fakeTendermintClient) Block(_ int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	return nil, nil, nil
+}
+
+func (client *fakeTendermintClient) BlockResults(_ int64) (*usecase_model.BlockResults, error) {
+	return nil, nil
+}
+
+func (client *fakeTendermintClient) LatestBlockHeight() (int64, error) {
+	return client.latestBlockHeight, nil
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+
+	event_interface "github.com/crypto-com/chain-indexing/appinterface/event"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+type EventDigests struct {
+	logger applogger.Logger
+
+	digestStore *event_interface.RDbDigestStore
+}
+
+func NewEventDigests(logger applogger.Logger, rdbHandle *rdb.Handle) *EventDigests {
+	return &EventDigests{
+		logger.WithFields(applogger.LogFields{
+			"module": "EventDigestsHandler",
+		}),
+
+		event_interface.NewRDbDigestStore(rdbHandle),
+	}
+}
+
+func (handler *EventDigests) FindByHeight(ctx *fasthttp.RequestCtx) {
+	heightParam, _ := ctx.UserValue("height").(string)
+	height, err := strconv.ParseInt(heightParam, 10, 64)
+	if err != nil {
+		httpapi.BadRequest(ctx, errors.New("invalid block height"))
+		return
+	}
+
+	maybeDigest, err := handler.digestStore.FindByHeight(height)
+	if err != nil {
+		handler.logger.Errorf("error finding event digest by height: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	if maybeDigest == nil {
+		httpapi.NotFound(ctx)
+		return
+	}
+
+	httpapi.Success(ctx, EventDigest{
+		Height: height,
+		Digest: *maybeDigest,
+	})
+}
+
+type EventDigest struct {
+	Height int64  `json:"height"`
+	Digest string `json:"digest"`
+}
@@ -0,0 +1,15 @@
+package handlers
+
+// isProjectionEnabled returns true when the projection is in the enabled projection ids, nil enabled
+// projection ids means all projections are enabled
+func isProjectionEnabled(maybeEnabledProjectionIds []string, projectionId string) bool {
+	if maybeEnabledProjectionIds == nil {
+		return true
+	}
+	for _, enabledProjectionId := range maybeEnabledProjectionIds {
+		if enabledProjectionId == projectionId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	proposal_view "github.com/crypto-com/chain-indexing/appinterface/projection/proposal/view"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/view"
+	"github.com/crypto-com/chain-indexing/appinterface/rdb"
+	"github.com/crypto-com/chain-indexing/infrastructure/httpapi"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+type Proposals struct {
+	logger applogger.Logger
+
+	proposalsView *proposal_view.Proposals
+	depositsView  *proposal_view.ProposalDeposits
+	votesView     *proposal_view.ProposalVotes
+}
+
+func NewProposals(logger applogger.Logger, rdbHandle *rdb.Handle) *Proposals {
+	return &Proposals{
+		logger.WithFields(applogger.LogFields{
+			"module": "ProposalsHandler",
+		}),
+
+		proposal_view.NewProposals(rdbHandle),
+		proposal_view.NewProposalDeposits(rdbHandle),
+		proposal_view.NewProposalVotes(rdbHandle),
+	}
+}
+
+func (handler *Proposals) List(ctx *fasthttp.RequestCtx) {
+	pagination, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	filter := proposal_view.ProposalsListFilter{
+		MaybeStatuses: nil,
+	}
+	queryArgs := ctx.QueryArgs()
+	if queryArgs.Has("filter.status") {
+		filter.MaybeStatuses = strings.Split(string(queryArgs.Peek("filter.status")), ",")
+	}
+
+	idOrder := view.ORDER_ASC
+	if queryArgs.Has("order") {
+		if string(queryArgs.Peek("order")) == "id.desc" {
+			idOrder = view.ORDER_DESC
+		}
+	}
+
+	proposals, paginationResult, err := handler.proposalsView.List(
+		filter, proposal_view.ProposalsListOrder{Id: idOrder}, pagination,
+	)
+	if err != nil {
+		handler.logger.Errorf("error listing proposals: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.SuccessWithPagination(ctx, proposals, paginationResult)
+}
+
+func (handler *Proposals) FindById(ctx *fasthttp.RequestCtx) {
+	proposalId, ok := parseProposalIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	proposal, err := handler.proposalsView.FindById(proposalId)
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			httpapi.NotFound(ctx)
+			return
+		}
+		handler.logger.Errorf("error finding proposal by id: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.Success(ctx, proposal)
+}
+
+func (handler *Proposals) ListVotesById(ctx *fasthttp.RequestCtx) {
+	pagination, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	proposalId, ok := parseProposalIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	filter := proposal_view.ProposalVotesListFilter{
+		MaybeOptions: nil,
+	}
+	queryArgs := ctx.QueryArgs()
+	if queryArgs.Has("filter.option") {
+		filter.MaybeOptions = strings.Split(string(queryArgs.Peek("filter.option")), ",")
+	}
+
+	heightOrder := view.ORDER_ASC
+	if queryArgs.Has("order") {
+		if string(queryArgs.Peek("order")) == "height.desc" {
+			heightOrder = view.ORDER_DESC
+		}
+	}
+
+	votes, paginationResult, err := handler.votesView.ListByProposal(
+		proposalId, filter, proposal_view.ProposalVotesListOrder{BlockHeight: heightOrder}, pagination,
+	)
+	if err != nil {
+		handler.logger.Errorf("error listing proposal votes: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.SuccessWithPagination(ctx, votes, paginationResult)
+}
+
+func (handler *Proposals) ListDepositsById(ctx *fasthttp.RequestCtx) {
+	pagination, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	proposalId, ok := parseProposalIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	idOrder := view.ORDER_ASC
+	queryArgs := ctx.QueryArgs()
+	if queryArgs.Has("order") {
+		if string(queryArgs.Peek("order")) == "height.desc" {
+			idOrder = view.ORDER_DESC
+		}
+	}
+
+	deposits, paginationResult, err := handler.depositsView.ListByProposal(
+		proposalId, proposal_view.ProposalDepositsListOrder{Id: idOrder}, pagination,
+	)
+	if err != nil {
+		handler.logger.Errorf("error listing proposal deposits: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.SuccessWithPagination(ctx, deposits, paginationResult)
+}
+
+// parseProposalIdParam returns the proposal id in path, or responds bad request when it is invalid
+func parseProposalIdParam(ctx *fasthttp.RequestCtx) (string, bool) {
+	idParam, _ := ctx.UserValue("id").(string)
+	if _, err := strconv.ParseInt(idParam, 10, 64); err != nil {
+		httpapi.BadRequest(ctx, errors.New("invalid proposal id"))
+		return "", false
+	}
+	return idParam, true
+}
@@ -34,41 +34,65 @@ func NewSearch(logger applogger.Logger, rdbHandle *rdb.Handle) *Search {
 	}
 }
 
+// WithEnabledProjections makes the handler search the views of the enabled projections only, the
+// results of the disabled projections are always empty
+func (search *Search) WithEnabledProjections(maybeEnabledProjectionIds []string) *Search {
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "Block") {
+		search.blocksView = nil
+	}
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "Transaction") {
+		search.transactionsView = nil
+	}
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "Validator") {
+		search.validatorsView = nil
+	}
+	return search
+}
+
 func (search *Search) Search(ctx *fasthttp.RequestCtx) {
 	keyword := string(ctx.QueryArgs().Peek("keyword"))
 
 	var results SearchResults
 
-	blocks, err := search.blocksView.Search(keyword)
-	if err != nil {
-		if errors.Is(err, rdb.ErrNoRows) {
-			blocks = []block_view.Block{}
-		} else {
-			search.logger.Errorf("error searching block: %v", err)
-			httpapi.InternalServerError(ctx)
-			return
+	blocks := []block_view.Block{}
+	if search.blocksView != nil {
+		var err error
+		if blocks, err = search.blocksView.Search(keyword); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				blocks = []block_view.Block{}
+			} else {
+				search.logger.Errorf("error searching block: %v", err)
+				httpapi.InternalServerError(ctx)
+				return
+			}
 		}
 	}
 
-	transactions, err := search.transactionsView.Search(keyword)
-	if err != nil {
-		if errors.Is(err, rdb.ErrNoRows) {
-			transactions = []transaction_view.TransactionRow{}
-		} else {
-			search.logger.Errorf("error searching transaction: %v", err)
-			httpapi.InternalServerError(ctx)
-			return
+	transactions := []transaction_view.TransactionRow{}
+	if search.transactionsView != nil {
+		var err error
+		if transactions, err = search.transactionsView.Search(keyword); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				transactions = []transaction_view.TransactionRow{}
+			} else {
+				search.logger.Errorf("error searching transaction: %v", err)
+				httpapi.InternalServerError(ctx)
+				return
+			}
 		}
 	}
 
-	validators, err := search.validatorsView.Search(keyword)
-	if err != nil {
-		if errors.Is(err, rdb.ErrNoRows) {
-			validators = []validator_view.ValidatorRow{}
-		} else {
-			search.logger.Errorf("error searching validator: %v", err)
-			httpapi.InternalServerError(ctx)
-			return
+	validators := []validator_view.ValidatorRow{}
+	if search.validatorsView != nil {
+		var err error
+		if validators, err = search.validatorsView.Search(keyword); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				validators = []validator_view.ValidatorRow{}
+			} else {
+				search.logger.Errorf("error searching validator: %v", err)
+				httpapi.InternalServerError(ctx)
+				return
+			}
 		}
 	}
 
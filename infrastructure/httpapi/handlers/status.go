@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 	block_view "github.com/crypto-com/chain-indexing/appinterface/projection/block/view"
+	"github.com/crypto-com/chain-indexing/appinterface/projection/deadletter"
 	transaction_view "github.com/crypto-com/chain-indexing/appinterface/projection/transaction/view"
 	"github.com/crypto-com/chain-indexing/appinterface/projection/validator/constants"
 	validator_view "github.com/crypto-com/chain-indexing/appinterface/projection/validator/view"
@@ -24,6 +26,7 @@ type StatusHandler struct {
 	validatorsView        *validator_view.Validators
 	validatorStatsView    *validatorstats_view.ValidatorStats
 	statusView         *status_polling.Status
+	deadLetterQueue       *deadletter.RDbQueue
 
 }
 
@@ -38,54 +41,93 @@ func NewStatusHandler(logger applogger.Logger, rdbHandle *rdb.Handle) *StatusHan
 		validator_view.NewValidators(rdbHandle),
 		validatorstats_view.NewValidatorStats(rdbHandle),
 		status_polling.NewStatus(rdbHandle),
+		deadletter.NewRDbQueue(rdbHandle),
 	}
 }
 
-func (handler *StatusHandler) GetStatus(ctx *fasthttp.RequestCtx) {
-	blockCount, err := handler.blocksView.Count()
-	if err != nil {
-		handler.logger.Errorf("error fetching block count: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+// WithEnabledProjections makes the handler read the views of the enabled projections only, the counts
+// of the disabled projections are omitted from the status
+func (handler *StatusHandler) WithEnabledProjections(maybeEnabledProjectionIds []string) *StatusHandler {
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "Block") {
+		handler.blocksView = nil
 	}
-
-	transactionCount, err := handler.transactionsTotalView.FindBy("-")
-	if err != nil {
-		handler.logger.Errorf("error fetching transaction count: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "Transaction") {
+		handler.transactionsTotalView = nil
+	}
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "Validator") {
+		handler.validatorsView = nil
 	}
+	if !isProjectionEnabled(maybeEnabledProjectionIds, "ValidatorStats") {
+		handler.validatorStatsView = nil
+	}
+	return handler
+}
 
-	totalDelegated, err := handler.validatorStatsView.FindBy(validatorstats.TOTAL_DELEGATE)
-	if err != nil {
-		handler.logger.Errorf("error fetching total delegate: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+func (handler *StatusHandler) GetStatus(ctx *fasthttp.RequestCtx) {
+	var maybeBlockCount *int64
+	if handler.blocksView != nil {
+		blockCount, err := handler.blocksView.Count()
+		if err != nil {
+			handler.logger.Errorf("error fetching block count: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeBlockCount = &blockCount
 	}
 
-	totalReward, err := handler.validatorStatsView.FindBy(validatorstats.TOTAL_REWARD)
-	if err != nil {
-		handler.logger.Errorf("error fetching total reward: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+	var maybeTransactionCount *int64
+	if handler.transactionsTotalView != nil {
+		transactionCount, err := handler.transactionsTotalView.FindBy("-")
+		if err != nil {
+			handler.logger.Errorf("error fetching transaction count: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeTransactionCount = &transactionCount
 	}
 
-	validatorCount, err := handler.validatorsView.Count(validator_view.CountFilter{
-		MaybeStatus: nil,
-	})
-	if err != nil {
-		handler.logger.Errorf("error fetching validators count: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+	var maybeTotalDelegated *string
+	var maybeTotalReward *string
+	if handler.validatorStatsView != nil {
+		totalDelegated, err := handler.validatorStatsView.FindBy(validatorstats.TOTAL_DELEGATE)
+		if err != nil {
+			handler.logger.Errorf("error fetching total delegate: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeTotalDelegated = &totalDelegated
+
+		totalReward, err := handler.validatorStatsView.FindBy(validatorstats.TOTAL_REWARD)
+		if err != nil {
+			handler.logger.Errorf("error fetching total reward: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeTotalReward = &totalReward
 	}
 
-	activeValidatorCount, err := handler.validatorsView.Count(validator_view.CountFilter{
-		MaybeStatus: []string{constants.BONDED, constants.UNBONDING},
-	})
-	if err != nil {
-		handler.logger.Errorf("error fetching active validators count: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+	var maybeValidatorCount *int64
+	var maybeActiveValidatorCount *int64
+	if handler.validatorsView != nil {
+		validatorCount, err := handler.validatorsView.Count(validator_view.CountFilter{
+			MaybeStatus: nil,
+		})
+		if err != nil {
+			handler.logger.Errorf("error fetching validators count: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeValidatorCount = &validatorCount
+
+		activeValidatorCount, err := handler.validatorsView.Count(validator_view.CountFilter{
+			MaybeStatus: []string{constants.BONDED, constants.UNBONDING},
+		})
+		if err != nil {
+			handler.logger.Errorf("error fetching active validators count: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeActiveValidatorCount = &activeValidatorCount
 	}
 
 	latestHeight, err := handler.statusView.FindBy("LatestHeight")
@@ -104,14 +146,50 @@ func (handler *StatusHandler) GetStatus(ctx *fasthttp.RequestCtx) {
 		httpapi.InternalServerError(ctx)
 	}
 
+	// history before the start height is not indexed when indexing starts after genesis
+	var maybeHistoryStartHeight *int64
+	rawHistoryStartHeight, err := handler.statusView.FindBy(status_polling.PARTIAL_HISTORY_START_HEIGHT)
+	if err == nil {
+		historyStartHeight, parseErr := strconv.ParseInt(rawHistoryStartHeight, 10, 64)
+		if parseErr != nil {
+			handler.logger.Errorf("error parsing partial history start height: %v", parseErr)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		maybeHistoryStartHeight = &historyStartHeight
+	} else if !errors.Is(err, rdb.ErrNoRows) {
+		handler.logger.Errorf("error fetching partial history start height: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	deadLetters, err := handler.deadLetterQueue.ListUnresolved()
+	if err != nil {
+		handler.logger.Errorf("error fetching unresolved dead letters: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	degradedProjections := make([]DegradedProjection, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		degradedProjections = append(degradedProjections, DegradedProjection{
+			ProjectionId:      deadLetter.ProjectionId,
+			QuarantinedHeight: deadLetter.Height,
+			Status:            deadLetter.Status,
+			Error:             deadLetter.Error,
+		})
+	}
+
 	status := Status{
-		BlockCount:           blockCount,
-		TransactionCount:     transactionCount,
-		TotalDelegated:       totalDelegated,
-		TotalReward:          totalReward,
-		ValidatorCount:       validatorCount,
-		ActiveValidatorCount: activeValidatorCount,
+		MaybeBlockCount:           maybeBlockCount,
+		MaybeTransactionCount:     maybeTransactionCount,
+		MaybeTotalDelegated:       maybeTotalDelegated,
+		MaybeTotalReward:          maybeTotalReward,
+		MaybeValidatorCount:       maybeValidatorCount,
+		MaybeActiveValidatorCount: maybeActiveValidatorCount,
 		LatestHeight: latestHeightValue,
+		PartialHistory:          maybeHistoryStartHeight != nil,
+		MaybeHistoryStartHeight: maybeHistoryStartHeight,
+		DegradedProjections:     degradedProjections,
 
 	}
 
@@ -119,11 +197,26 @@ func (handler *StatusHandler) GetStatus(ctx *fasthttp.RequestCtx) {
 }
 
 type Status struct {
-	BlockCount           int64  `json:"blockCount"`
-	TransactionCount     int64  `json:"transactionCount"`
-	TotalDelegated       string `json:"totalDelegated"`
-	TotalReward          string `json:"totalReward"`
-	ValidatorCount       int64  `json:"validatorCount"`
-	ActiveValidatorCount int64  `json:"activeValidatorCount"`
+	// The counts of the projections which are not enabled are omitted
+	MaybeBlockCount           *int64  `json:"blockCount,omitempty"`
+	MaybeTransactionCount     *int64  `json:"transactionCount,omitempty"`
+	MaybeTotalDelegated       *string `json:"totalDelegated,omitempty"`
+	MaybeTotalReward          *string `json:"totalReward,omitempty"`
+	MaybeValidatorCount       *int64  `json:"validatorCount,omitempty"`
+	MaybeActiveValidatorCount *int64  `json:"activeValidatorCount,omitempty"`
 	LatestHeight    int64   `json:"latestHeight"`
+	// PartialHistory is true when the indexing starts after genesis, blocks, transactions and
+	// activities before the history start height are not indexed
+	PartialHistory          bool   `json:"partialHistory"`
+	MaybeHistoryStartHeight *int64 `json:"historyStartHeight"`
+	// DegradedProjections are the projections waiting at a height quarantined after failing to handle it,
+	// or halted after chain reorganisation until they are reset
+	DegradedProjections []DegradedProjection `json:"degradedProjections"`
+}
+
+type DegradedProjection struct {
+	ProjectionId      string `json:"projectionId"`
+	QuarantinedHeight int64  `json:"quarantinedHeight"`
+	Status            string `json:"status"`
+	Error             string `json:"error"`
 }
@@ -17,6 +17,11 @@ type RouteRegistry struct {
 	validatorsHandler      *handlers.Validators
 	accountMessagesHandler *handlers.AccountMessages
 	accountsHandler        *handlers.Accounts
+	eventDigestsHandler    *handlers.EventDigests
+	proposalsHandler       *handlers.Proposals
+
+	// maybeEnabledProjectionIds are the projections backing the endpoints, nil means all are enabled
+	maybeEnabledProjectionIds []string
 }
 
 func NewRoutesRegistry(
@@ -28,6 +33,8 @@ func NewRoutesRegistry(
 	validatorsHandler *handlers.Validators,
 	accountMessagesHandler *handlers.AccountMessages,
 	accountsHandler *handlers.Accounts,
+	eventDigestsHandler *handlers.EventDigests,
+	proposalsHandler *handlers.Proposals,
 ) *RouteRegistry {
 	return &RouteRegistry{
 		searchHandler,
@@ -38,7 +45,30 @@ func NewRoutesRegistry(
 		validatorsHandler,
 		accountMessagesHandler,
 		accountsHandler,
+		eventDigestsHandler,
+		proposalsHandler,
+
+		nil,
+	}
+}
+
+// WithEnabledProjections mounts only the endpoints served by the enabled projections. Endpoints not
+// backed by a projection are always mounted.
+func (registry *RouteRegistry) WithEnabledProjections(projectionIds []string) *RouteRegistry {
+	registry.maybeEnabledProjectionIds = projectionIds
+	return registry
+}
+
+func (registry *RouteRegistry) isProjectionEnabled(projectionId string) bool {
+	if registry.maybeEnabledProjectionIds == nil {
+		return true
+	}
+	for _, enabledProjectionId := range registry.maybeEnabledProjectionIds {
+		if enabledProjectionId == projectionId {
+			return true
+		}
 	}
+	return false
 }
 
 func (registry *RouteRegistry) Register(server *httpapi.Server, routePrefix string) {
@@ -51,22 +81,41 @@ func (registry *RouteRegistry) Register(server *httpapi.Server, routePrefix stri
 		ctx.SetBody([]byte("Ok"))
 	})
 	server.GET(fmt.Sprintf("%s/api/v1/search", routePrefix), registry.searchHandler.Search)
-	server.GET(fmt.Sprintf("%s/api/v1/blocks", routePrefix), registry.blocksHandler.List)
-	server.GET(fmt.Sprintf("%s/api/v1/blocks/{height-or-hash}", routePrefix), registry.blocksHandler.FindBy)
-	server.GET(fmt.Sprintf("%s/api/v1/blocks/{height}/transactions", routePrefix), registry.blocksHandler.ListTransactionsByHeight)
-	server.GET(fmt.Sprintf("%s/api/v1/blocks/{height}/events", routePrefix), registry.blocksHandler.ListEventsByHeight)
+	server.GET(fmt.Sprintf("%s/api/v1/blocks/{height}/events/digest", routePrefix), registry.eventDigestsHandler.FindByHeight)
 	server.GET(fmt.Sprintf("%s/api/v1/status", routePrefix), registry.statusHandler.GetStatus)
-	server.GET(fmt.Sprintf("%s/api/v1/transactions", routePrefix), registry.transactionHandler.List)
-	server.GET(fmt.Sprintf("%s/api/v1/transactions/{hash}", routePrefix), registry.transactionHandler.FindByHash)
-	server.GET(fmt.Sprintf("%s/api/v1/events", routePrefix), registry.blockEventHandler.List)
-	server.GET(fmt.Sprintf("%s/api/v1/events/{id}", routePrefix), registry.blockEventHandler.FindById)
-	server.GET(fmt.Sprintf("%s/api/v1/accounts/{account}/messages", routePrefix), registry.accountMessagesHandler.ListByAccount)
-	server.GET(fmt.Sprintf("%s/api/v1/validators", routePrefix), registry.validatorsHandler.List)
-	server.GET(fmt.Sprintf("%s/api/v1/validators/active", routePrefix), registry.validatorsHandler.ListActive)
-	server.GET(fmt.Sprintf("%s/api/v1/validators/{address}", routePrefix), registry.validatorsHandler.FindBy)
-	server.GET(fmt.Sprintf("%s/api/v1/validators/{address}/activities", routePrefix), registry.validatorsHandler.ListActivities)
-	// Account number, sequence number, balance are fetched from the latest state (regardless of current replayed height)
-	server.GET(fmt.Sprintf("%s/api/v1/accounts/info", routePrefix), registry.accountsHandler.List)
-	server.GET(fmt.Sprintf("%s/api/v1/accounts/info/{address}", routePrefix), registry.accountsHandler.FindBy)
+	if registry.isProjectionEnabled("Block") {
+		server.GET(fmt.Sprintf("%s/api/v1/blocks", routePrefix), registry.blocksHandler.List)
+		server.GET(fmt.Sprintf("%s/api/v1/blocks/{height-or-hash}", routePrefix), registry.blocksHandler.FindBy)
+	}
+	if registry.isProjectionEnabled("Transaction") {
+		server.GET(fmt.Sprintf("%s/api/v1/blocks/{height}/transactions", routePrefix), registry.blocksHandler.ListTransactionsByHeight)
+		server.GET(fmt.Sprintf("%s/api/v1/transactions", routePrefix), registry.transactionHandler.List)
+		server.GET(fmt.Sprintf("%s/api/v1/transactions/{hash}", routePrefix), registry.transactionHandler.FindByHash)
+	}
+	if registry.isProjectionEnabled("BlockEvent") {
+		server.GET(fmt.Sprintf("%s/api/v1/blocks/{height}/events", routePrefix), registry.blocksHandler.ListEventsByHeight)
+		server.GET(fmt.Sprintf("%s/api/v1/events", routePrefix), registry.blockEventHandler.List)
+		server.GET(fmt.Sprintf("%s/api/v1/events/{id}", routePrefix), registry.blockEventHandler.FindById)
+	}
+	if registry.isProjectionEnabled("AccountMessage") {
+		server.GET(fmt.Sprintf("%s/api/v1/accounts/{account}/messages", routePrefix), registry.accountMessagesHandler.ListByAccount)
+	}
+	if registry.isProjectionEnabled("Validator") {
+		server.GET(fmt.Sprintf("%s/api/v1/validators", routePrefix), registry.validatorsHandler.List)
+		server.GET(fmt.Sprintf("%s/api/v1/validators/active", routePrefix), registry.validatorsHandler.ListActive)
+		server.GET(fmt.Sprintf("%s/api/v1/validators/{address}", routePrefix), registry.validatorsHandler.FindBy)
+		server.GET(fmt.Sprintf("%s/api/v1/validators/{address}/activities", routePrefix), registry.validatorsHandler.ListActivities)
+	}
+	if registry.isProjectionEnabled("Account") {
+		// Account number, sequence number, balance are fetched from the latest state (regardless of current replayed height)
+		server.GET(fmt.Sprintf("%s/api/v1/accounts/info", routePrefix), registry.accountsHandler.List)
+		server.GET(fmt.Sprintf("%s/api/v1/accounts/info/{address}", routePrefix), registry.accountsHandler.FindBy)
+	}
+	if registry.isProjectionEnabled("Proposal") {
+		server.GET(fmt.Sprintf("%s/api/v1/proposals", routePrefix), registry.proposalsHandler.List)
+		server.GET(fmt.Sprintf("%s/api/v1/proposals/{id}", routePrefix), registry.proposalsHandler.FindById)
+		server.GET(fmt.Sprintf("%s/api/v1/proposals/{id}/votes", routePrefix), registry.proposalsHandler.ListVotesById)
+		server.GET(fmt.Sprintf("%s/api/v1/proposals/{id}/deposits", routePrefix), registry.proposalsHandler.ListDepositsById)
+	}
 
 }
@@ -1,6 +1,10 @@
 package httpapi
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	applogger "github.com/crypto-com/chain-indexing/internal/logger"
 	"github.com/fasthttp/router"
 	"github.com/lab259/cors"
@@ -67,6 +71,40 @@ func (server *Server) WithPanicHandler(handler func(ctx *fasthttp.RequestCtx, er
 }
 
 func (server *Server) ListenAndServe() error {
+	return fasthttp.ListenAndServe(server.listeningAddress, server.handler())
+}
+
+// ListenAndServeWithContext serves until the context is cancelled, then stops accepting connections
+// and waits for the open connections to finish within the shutdown timeout
+func (server *Server) ListenAndServeWithContext(ctx context.Context, shutdownTimeout time.Duration) error {
+	httpServer := &fasthttp.Server{
+		Handler: server.handler(),
+	}
+
+	serveErrCh := make(chan error, 1)
+	go func() {
+		serveErrCh <- httpServer.ListenAndServe(server.listeningAddress)
+	}()
+
+	select {
+	case err := <-serveErrCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownErrCh := make(chan error, 1)
+	go func() {
+		shutdownErrCh <- httpServer.Shutdown()
+	}()
+	select {
+	case err := <-shutdownErrCh:
+		return err
+	case <-time.After(shutdownTimeout):
+		return fmt.Errorf("timeout shutting down HTTP server after %s", shutdownTimeout)
+	}
+}
+
+func (server *Server) handler() fasthttp.RequestHandler {
 	handler := server.router.Handler
 	if server.corsMiddleware != nil {
 		handler = server.corsMiddleware(handler)
@@ -77,7 +115,7 @@ func (server *Server) ListenAndServe() error {
 	for _, middleware := range server.middlewares {
 		handler = middleware(handler)
 	}
-	return fasthttp.ListenAndServe(server.listeningAddress, handler)
+	return handler
 }
 
 type Middleware = func(fasthttp.RequestHandler) fasthttp.RequestHandler
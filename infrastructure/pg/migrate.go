@@ -3,17 +3,20 @@ package pg
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	gomigrate "github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
 )
 
 type Migrate struct {
 	*gomigrate.Migrate
 
-	config       *ConnConfig
-	sourceFolder string
+	// newClient creates the "golang-migrate" client of the migration source
+	newClient func() (*gomigrate.Migrate, error)
 
 	reCreateClientOnReset bool
 }
@@ -28,7 +31,50 @@ func MustNewMigrate(config *ConnConfig, sourceFolder string) *Migrate {
 }
 
 func NewMigrate(config *ConnConfig, sourceFolder string) (*Migrate, error) {
-	m, err := _newMigrate(config, sourceFolder)
+	return NewMigrateWithTable(config, sourceFolder, "")
+}
+
+// NewMigrateWithTable creates a Migrate keeping the applied version in the provided migrations table,
+// so that multiple migration folders can be applied to the same database independently
+func NewMigrateWithTable(config *ConnConfig, sourceFolder string, migrationsTable string) (*Migrate, error) {
+	return newMigrate(func() (*gomigrate.Migrate, error) {
+		return gomigrate.New(
+			fmt.Sprintf("file://%s", sourceFolder),
+			migrationsDatabaseURL(config, migrationsTable),
+		)
+	})
+}
+
+// NewEmbeddedMigrate creates a Migrate of the migrations compiled into the binary, keyed by file name
+// in the format `<version>_<title>.<up|down>.sql`. The applied version is kept in the provided
+// migrations table.
+func NewEmbeddedMigrate(config *ConnConfig, migrations map[string]string, migrationsTable string) (*Migrate, error) {
+	fileNames := make([]string, 0, len(migrations))
+	for fileName := range migrations {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	return newMigrate(func() (*gomigrate.Migrate, error) {
+		sourceDriver, err := bindata.WithInstance(bindata.Resource(fileNames, func(name string) ([]byte, error) {
+			migration, ok := migrations[name]
+			if !ok {
+				return nil, fmt.Errorf("migration %s not found", name)
+			}
+			return []byte(migration), nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		return gomigrate.NewWithSourceInstance(
+			"go-bindata", sourceDriver, migrationsDatabaseURL(config, migrationsTable),
+		)
+	})
+}
+
+func newMigrate(newClient func() (*gomigrate.Migrate, error)) (*Migrate, error) {
+	m, err := newClient()
 	if err != nil {
 		return nil, err
 	}
@@ -37,18 +83,25 @@ func NewMigrate(config *ConnConfig, sourceFolder string) (*Migrate, error) {
 	return &Migrate{
 		m,
 
-		config,
-		sourceFolder,
+		newClient,
 
 		reCreateClientOnReset,
 	}, nil
 }
 
-func _newMigrate(config *ConnConfig, sourceFolder string) (*gomigrate.Migrate, error) {
-	return gomigrate.New(
-		fmt.Sprintf("file://%s", sourceFolder),
-		config.ToURL(),
-	)
+// migrationsDatabaseURL returns the database URL keeping the applied version in migrationsTable, or in
+// the default table "schema_migrations" when it is empty
+func migrationsDatabaseURL(config *ConnConfig, migrationsTable string) string {
+	databaseURL := config.ToURL()
+	if migrationsTable == "" {
+		return databaseURL
+	}
+
+	separator := "?"
+	if strings.Contains(databaseURL, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%sx-migrations-table=%s", databaseURL, separator, migrationsTable)
 }
 
 func (m *Migrate) SetReCreateOnReset(shouldReset bool) {
@@ -109,7 +162,7 @@ func (m *Migrate) Reset() error {
 	// There is a known bug of "golang-migrate" that after `Drop()`, "schema_migrations" won't be
 	// create by `Up()`. The solution is to re-create the "golang-migrate" client
 	if m.reCreateClientOnReset {
-		migrate, err := _newMigrate(m.config, m.sourceFolder)
+		migrate, err := m.newClient()
 		if err != nil {
 			return err
 		}
@@ -174,6 +174,27 @@ var _ = Describe("PgMigrate", func() {
 			})
 		})
 
+		Describe("NewEmbeddedMigrate", func() {
+			It("should execute embedded up migrations and keep the version in the migrations table", func() {
+				migrate, err := NewEmbeddedMigrate(config, map[string]string{
+					"1_tests.up.sql":   "CREATE TABLE tests (id INT NOT NULL, PRIMARY KEY (id));",
+					"1_tests.down.sql": "DROP TABLE tests;",
+				}, "schema_migrations_tests")
+				Expect(err).To(BeNil())
+
+				Expect(migrate.Up()).To(BeNil())
+
+				version, dirty, err := migrate.Version()
+				Expect(version).To(Equal(uint(1)))
+				Expect(dirty).To(BeFalse())
+				Expect(err).To(BeNil())
+
+				Expect(pgConn.IsTableExists("tests")).To(BeTrue())
+				Expect(pgConn.IsTableExists("schema_migrations_tests")).To(BeTrue())
+				Expect(pgConn.IsTableExists("schema_migrations")).To(BeFalse())
+			})
+		})
+
 		Describe("Down", func() {
 			It("should do nothing when no up migration has been executed", func() {
 				var err error
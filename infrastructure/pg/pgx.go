@@ -58,10 +58,15 @@ func (config *PgxConnPoolConfig) ToURL() string {
 }
 
 var _ rdb.Conn = &PgxConn{}
+var _ rdb.Listener = &PgxConn{}
+var _ rdb.Migrator = &PgxConn{}
 
 type PgxConn struct {
 	// pgxConn could be simple connection or connetion pool
 	pgxConn PgxConnLike
+
+	// config is used to connect the migrations to the same database
+	config ConnConfig
 }
 
 func MustNewPgxConn(config *ConnConfig, logger logger.Logger) *PgxConn {
@@ -86,6 +91,8 @@ func NewPgxConn(config *ConnConfig, logger logger.Logger) (*PgxConn, error) {
 
 	return &PgxConn{
 		conn,
+
+		*config,
 	}, nil
 }
 
@@ -111,9 +118,86 @@ func NewPgxConnPool(config *PgxConnPoolConfig, logger logger.Logger) (*PgxConn,
 
 	return &PgxConn{
 		conn,
+
+		config.ConnConfig,
 	}, nil
 }
 
+// Close closes the connection, or all connections in the pool after waiting for acquired connections
+// to be released
+func (conn *PgxConn) Close() error {
+	switch pgxConn := conn.pgxConn.(type) {
+	case *pgxpool.Pool:
+		pgxConn.Close()
+	case *pgx.Conn:
+		return pgxConn.Close(context.Background())
+	}
+	return nil
+}
+
+// Listen listens to the notifications on channel with a dedicated connection acquired from the pool.
+// The connection is released when the context is cancelled or listening fails.
+func (conn *PgxConn) Listen(ctx context.Context, channel string, payloadCh chan<- string) error {
+	pool, ok := conn.pgxConn.(*pgxpool.Pool)
+	if !ok {
+		return errors.New("error listening to notifications: a connection pool is required")
+	}
+
+	pooledConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection to listen: %v", err)
+	}
+	defer func() {
+		// the connection is returned to the pool, it should not keep receiving notifications
+		if !pooledConn.Conn().IsClosed() {
+			_, _ = pooledConn.Exec(context.Background(), "UNLISTEN *")
+		}
+		pooledConn.Release()
+	}()
+
+	if _, err = pooledConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("error listening to channel %s: %v", channel, err)
+	}
+
+	for {
+		notification, err := pooledConn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error waiting for notification on channel %s: %v", channel, err)
+		}
+
+		select {
+		case payloadCh <- notification.Payload:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// MigrateUp applies the migrations not applied yet with a dedicated connection, which is closed
+// afterwards. The applied version is kept in migrationsTable.
+func (conn *PgxConn) MigrateUp(migrations map[string]string, migrationsTable string) error {
+	migrate, err := NewEmbeddedMigrate(&conn.config, migrations, migrationsTable)
+	if err != nil {
+		return fmt.Errorf("error creating migrate: %v", err)
+	}
+	upErr := migrate.Up()
+	sourceErr, dbErr := migrate.Close()
+	if upErr != nil {
+		return fmt.Errorf("error applying migrations: %v", upErr)
+	}
+	if sourceErr != nil {
+		return fmt.Errorf("error closing migrations source: %v", sourceErr)
+	}
+	if dbErr != nil {
+		return fmt.Errorf("error closing migrations database connection: %v", dbErr)
+	}
+
+	return nil
+}
+
 func (conn *PgxConn) Begin() (rdb.Tx, error) {
 	tx, err := conn.pgxConn.Begin(context.Background())
 	if err != nil {
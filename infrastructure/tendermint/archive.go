@@ -0,0 +1,302 @@
+package tendermint
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Archive layout:
+//   manifest.json
+//   genesis.json
+//   block/<height / ARCHIVE_SHARD_SIZE>/<height>.json
+//   block_results/<height / ARCHIVE_SHARD_SIZE>/<height>.json
+// Every file is the raw Tendermint JSON-RPC response. In a directory archive, files may be gzip
+// compressed with an extra `.gz` extension. An archive can also be a zip file of the same layout.
+
+const ARCHIVE_VERSION = 1
+const ARCHIVE_SHARD_SIZE = 10000
+
+const ARCHIVE_MANIFEST_FILE = "manifest.json"
+const ARCHIVE_GENESIS_FILE = "genesis.json"
+const ARCHIVE_BLOCK_DIR = "block"
+const ARCHIVE_BLOCK_RESULTS_DIR = "block_results"
+
+var ErrArchiveFileNotFound = errors.New("file not found in archive")
+
+type ArchiveManifest struct {
+	Version    int    `json:"version"`
+	ChainID    string `json:"chain_id"`
+	FromHeight int64  `json:"from_height"`
+	ToHeight   int64  `json:"to_height"`
+}
+
+func ArchiveBlockFile(height int64) string {
+	return archiveHeightFile(ARCHIVE_BLOCK_DIR, height)
+}
+
+func ArchiveBlockResultsFile(height int64) string {
+	return archiveHeightFile(ARCHIVE_BLOCK_RESULTS_DIR, height)
+}
+
+func archiveHeightFile(dir string, height int64) string {
+	return fmt.Sprintf("%s/%d/%d.json", dir, height/ARCHIVE_SHARD_SIZE, height)
+}
+
+// archiveReader reads files from an archive
+type archiveReader interface {
+	// Open opens the file in archive. It returns ErrArchiveFileNotFound when the file does not exist.
+	Open(name string) (io.ReadCloser, error)
+	Close() error
+}
+
+func openArchiveReader(path string) (archiveReader, error) {
+	if strings.HasSuffix(path, ".zip") {
+		zipReader, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening zip archive: %v", err)
+		}
+		return &zipArchiveReader{zipReader}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening archive directory: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("archive %s is neither a directory nor a zip file", path)
+	}
+	return &dirArchiveReader{path}, nil
+}
+
+type dirArchiveReader struct {
+	dir string
+}
+
+func (reader *dirArchiveReader) Open(name string) (io.ReadCloser, error) {
+	path := filepath.Join(reader.dir, filepath.FromSlash(name))
+	file, err := os.Open(path)
+	if err == nil {
+		return file, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error opening archive file %s: %v", name, err)
+	}
+
+	gzipFile, err := os.Open(path + ".gz")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrArchiveFileNotFound, name)
+		}
+		return nil, fmt.Errorf("error opening archive file %s.gz: %v", name, err)
+	}
+	gzipReader, err := gzip.NewReader(gzipFile)
+	if err != nil {
+		_ = gzipFile.Close()
+		return nil, fmt.Errorf("error decompressing archive file %s.gz: %v", name, err)
+	}
+	return &gzipReadCloser{gzipReader, gzipFile}, nil
+}
+
+func (reader *dirArchiveReader) Close() error {
+	return nil
+}
+
+type zipArchiveReader struct {
+	zipReader *zip.ReadCloser
+}
+
+func (reader *zipArchiveReader) Open(name string) (io.ReadCloser, error) {
+	file, err := reader.zipReader.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrArchiveFileNotFound, name)
+		}
+		return nil, fmt.Errorf("error opening archive file %s: %v", name, err)
+	}
+	return file, nil
+}
+
+func (reader *zipArchiveReader) Close() error {
+	return reader.zipReader.Close()
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (reader *gzipReadCloser) Close() error {
+	if err := reader.Reader.Close(); err != nil {
+		_ = reader.file.Close()
+		return err
+	}
+	return reader.file.Close()
+}
+
+// ArchiveWriter writes Tendermint responses to an archive. The archive is a zip file when the path
+// ends with `.zip`, otherwise a directory.
+type ArchiveWriter struct {
+	dir      string
+	compress bool
+
+	// zipMutex guards zipWriter, which only supports writing one file at a time
+	zipMutex  sync.Mutex
+	zipFile   *os.File
+	zipWriter *zip.Writer
+}
+
+// NewArchiveWriter creates an archive writer. `compress` gzip compresses every file in a directory
+// archive, zip archives are always compressed.
+func NewArchiveWriter(path string, compress bool) (*ArchiveWriter, error) {
+	if strings.HasSuffix(path, ".zip") {
+		zipFile, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("error creating zip archive: %v", err)
+		}
+		return &ArchiveWriter{
+			zipFile:   zipFile,
+			zipWriter: zip.NewWriter(zipFile),
+		}, nil
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("error creating archive directory: %v", err)
+	}
+	return &ArchiveWriter{
+		dir:      path,
+		compress: compress,
+	}, nil
+}
+
+// ReadManifest returns the manifest of an existing directory archive, nil if there is none
+func (writer *ArchiveWriter) ReadManifest() (*ArchiveManifest, error) {
+	if writer.zipWriter != nil {
+		return nil, nil
+	}
+
+	reader := &dirArchiveReader{writer.dir}
+	manifest, err := readArchiveManifest(reader)
+	if err != nil {
+		if errors.Is(err, ErrArchiveFileNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// WriteFile writes the content read from `src` to the archive file. It is safe for concurrent use,
+// but writes to zip archives are serialized.
+func (writer *ArchiveWriter) WriteFile(name string, src io.Reader) error {
+	if writer.zipWriter != nil {
+		writer.zipMutex.Lock()
+		defer writer.zipMutex.Unlock()
+
+		fileWriter, err := writer.zipWriter.Create(name)
+		if err != nil {
+			return fmt.Errorf("error creating archive file %s: %v", name, err)
+		}
+		if _, err = io.Copy(fileWriter, src); err != nil {
+			return fmt.Errorf("error writing archive file %s: %v", name, err)
+		}
+		return nil
+	}
+
+	return writer.writeFile(name, src, writer.compress)
+}
+
+// writeFile writes the file to the directory archive atomically
+func (writer *ArchiveWriter) writeFile(name string, src io.Reader, compress bool) error {
+	path := filepath.Join(writer.dir, filepath.FromSlash(name))
+	if compress {
+		path += ".gz"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating archive directory: %v", err)
+	}
+
+	// write to a temporary file first so that a partially written file is never read
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error creating archive file %s: %v", name, err)
+	}
+	var dst io.Writer = file
+	var gzipWriter *gzip.Writer
+	if compress {
+		gzipWriter = gzip.NewWriter(file)
+		dst = gzipWriter
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error writing archive file %s: %v", name, err)
+	}
+	if gzipWriter != nil {
+		if err = gzipWriter.Close(); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("error compressing archive file %s: %v", name, err)
+		}
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("error closing archive file %s: %v", name, err)
+	}
+
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error moving archive file %s: %v", name, err)
+	}
+	return nil
+}
+
+// WriteManifest writes the manifest to the archive. It should be written after all the blocks.
+func (writer *ArchiveWriter) WriteManifest(manifest *ArchiveManifest) error {
+	manifestJSON, err := jsoniter.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding archive manifest: %v", err)
+	}
+
+	if writer.zipWriter != nil {
+		return writer.WriteFile(ARCHIVE_MANIFEST_FILE, bytes.NewReader(manifestJSON))
+	}
+	// manifest is never compressed so that it can be inspected easily
+	return writer.writeFile(ARCHIVE_MANIFEST_FILE, bytes.NewReader(manifestJSON), false)
+}
+
+func (writer *ArchiveWriter) Close() error {
+	if writer.zipWriter == nil {
+		return nil
+	}
+
+	if err := writer.zipWriter.Close(); err != nil {
+		_ = writer.zipFile.Close()
+		return fmt.Errorf("error closing zip archive: %v", err)
+	}
+	return writer.zipFile.Close()
+}
+
+func readArchiveManifest(reader archiveReader) (*ArchiveManifest, error) {
+	manifestFile, err := reader.Open(ARCHIVE_MANIFEST_FILE)
+	if err != nil {
+		return nil, err
+	}
+	defer manifestFile.Close()
+
+	var manifest ArchiveManifest
+	if err := jsoniter.NewDecoder(manifestFile).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("error decoding archive manifest: %v", err)
+	}
+	if manifest.Version != ARCHIVE_VERSION {
+		return nil, fmt.Errorf("unsupported archive version: %d", manifest.Version)
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,191 @@
+package tendermint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+
+	tendermint_interface "github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+var _ tendermint_interface.Client = &CachedClient{}
+
+// CachedClient serves Genesis, Block and BlockResults from the response cache and only requests
+// Tendermint on cache miss. Finalised blocks never change, so cached responses are never refreshed
+// unless they are deleted on chain reorganisation. LatestBlockHeight is never cached.
+//
+// Responses are cached under the chain id with the same layout as an archive, so that the cache of
+// a chain is never served to another chain.
+type CachedClient struct {
+	logger applogger.Logger
+	client *HTTPClient
+	cache  *ResponseCache
+
+	chainIDMutex sync.Mutex
+	chainID      string
+}
+
+func NewCachedClient(logger applogger.Logger, client *HTTPClient, cache *ResponseCache) *CachedClient {
+	return &CachedClient{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "CachedTendermintClient",
+		}),
+		client: client,
+		cache:  cache,
+	}
+}
+
+func (client *CachedClient) Genesis() (*genesis.Genesis, error) {
+	rawResp, err := client.requestCached(ARCHIVE_GENESIS_FILE, "genesis")
+	if err != nil {
+		return nil, err
+	}
+
+	genesis, err := ParseGenesisResp(bytes.NewReader(rawResp))
+	if err != nil {
+		return nil, err
+	}
+
+	return genesis, nil
+}
+
+// Block gets the block response with target height
+func (client *CachedClient) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	rawResp, err := client.requestCached(
+		ArchiveBlockFile(height), "block", "height="+strconv.FormatInt(height, 10),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, rawBlock, err := ParseBlockResp(bytes.NewReader(rawResp))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, rawBlock, nil
+}
+
+func (client *CachedClient) BlockResults(height int64) (*usecase_model.BlockResults, error) {
+	rawResp, err := client.requestCached(
+		ArchiveBlockResultsFile(height), "block_results", "height="+strconv.FormatInt(height, 10),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	blockResults, err := ParseBlockResultsResp(bytes.NewReader(rawResp))
+	if err != nil {
+		return nil, err
+	}
+
+	return blockResults, nil
+}
+
+// LatestBlockHeight gets the chain's latest block height from Tendermint
+func (client *CachedClient) LatestBlockHeight() (int64, error) {
+	return client.client.LatestBlockHeight()
+}
+
+// DeleteAfterHeight deletes the cached blocks and block results of the chain after height
+func (client *CachedClient) DeleteAfterHeight(height int64) error {
+	chainID, err := client.getChainID()
+	if err != nil {
+		return err
+	}
+
+	prefix := chainID + "/"
+	return client.cache.Delete(func(key string) bool {
+		if !strings.HasPrefix(key, prefix) {
+			return false
+		}
+		keyHeight, isHeightKey := parseCacheKeyHeight(key)
+		return isHeightKey && keyHeight > height
+	})
+}
+
+// requestCached returns the raw response of the method from cache, or requests Tendermint and caches
+// the response on cache miss
+func (client *CachedClient) requestCached(name string, method string, queryString ...string) ([]byte, error) {
+	chainID, err := client.getChainID()
+	if err != nil {
+		return nil, err
+	}
+	key := chainID + "/" + name
+
+	rawResp, err := client.cache.Get(key)
+	if err == nil {
+		return rawResp, nil
+	}
+	if !errors.Is(err, ErrResponseCacheMiss) {
+		return nil, err
+	}
+
+	rawRespBody, err := client.client.RequestRaw(method, queryString...)
+	if err != nil {
+		return nil, err
+	}
+	defer rawRespBody.Close()
+
+	rawResp, err = ioutil.ReadAll(rawRespBody)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Tendermint %s response: %v", method, err)
+	}
+	// JSON-RPC errors are returned with HTTP 200, only successful responses can be cached
+	if !isSuccessfulRPCResp(rawResp) {
+		return rawResp, nil
+	}
+	// failing to cache does not fail the request
+	if err = client.cache.Put(key, rawResp); err != nil {
+		client.logger.Errorf("error caching Tendermint %s response: %v", method, err)
+	}
+
+	return rawResp, nil
+}
+
+func (client *CachedClient) getChainID() (string, error) {
+	client.chainIDMutex.Lock()
+	defer client.chainIDMutex.Unlock()
+
+	if client.chainID != "" {
+		return client.chainID, nil
+	}
+
+	chainID, err := client.client.ChainID()
+	if err != nil {
+		return "", fmt.Errorf("error getting chain id for response cache: %v", err)
+	}
+	client.chainID = chainID
+
+	return chainID, nil
+}
+
+// parseCacheKeyHeight returns the height of a block or block results cache key
+func parseCacheKeyHeight(key string) (int64, bool) {
+	height, err := strconv.ParseInt(strings.TrimSuffix(path.Base(key), ".json"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return height, true
+}
+
+func isSuccessfulRPCResp(rawResp []byte) bool {
+	var resp struct {
+		Error interface{} `json:"error"`
+	}
+	if err := jsoniter.Unmarshal(rawResp, &resp); err != nil {
+		return false
+	}
+	return resp.Error == nil
+}
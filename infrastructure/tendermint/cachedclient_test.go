@@ -0,0 +1,118 @@
+package tendermint_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	. "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	infrastructure_tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+)
+
+const STATUS_JSON = `{"jsonrpc":"2.0","id":-1,"result":{"node_info":{"network":"testnet-croeseid-1"}}}`
+const RPC_ERROR_JSON = `{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"Internal error","data":"height 100 is not available"}}`
+
+var _ = Describe("CachedClient", func() {
+	var server *ghttp.Server
+	var tmpDir string
+	var cache *ResponseCache
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "tendermint-cache")
+		Expect(err).To(BeNil())
+		cache, err = NewResponseCache(tmpDir, 0)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should implement Client", func() {
+		var _ tendermint.Client = NewCachedClient(NewFakeLogger(), NewHTTPClient("http://localhost:26657"), cache)
+	})
+
+	It("should serve the block from cache after the first request", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/status"),
+				ghttp.RespondWith(http.StatusOK, STATUS_JSON),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block", "height=100"),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_JSON),
+			),
+		)
+
+		client := NewCachedClient(NewFakeLogger(), NewHTTPClient(server.URL()), cache)
+
+		block, _, err := client.Block(100)
+		Expect(err).To(BeNil())
+		Expect(block.Height).To(Equal(int64(100)))
+
+		cachedBlock, _, err := client.Block(100)
+		Expect(err).To(BeNil())
+		Expect(cachedBlock).To(Equal(block))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("should not cache JSON-RPC error response", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/status"),
+				ghttp.RespondWith(http.StatusOK, STATUS_JSON),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block", "height=100"),
+				ghttp.RespondWith(http.StatusOK, RPC_ERROR_JSON),
+			),
+		)
+
+		client := NewCachedClient(NewFakeLogger(), NewHTTPClient(server.URL()), cache)
+
+		_, _, _ = client.Block(100)
+		Expect(cache.Size()).To(Equal(int64(0)))
+	})
+
+	It("should request the chain again after deleting cached blocks", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/status"),
+				ghttp.RespondWith(http.StatusOK, STATUS_JSON),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block", "height=100"),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_JSON),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/block", "height=100"),
+				ghttp.RespondWith(http.StatusOK, infrastructure_tendermint_test.BLOCK_JSON),
+			),
+		)
+
+		client := NewCachedClient(NewFakeLogger(), NewHTTPClient(server.URL()), cache)
+
+		_, _, err := client.Block(100)
+		Expect(err).To(BeNil())
+
+		Expect(client.DeleteAfterHeight(100)).To(Succeed())
+		Expect(cache.Size()).NotTo(Equal(int64(0)))
+
+		Expect(client.DeleteAfterHeight(99)).To(Succeed())
+		Expect(cache.Size()).To(Equal(int64(0)))
+
+		_, _, err = client.Block(100)
+		Expect(err).To(BeNil())
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,113 @@
+package tendermint
+
+import (
+	"fmt"
+
+	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
+
+	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+// FileClient replays Tendermint responses from an archive created by ArchiveWriter. The archive is
+// either a directory or a zip file.
+type FileClient struct {
+	reader   archiveReader
+	manifest *ArchiveManifest
+}
+
+// NewFileClient opens the archive at path and returns a FileClient serving the heights in its manifest
+func NewFileClient(archivePath string) (*FileClient, error) {
+	reader, err := openArchiveReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := readArchiveManifest(reader)
+	if err != nil {
+		_ = reader.Close()
+		return nil, fmt.Errorf("error reading archive manifest: %v", err)
+	}
+
+	return &FileClient{
+		reader:   reader,
+		manifest: manifest,
+	}, nil
+}
+
+// Manifest returns the manifest of the archive
+func (client *FileClient) Manifest() ArchiveManifest {
+	return *client.manifest
+}
+
+func (client *FileClient) Genesis() (*genesis.Genesis, error) {
+	rawRespBody, err := client.reader.Open(ARCHIVE_GENESIS_FILE)
+	if err != nil {
+		return nil, err
+	}
+	defer rawRespBody.Close()
+
+	genesis, err := ParseGenesisResp(rawRespBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return genesis, nil
+}
+
+// Block gets the archived block response with target height
+func (client *FileClient) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	if err := client.checkHeight(height); err != nil {
+		return nil, nil, err
+	}
+
+	rawRespBody, err := client.reader.Open(ArchiveBlockFile(height))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rawRespBody.Close()
+
+	block, rawBlock, err := ParseBlockResp(rawRespBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, rawBlock, nil
+}
+
+func (client *FileClient) BlockResults(height int64) (*usecase_model.BlockResults, error) {
+	if err := client.checkHeight(height); err != nil {
+		return nil, err
+	}
+
+	rawRespBody, err := client.reader.Open(ArchiveBlockResultsFile(height))
+	if err != nil {
+		return nil, err
+	}
+	defer rawRespBody.Close()
+
+	blockResults, err := ParseBlockResultsResp(rawRespBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockResults, nil
+}
+
+// LatestBlockHeight returns the last height in the archive
+func (client *FileClient) LatestBlockHeight() (int64, error) {
+	return client.manifest.ToHeight, nil
+}
+
+func (client *FileClient) Close() error {
+	return client.reader.Close()
+}
+
+func (client *FileClient) checkHeight(height int64) error {
+	if height < client.manifest.FromHeight || height > client.manifest.ToHeight {
+		return fmt.Errorf(
+			"block %d is out of archive range %d-%d",
+			height, client.manifest.FromHeight, client.manifest.ToHeight,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package tendermint_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/appinterface/tendermint"
+	. "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+	infrastructure_tendermint_test "github.com/crypto-com/chain-indexing/infrastructure/tendermint/test"
+	usecase_parser_test "github.com/crypto-com/chain-indexing/usecase/parser/test"
+)
+
+var _ = Describe("FileClient", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "tendermint-archive")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should implement Client", func() {
+		var _ tendermint.Client = &FileClient{}
+	})
+
+	archiveCases := []struct {
+		description string
+		path        string
+		compress    bool
+	}{
+		{"directory archive", "archive", false},
+		{"compressed directory archive", "archive", true},
+		{"zip archive", "archive.zip", false},
+	}
+	for _, archiveCase := range archiveCases {
+		archiveCase := archiveCase
+
+		Describe(archiveCase.description, func() {
+			var archivePath string
+
+			BeforeEach(func() {
+				archivePath = filepath.Join(tmpDir, archiveCase.path)
+				mustWriteArchive(archivePath, archiveCase.compress, &ArchiveManifest{
+					Version:    ARCHIVE_VERSION,
+					ChainID:    "testnet-croeseid-1",
+					FromHeight: 0,
+					ToHeight:   100,
+				})
+			})
+
+			It("should replay the archived responses", func() {
+				client, err := NewFileClient(archivePath)
+				Expect(err).To(BeNil())
+				defer client.Close()
+
+				Expect(client.Manifest().ChainID).To(Equal("testnet-croeseid-1"))
+
+				latestBlockHeight, err := client.LatestBlockHeight()
+				Expect(err).To(BeNil())
+				Expect(latestBlockHeight).To(Equal(int64(100)))
+
+				genesis, err := client.Genesis()
+				Expect(err).To(BeNil())
+				Expect(genesis.ChainID).To(Equal("testnet-croeseid-2"))
+
+				block, rawBlock, err := client.Block(100)
+				Expect(err).To(BeNil())
+				Expect(block.Height).To(Equal(int64(100)))
+				Expect(block.Hash).To(Equal("82C25937191D1CF73BE9222CB04CE35B7A1366CC5BB08D9BB9AB457712E4F2D1"))
+				Expect(rawBlock).NotTo(BeNil())
+
+				blockResults, err := client.BlockResults(100)
+				Expect(err).To(BeNil())
+				Expect(blockResults.TxsResults).NotTo(BeEmpty())
+			})
+
+			It("should return Error when the block is not in the archive", func() {
+				client, err := NewFileClient(archivePath)
+				Expect(err).To(BeNil())
+				defer client.Close()
+
+				_, _, err = client.Block(101)
+				Expect(err).To(MatchError("block 101 is out of archive range 0-100"))
+
+				_, _, err = client.Block(99)
+				Expect(err).To(MatchError(ErrArchiveFileNotFound))
+			})
+		})
+	}
+
+	It("should return Error when the archive has no manifest", func() {
+		_, err := NewFileClient(tmpDir)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("ArchiveWriter", func() {
+	It("should read the manifest of existing directory archive", func() {
+		tmpDir, err := ioutil.TempDir("", "tendermint-archive")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(tmpDir)
+
+		writer, err := NewArchiveWriter(tmpDir, true)
+		Expect(err).To(BeNil())
+		manifest, err := writer.ReadManifest()
+		Expect(err).To(BeNil())
+		Expect(manifest).To(BeNil())
+
+		anyManifest := ArchiveManifest{
+			Version:    ARCHIVE_VERSION,
+			ChainID:    "testnet-croeseid-1",
+			FromHeight: 1,
+			ToHeight:   10,
+		}
+		Expect(writer.WriteManifest(&anyManifest)).To(Succeed())
+		Expect(writer.Close()).To(Succeed())
+
+		// manifest is never compressed
+		Expect(filepath.Join(tmpDir, ARCHIVE_MANIFEST_FILE)).To(BeARegularFile())
+
+		manifest, err = writer.ReadManifest()
+		Expect(err).To(BeNil())
+		Expect(*manifest).To(Equal(anyManifest))
+	})
+})
+
+func mustWriteArchive(path string, compress bool, manifest *ArchiveManifest) {
+	writer, err := NewArchiveWriter(path, compress)
+	Expect(err).To(BeNil())
+
+	Expect(writer.WriteFile(
+		ARCHIVE_GENESIS_FILE, strings.NewReader(usecase_parser_test.GENESIS_RESP),
+	)).To(Succeed())
+	Expect(writer.WriteFile(
+		ArchiveBlockFile(100), strings.NewReader(infrastructure_tendermint_test.BLOCK_JSON),
+	)).To(Succeed())
+	Expect(writer.WriteFile(
+		ArchiveBlockResultsFile(100), strings.NewReader(infrastructure_tendermint_test.BLOCK_RESULTS_JSON),
+	)).To(Succeed())
+	Expect(writer.WriteManifest(manifest)).To(Succeed())
+	Expect(writer.Close()).To(Succeed())
+}
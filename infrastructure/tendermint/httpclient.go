@@ -2,35 +2,57 @@ package tendermint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 	"io/ioutil"
 	"encoding/json"
 
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/crypto-com/chain-indexing/infrastructure/endpointpool"
 	"github.com/crypto-com/chain-indexing/usecase/model/genesis"
 
 	usecase_model "github.com/crypto-com/chain-indexing/usecase/model"
 )
 
 type HTTPClient struct {
-	httpClient       *http.Client
-	tendermintRPCUrl string
+	httpClient *http.Client
+	endpoints  *endpointpool.Pool
 }
 
 // NewHTTPClient returns a new HTTPClient for tendermint request
 func NewHTTPClient(tendermintRPCUrl string) *HTTPClient {
+	return NewMultiEndpointHTTPClient([]string{tendermintRPCUrl})
+}
+
+// NewMultiEndpointHTTPClient returns a new HTTPClient which fails over among the tendermint endpoints.
+// All endpoints must report the same chain id.
+func NewMultiEndpointHTTPClient(tendermintRPCUrls []string) *HTTPClient {
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	return &HTTPClient{
-		httpClient,
-		strings.TrimSuffix(tendermintRPCUrl, "/"),
+	client := &HTTPClient{
+		httpClient: httpClient,
 	}
+	client.endpoints = endpointpool.NewPool(tendermintRPCUrls, client.chainID)
+
+	return client
+}
+
+// WithChainID pins the chain id which every endpoint has to report, see endpointpool.Pool.WithChainID
+func (client *HTTPClient) WithChainID(chainID string) *HTTPClient {
+	client.endpoints.WithChainID(chainID)
+	return client
+}
+
+// VerifyChainID checks that the endpoints serve the same chain, see endpointpool.Pool.VerifyChainID
+func (client *HTTPClient) VerifyChainID() error {
+	return client.endpoints.VerifyChainID()
 }
 
 func (client *HTTPClient) Genesis() (*genesis.Genesis, error) {
@@ -102,34 +124,93 @@ func (client *HTTPClient) LatestBlockHeight() (int64, error) {
 	return block.Height, nil
 }
 
-// request construct tendermint url and issues an HTTP request
+// RequestRaw requests the Tendermint RPC method and returns the unparsed response body
+func (client *HTTPClient) RequestRaw(method string, queryString ...string) (io.ReadCloser, error) {
+	return client.request(method, queryString...)
+}
+
+// request issues an HTTP request to the healthiest endpoint and fails over to the others on
+// connection error or unavailable endpoint.
 // returns the success http Body
 func (client *HTTPClient) request(method string, queryString ...string) (io.ReadCloser, error) {
+	return client.endpoints.Request(func(endpointURL string) (io.ReadCloser, bool, error) {
+		return client.requestEndpoint(endpointURL, method, queryString...)
+	})
+}
+
+// requestEndpoint construct tendermint url and issues an HTTP request to the endpoint. It returns
+// whether the error is caused by the endpoint being unavailable.
+func (client *HTTPClient) requestEndpoint(
+	endpointURL string, method string, queryString ...string,
+) (io.ReadCloser, bool, error) {
 	var err error
 
-	url := client.tendermintRPCUrl + "/" + method
+	url := endpointURL + "/" + method
 	if len(queryString) > 0 {
 		url += "?" + queryString[0]
 	}
 
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating HTTP request with context: %v", err)
+		return nil, false, fmt.Errorf("error creating HTTP request with context: %v", err)
 	}
 	rawResp, err := client.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %v", url, err)
+		return nil, true, fmt.Errorf("error requesting Tendermint %s endpoint: %v", url, err)
 	}
 
 	if rawResp.StatusCode != 200 {
 		rawResp.Body.Close()
-		return nil, fmt.Errorf("error requesting Tendermint %s endpoint: %s", method, rawResp.Status)
+		return nil, endpointpool.IsUnavailableStatus(rawResp.StatusCode), fmt.Errorf(
+			"error requesting Tendermint %s endpoint: %s", method, rawResp.Status,
+		)
 	}
 
-	return rawResp.Body, nil
+	return rawResp.Body, false, nil
 }
 
+// ChainID returns the chain id reported by the endpoints
+func (client *HTTPClient) ChainID() (string, error) {
+	if chainID := client.endpoints.ChainID(); chainID != "" {
+		return chainID, nil
+	}
 
+	rawRespBody, err := client.request("status")
+	if err != nil {
+		return "", err
+	}
+	defer rawRespBody.Close()
+
+	return parseStatusChainID(rawRespBody)
+}
+
+// chainID requests the status of the endpoint and returns the chain id it reports
+func (client *HTTPClient) chainID(endpointURL string) (string, error) {
+	rawRespBody, _, err := client.requestEndpoint(endpointURL, "status")
+	if err != nil {
+		return "", err
+	}
+	defer rawRespBody.Close()
+
+	chainID, err := parseStatusChainID(rawRespBody)
+	if err != nil {
+		return "", fmt.Errorf("error getting chain id of %s: %v", endpointURL, err)
+	}
+
+	return chainID, nil
+}
+
+func parseStatusChainID(rawRespReader io.Reader) (string, error) {
+	var statusResp StatusResp
+	if err := jsoniter.NewDecoder(rawRespReader).Decode(&statusResp); err != nil {
+		return "", fmt.Errorf("error decoding Tendermint status response: %v", err)
+	}
+	if statusResp.Result.NodeInfo.Network == "" {
+		return "", errors.New("missing chain id in Tendermint status response")
+	}
+
+	return statusResp.Result.NodeInfo.Network, nil
+}
 
 func (client *HTTPClient) Status() (*map[string]interface{}, error){
 	rawRespBody, err := client.request("status")
@@ -149,6 +230,14 @@ func (client *HTTPClient) Status() (*map[string]interface{}, error){
 	return &jsonMap, nil
 }
 
+type StatusResp struct {
+	Result struct {
+		NodeInfo struct {
+			Network string `json:"network"`
+		} `json:"node_info"`
+	} `json:"result"`
+}
+
 type GenesisResp struct {
 	Jsonrpc string            `json:"jsonrpc"`
 	ID      int64             `json:"id"`
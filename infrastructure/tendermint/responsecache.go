@@ -0,0 +1,203 @@
+package tendermint
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EVICTION_TARGET_RATIO is the ratio of size cap to evict down to, so that eviction does not run on
+// every write once the cache is full
+const EVICTION_TARGET_RATIO = 0.9
+
+var ErrResponseCacheMiss = errors.New("response cache miss")
+
+// ResponseCache persists raw responses on disk. Least recently used entries are evicted when the
+// total size exceeds the size cap. Access time survives restart through the file modification time.
+type ResponseCache struct {
+	dir     string
+	maxSize int64
+
+	mutex     sync.Mutex
+	entries   map[string]*responseCacheEntry
+	totalSize int64
+}
+
+type responseCacheEntry struct {
+	size           int64
+	lastAccessedAt time.Time
+}
+
+// NewResponseCache creates a cache in the directory and loads existing entries. A non-positive
+// maxSize disables eviction.
+func NewResponseCache(dir string, maxSize int64) (*ResponseCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating response cache directory: %v", err)
+	}
+
+	cache := &ResponseCache{
+		dir:     dir,
+		maxSize: maxSize,
+
+		entries: make(map[string]*responseCacheEntry),
+	}
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+func (cache *ResponseCache) load() error {
+	return filepath.Walk(cache.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("error loading response cache: %v", err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if filepath.Ext(path) == ".tmp" {
+			// leftover of an interrupted write
+			_ = os.Remove(path)
+			return nil
+		}
+
+		key, err := filepath.Rel(cache.dir, path)
+		if err != nil {
+			return fmt.Errorf("error loading response cache: %v", err)
+		}
+		cache.entries[filepath.ToSlash(key)] = &responseCacheEntry{
+			size:           info.Size(),
+			lastAccessedAt: info.ModTime(),
+		}
+		cache.totalSize += info.Size()
+		return nil
+	})
+}
+
+// Get returns the cached response of the key. It returns ErrResponseCacheMiss when the key is not
+// cached.
+func (cache *ResponseCache) Get(key string) ([]byte, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		return nil, ErrResponseCacheMiss
+	}
+
+	path := cache.path(key)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			cache.removeEntry(key)
+			return nil, ErrResponseCacheMiss
+		}
+		return nil, fmt.Errorf("error reading response cache %s: %v", key, err)
+	}
+
+	entry.lastAccessedAt = time.Now()
+	// best effort to keep the access time across restart
+	_ = os.Chtimes(path, entry.lastAccessedAt, entry.lastAccessedAt)
+
+	return data, nil
+}
+
+// Put stores the response of the key and evicts least recently used entries if needed
+func (cache *ResponseCache) Put(key string, data []byte) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.maxSize > 0 && int64(len(data)) > cache.maxSize {
+		return nil
+	}
+
+	path := cache.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating response cache directory: %v", err)
+	}
+	// write to a temporary file first so that a partially written response is never read
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing response cache %s: %v", key, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error moving response cache %s: %v", key, err)
+	}
+
+	cache.removeEntry(key)
+	cache.entries[key] = &responseCacheEntry{
+		size:           int64(len(data)),
+		lastAccessedAt: time.Now(),
+	}
+	cache.totalSize += int64(len(data))
+
+	if cache.maxSize > 0 && cache.totalSize > cache.maxSize {
+		cache.evict(int64(float64(cache.maxSize) * EVICTION_TARGET_RATIO))
+	}
+	return nil
+}
+
+// Delete removes the keys matched by `shouldDelete` from the cache
+func (cache *ResponseCache) Delete(shouldDelete func(key string) bool) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for key := range cache.entries {
+		if !shouldDelete(key) {
+			continue
+		}
+		if err := os.Remove(cache.path(key)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error deleting response cache %s: %v", key, err)
+		}
+		cache.removeEntry(key)
+	}
+	return nil
+}
+
+// Size returns the total size of cached responses in bytes
+func (cache *ResponseCache) Size() int64 {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.totalSize
+}
+
+// evict removes least recently used entries until total size is not more than targetSize. Caller
+// must hold the mutex.
+func (cache *ResponseCache) evict(targetSize int64) {
+	keys := make([]string, 0, len(cache.entries))
+	for key := range cache.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return cache.entries[keys[i]].lastAccessedAt.Before(cache.entries[keys[j]].lastAccessedAt)
+	})
+
+	for _, key := range keys {
+		if cache.totalSize <= targetSize {
+			return
+		}
+		if err := os.Remove(cache.path(key)); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		cache.removeEntry(key)
+	}
+}
+
+// removeEntry removes the key from the index. Caller must hold the mutex.
+func (cache *ResponseCache) removeEntry(key string) {
+	if entry, ok := cache.entries[key]; ok {
+		cache.totalSize -= entry.size
+		delete(cache.entries, key)
+	}
+}
+
+func (cache *ResponseCache) path(key string) string {
+	return filepath.Join(cache.dir, filepath.FromSlash(key))
+}
@@ -0,0 +1,89 @@
+package tendermint_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/crypto-com/chain-indexing/infrastructure/tendermint"
+)
+
+var _ = Describe("ResponseCache", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "tendermint-cache")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should return ErrResponseCacheMiss when the key is not cached", func() {
+		cache, err := NewResponseCache(tmpDir, 0)
+		Expect(err).To(BeNil())
+
+		_, err = cache.Get("chain/block/0/1.json")
+		Expect(err).To(Equal(ErrResponseCacheMiss))
+	})
+
+	It("should return the cached response after restart", func() {
+		cache, err := NewResponseCache(tmpDir, 0)
+		Expect(err).To(BeNil())
+		Expect(cache.Put("chain/block/0/1.json", []byte("block 1"))).To(Succeed())
+
+		cache, err = NewResponseCache(tmpDir, 0)
+		Expect(err).To(BeNil())
+
+		data, err := cache.Get("chain/block/0/1.json")
+		Expect(err).To(BeNil())
+		Expect(string(data)).To(Equal("block 1"))
+		Expect(cache.Size()).To(Equal(int64(7)))
+	})
+
+	It("should evict least recently used responses when exceeding size cap", func() {
+		cache, err := NewResponseCache(tmpDir, 20)
+		Expect(err).To(BeNil())
+
+		Expect(cache.Put("chain/block/0/1.json", []byte("0123456789"))).To(Succeed())
+		<-time.After(10 * time.Millisecond)
+		Expect(cache.Put("chain/block/0/2.json", []byte("0123456789"))).To(Succeed())
+		<-time.After(10 * time.Millisecond)
+		_, err = cache.Get("chain/block/0/1.json")
+		Expect(err).To(BeNil())
+
+		Expect(cache.Put("chain/block/0/3.json", []byte("0123456789"))).To(Succeed())
+
+		Expect(cache.Size()).To(Equal(int64(10)))
+		_, err = cache.Get("chain/block/0/2.json")
+		Expect(err).To(Equal(ErrResponseCacheMiss))
+		_, err = cache.Get("chain/block/0/1.json")
+		Expect(err).To(Equal(ErrResponseCacheMiss))
+		_, err = cache.Get("chain/block/0/3.json")
+		Expect(err).To(BeNil())
+		Expect(filepath.Join(tmpDir, "chain", "block", "0", "2.json")).NotTo(BeAnExistingFile())
+	})
+
+	It("should delete matched responses", func() {
+		cache, err := NewResponseCache(tmpDir, 0)
+		Expect(err).To(BeNil())
+		Expect(cache.Put("chain/block/0/1.json", []byte("block 1"))).To(Succeed())
+		Expect(cache.Put("chain/block/0/2.json", []byte("block 2"))).To(Succeed())
+
+		Expect(cache.Delete(func(key string) bool {
+			return key == "chain/block/0/2.json"
+		})).To(Succeed())
+
+		_, err = cache.Get("chain/block/0/1.json")
+		Expect(err).To(BeNil())
+		_, err = cache.Get("chain/block/0/2.json")
+		Expect(err).To(Equal(ErrResponseCacheMiss))
+		Expect(cache.Size()).To(Equal(int64(7)))
+	})
+})
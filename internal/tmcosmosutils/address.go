@@ -0,0 +1,22 @@
+package tmcosmosutils
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// AccountAddressFromValidatorAddress returns the account address of the validator operator, which
+// shares the same address bytes with a different bech32 prefix
+func AccountAddressFromValidatorAddress(accountAddressPrefix string, validatorAddress string) (string, error) {
+	_, conv, err := bech32.Decode(validatorAddress)
+	if err != nil {
+		return "", fmt.Errorf("error decoding validator address: %v", err)
+	}
+	address, err := bech32.Encode(accountAddressPrefix, conv)
+	if err != nil {
+		return "", fmt.Errorf("error encoding validator address bits to account address: %v", err)
+	}
+
+	return address, nil
+}
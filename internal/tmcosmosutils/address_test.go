@@ -0,0 +1,23 @@
+package tmcosmosutils_test
+
+import (
+	"github.com/crypto-com/chain-indexing/internal/tmcosmosutils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tmcosmosutils", func() {
+	Describe("AccountAddressFromValidatorAddress", func() {
+		It("should work", func() {
+			Expect(tmcosmosutils.AccountAddressFromValidatorAddress(
+				"tcro", "tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus",
+			)).To(Equal("tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn"))
+		})
+
+		It("should return error when the validator address is invalid", func() {
+			_, err := tmcosmosutils.AccountAddressFromValidatorAddress("tcro", "invalid")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
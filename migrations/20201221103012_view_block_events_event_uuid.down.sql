@@ -0,0 +1 @@
+ALTER TABLE view_block_events DROP COLUMN IF EXISTS event_uuid;
@@ -0,0 +1 @@
+ALTER TABLE view_block_events ADD COLUMN event_uuid VARCHAR NULL;
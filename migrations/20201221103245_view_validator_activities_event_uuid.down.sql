@@ -0,0 +1 @@
+ALTER TABLE view_validator_activities DROP COLUMN IF EXISTS event_uuid;
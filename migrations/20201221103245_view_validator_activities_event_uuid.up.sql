@@ -0,0 +1 @@
+ALTER TABLE view_validator_activities ADD COLUMN event_uuid VARCHAR NULL;
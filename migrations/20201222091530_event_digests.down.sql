@@ -0,0 +1 @@
+DROP TABLE IF EXISTS event_digests;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE TABLE event_digests (
+    height BIGINT,
+    digest VARCHAR NOT NULL,
+    PRIMARY KEY (height)
+);
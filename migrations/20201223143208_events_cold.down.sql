@@ -0,0 +1 @@
+DROP TABLE IF EXISTS events_cold;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE TABLE events_cold (
+    uuid VARCHAR REFERENCES events (uuid) ON DELETE CASCADE,
+    payload BYTEA NOT NULL,
+    PRIMARY KEY (uuid)
+);
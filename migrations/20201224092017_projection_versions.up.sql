@@ -0,0 +1,5 @@
+CREATE TABLE projection_versions (
+    id VARCHAR NOT NULL,
+    version INTEGER NOT NULL,
+    PRIMARY KEY (id)
+);
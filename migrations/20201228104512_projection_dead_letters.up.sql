@@ -0,0 +1,9 @@
+CREATE TABLE projection_dead_letters (
+    projection_id VARCHAR NOT NULL,
+    height BIGINT NOT NULL,
+    events JSONB NOT NULL,
+    error VARCHAR NOT NULL,
+    status VARCHAR NOT NULL,
+    quarantined_at BIGINT NOT NULL,
+    PRIMARY KEY (projection_id, height)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS events_payload_to_archive_index;
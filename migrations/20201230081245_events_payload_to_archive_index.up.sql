@@ -0,0 +1 @@
+CREATE INDEX events_payload_to_archive_index ON events USING btree (name, height) WHERE payload <> '{}'::jsonb;
@@ -0,0 +1,35 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+type BootstrapAccount struct {
+	blockHeight int64
+	params      model.AccountBootstrapParams
+}
+
+func NewBootstrapAccount(blockHeight int64, params model.AccountBootstrapParams) *BootstrapAccount {
+	return &BootstrapAccount{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*BootstrapAccount) Name() string {
+	return "BootstrapAccount"
+}
+
+// Version returns version of command
+func (*BootstrapAccount) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *BootstrapAccount) Exec() (entity_event.Event, error) {
+	event := event.NewAccountBootstrapped(cmd.blockHeight, cmd.params)
+	return event, nil
+}
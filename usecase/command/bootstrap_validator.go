@@ -0,0 +1,35 @@
+package command
+
+import (
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+)
+
+type BootstrapValidator struct {
+	blockHeight int64
+	params      model.ValidatorBootstrapParams
+}
+
+func NewBootstrapValidator(blockHeight int64, params model.ValidatorBootstrapParams) *BootstrapValidator {
+	return &BootstrapValidator{
+		blockHeight,
+		params,
+	}
+}
+
+// Name returns name of command
+func (*BootstrapValidator) Name() string {
+	return "BootstrapValidator"
+}
+
+// Version returns version of command
+func (*BootstrapValidator) Version() int {
+	return 1
+}
+
+// Exec process the command data and return the event accordingly
+func (cmd *BootstrapValidator) Exec() (entity_event.Event, error) {
+	event := event.NewValidatorBootstrapped(cmd.blockHeight, cmd.params)
+	return event, nil
+}
@@ -0,0 +1,69 @@
+package event
+
+import (
+	"bytes"
+
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+
+	jsoniter "github.com/json-iterator/go"
+
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/luci/go-render/render"
+)
+
+const ACCOUNT_BOOTSTRAPPED = "AccountBootstrapped"
+
+// AccountBootstrapped is the account state at the bootstrap height when indexing starts after genesis
+type AccountBootstrapped struct {
+	event_entity.Base
+
+	AccountType    string    `json:"accountType"`
+	AccountAddress string    `json:"accountAddress"`
+	Pubkey         string    `json:"pubkey"`
+	AccountNumber  string    `json:"accountNumber"`
+	SequenceNumber string    `json:"sequenceNumber"`
+	Balance        coin.Coin `json:"balance"`
+}
+
+func NewAccountBootstrapped(blockHeight int64, params model.AccountBootstrapParams) *AccountBootstrapped {
+	return &AccountBootstrapped{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        ACCOUNT_BOOTSTRAPPED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.AccountType,
+		params.AccountAddress,
+		params.Pubkey,
+		params.AccountNumber,
+		params.SequenceNumber,
+		params.Balance,
+	}
+}
+
+func (event *AccountBootstrapped) ToJSON() (string, error) {
+	encoded, err := jsoniter.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (event *AccountBootstrapped) String() string {
+	return render.Render(event)
+}
+
+func DecodeAccountBootstrapped(encoded []byte) (event_entity.Event, error) {
+	jsonDecoder := jsoniter.NewDecoder(bytes.NewReader(encoded))
+	jsonDecoder.DisallowUnknownFields()
+
+	var event *AccountBootstrapped
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
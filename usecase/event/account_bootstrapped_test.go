@@ -0,0 +1,46 @@
+package event_test
+
+import (
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+var _ = Describe("Event", func() {
+	registry := event_entity.NewRegistry()
+	event_usecase.RegisterEvents(registry)
+
+	Describe("En/DecodeAccountBootstrapped", func() {
+		It("should able to encode and decode to the same event", func() {
+			anyHeight := int64(1000)
+			anyParams := model.AccountBootstrapParams{
+				AccountType:    "/cosmos.auth.v1beta1.BaseAccount ",
+				AccountAddress: "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn",
+				Pubkey:         "A+cv43DDSs8VOSKXFqMvqGxP3OOkr5z6T64XRpWUgh/i",
+				AccountNumber:  "1",
+				SequenceNumber: "2",
+				Balance:        coin.MustNewCoinFromString("100000000"),
+			}
+			event := event_usecase.NewAccountBootstrapped(anyHeight, anyParams)
+
+			encoded, err := event.ToJSON()
+			Expect(err).To(BeNil())
+
+			decodedEvent, err := registry.DecodeByType(
+				event_usecase.ACCOUNT_BOOTSTRAPPED, 1, []byte(encoded),
+			)
+			Expect(err).To(BeNil())
+			Expect(decodedEvent).To(Equal(event))
+			typedEvent, _ := decodedEvent.(*event_usecase.AccountBootstrapped)
+			Expect(typedEvent.Name()).To(Equal(event_usecase.ACCOUNT_BOOTSTRAPPED))
+			Expect(typedEvent.Version()).To(Equal(1))
+
+			Expect(typedEvent.AccountAddress).To(Equal(anyParams.AccountAddress))
+			Expect(typedEvent.Balance).To(Equal(anyParams.Balance))
+		})
+	})
+})
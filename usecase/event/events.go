@@ -4,8 +4,13 @@ import (
 	"github.com/crypto-com/chain-indexing/entity/event"
 )
 
+// RegisterEvents registers the decoders of the latest event versions. When an event is changed to a new
+// version, the upcaster from the previous version has to be registered so that the stored events of
+// older versions are still decoded.
 func RegisterEvents(registry *event.Registry) {
 	registry.Register(GENESIS_CREATED, 1, DecodeGenesisCreated)
+	registry.Register(VALIDATOR_BOOTSTRAPPED, 1, DecodeValidatorBootstrapped)
+	registry.Register(ACCOUNT_BOOTSTRAPPED, 1, DecodeAccountBootstrapped)
 
 	registry.Register(BLOCK_CREATED, 1, DecodeBlockCreated)
 	registry.Register(RAW_BLOCK_CREATED, 1, DecodeRawBlockCreated)
@@ -45,6 +45,11 @@ func (base *MsgBase) TxHash() string {
 	return base.MsgTxHash
 }
 
+// TxScope implements event.TxScoped
+func (base *MsgBase) TxScope() (string, int) {
+	return base.MsgTxHash, base.MsgIndex
+}
+
 func (base *MsgBase) TxSuccess() bool {
 	return strings.HasSuffix(base.Name(), MSG_SUCCESS_SUFFIX)
 }
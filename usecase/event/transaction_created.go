@@ -75,6 +75,11 @@ func parseSenders(signers []model.TransactionSigner) []TransactionSigner {
 	return parsedSenders
 }
 
+// TxScope implements entity_event.TxScoped
+func (event *TransactionCreated) TxScope() (string, int) {
+	return event.TxHash, entity_event.NO_MSG_INDEX
+}
+
 func (event *TransactionCreated) ToJSON() (string, error) {
 	encoded, err := jsoniter.Marshal(event)
 	if err != nil {
@@ -51,6 +51,11 @@ func NewTransactionFailed(blockHeight int64, params model.CreateTransactionParam
 	}
 }
 
+// TxScope implements entity_event.TxScoped
+func (event *TransactionFailed) TxScope() (string, int) {
+	return event.TxHash, entity_event.NO_MSG_INDEX
+}
+
 func (event *TransactionFailed) ToJSON() (string, error) {
 	encoded, err := jsoniter.Marshal(event)
 	if err != nil {
@@ -0,0 +1,76 @@
+package event
+
+import (
+	"bytes"
+
+	"github.com/crypto-com/chain-indexing/usecase/model"
+
+	jsoniter "github.com/json-iterator/go"
+
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/luci/go-render/render"
+)
+
+const VALIDATOR_BOOTSTRAPPED = "ValidatorBootstrapped"
+
+// ValidatorBootstrapped is the validator state at the bootstrap height when indexing starts after genesis
+type ValidatorBootstrapped struct {
+	event_entity.Base
+
+	OperatorAddress         string                        `json:"operatorAddress"`
+	InitialDelegatorAddress string                        `json:"initialDelegatorAddress"`
+	TendermintPubkey        string                        `json:"tendermintPubkey"`
+	Status                  string                        `json:"status"`
+	Jailed                  bool                          `json:"jailed"`
+	Power                   string                        `json:"power"`
+	Tokens                  string                        `json:"tokens"`
+	Description             model.MsgValidatorDescription `json:"description"`
+	Commission              model.MsgValidatorCommission  `json:"commission"`
+	MinSelfDelegation       string                        `json:"minSelfDelegation"`
+}
+
+func NewValidatorBootstrapped(blockHeight int64, params model.ValidatorBootstrapParams) *ValidatorBootstrapped {
+	return &ValidatorBootstrapped{
+		event_entity.NewBase(event_entity.BaseParams{
+			Name:        VALIDATOR_BOOTSTRAPPED,
+			Version:     1,
+			BlockHeight: blockHeight,
+		}),
+
+		params.OperatorAddress,
+		params.InitialDelegatorAddress,
+		params.TendermintPubkey,
+		params.Status,
+		params.Jailed,
+		params.Power,
+		params.Tokens,
+		params.Description,
+		params.Commission,
+		params.MinSelfDelegation,
+	}
+}
+
+func (event *ValidatorBootstrapped) ToJSON() (string, error) {
+	encoded, err := jsoniter.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (event *ValidatorBootstrapped) String() string {
+	return render.Render(event)
+}
+
+func DecodeValidatorBootstrapped(encoded []byte) (event_entity.Event, error) {
+	jsonDecoder := jsoniter.NewDecoder(bytes.NewReader(encoded))
+	jsonDecoder.DisallowUnknownFields()
+
+	var event *ValidatorBootstrapped
+	if err := jsonDecoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
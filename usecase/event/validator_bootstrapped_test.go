@@ -0,0 +1,57 @@
+package event_test
+
+import (
+	event_entity "github.com/crypto-com/chain-indexing/entity/event"
+	"github.com/crypto-com/chain-indexing/usecase/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	event_usecase "github.com/crypto-com/chain-indexing/usecase/event"
+)
+
+var _ = Describe("Event", func() {
+	registry := event_entity.NewRegistry()
+	event_usecase.RegisterEvents(registry)
+
+	Describe("En/DecodeValidatorBootstrapped", func() {
+		It("should able to encode and decode to the same event", func() {
+			anyHeight := int64(1000)
+			anyParams := model.ValidatorBootstrapParams{
+				OperatorAddress:         "tcrocncl1fmprm0sjy6lz9llv7rltn0v2azzwcwzvr4ufus",
+				InitialDelegatorAddress: "tcro1fmprm0sjy6lz9llv7rltn0v2azzwcwzvk2lsyn",
+				TendermintPubkey:        "tWY6qzpOg/6HFj2X3a8+tzIAehW7k2MWOgrjotcWCuI=",
+				Status:                  "Bonded",
+				Jailed:                  false,
+				Power:                   "123456",
+				Tokens:                  "12345600000000",
+				Description: model.MsgValidatorDescription{
+					Moniker: "node",
+				},
+				Commission: model.MsgValidatorCommission{
+					Rate:          "0.100000000000000000",
+					MaxRate:       "0.200000000000000000",
+					MaxChangeRate: "0.010000000000000000",
+				},
+				MinSelfDelegation: "1",
+			}
+			event := event_usecase.NewValidatorBootstrapped(anyHeight, anyParams)
+
+			encoded, err := event.ToJSON()
+			Expect(err).To(BeNil())
+
+			decodedEvent, err := registry.DecodeByType(
+				event_usecase.VALIDATOR_BOOTSTRAPPED, 1, []byte(encoded),
+			)
+			Expect(err).To(BeNil())
+			Expect(decodedEvent).To(Equal(event))
+			typedEvent, _ := decodedEvent.(*event_usecase.ValidatorBootstrapped)
+			Expect(typedEvent.Name()).To(Equal(event_usecase.VALIDATOR_BOOTSTRAPPED))
+			Expect(typedEvent.Version()).To(Equal(1))
+
+			Expect(typedEvent.OperatorAddress).To(Equal(anyParams.OperatorAddress))
+			Expect(typedEvent.TendermintPubkey).To(Equal(anyParams.TendermintPubkey))
+			Expect(typedEvent.Power).To(Equal(anyParams.Power))
+			Expect(typedEvent.Commission).To(Equal(anyParams.Commission))
+		})
+	})
+})
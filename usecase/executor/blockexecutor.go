@@ -46,6 +46,7 @@ func (exec *BlockExecutor) ExecAllCommands() error {
 		}
 		exec.Events = append(exec.Events, event)
 	}
+	event_entity.AssignDeterministicIDs(exec.Events)
 	return nil
 }
 
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/crypto-com/chain-indexing/usecase/coin"
+)
+
+// ValidatorBootstrapParams is the state of a validator at the bootstrap height when indexing starts
+// after genesis
+type ValidatorBootstrapParams struct {
+	OperatorAddress         string                  `json:"operatorAddress"`
+	InitialDelegatorAddress string                  `json:"initialDelegatorAddress"`
+	TendermintPubkey        string                  `json:"tendermintPubkey"`
+	Status                  string                  `json:"status"`
+	Jailed                  bool                    `json:"jailed"`
+	Power                   string                  `json:"power"`
+	Tokens                  string                  `json:"tokens"`
+	Description             MsgValidatorDescription `json:"description"`
+	Commission              MsgValidatorCommission  `json:"commission"`
+	MinSelfDelegation       string                  `json:"minSelfDelegation"`
+}
+
+// AccountBootstrapParams is the state of an account at the bootstrap height when indexing starts
+// after genesis
+type AccountBootstrapParams struct {
+	AccountType    string    `json:"accountType"`
+	AccountAddress string    `json:"accountAddress"`
+	Pubkey         string    `json:"pubkey"`
+	AccountNumber  string    `json:"accountNumber"`
+	SequenceNumber string    `json:"sequenceNumber"`
+	Balance        coin.Coin `json:"balance"`
+}
@@ -0,0 +1,235 @@
+package syncstrategy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/crypto-com/chain-indexing/entity/command"
+	applogger "github.com/crypto-com/chain-indexing/internal/logger"
+)
+
+var _ Strategy = &Resilient{}
+
+const DEFAULT_RESILIENT_MAX_RETRIES = 5
+const DEFAULT_RESILIENT_INITIAL_BACKOFF = 500 * time.Millisecond
+const DEFAULT_RESILIENT_MAX_BACKOFF = 30 * time.Second
+
+// Resilient sync strategy retries each block height individually with exponential backoff. Instead
+// of discarding the whole window on any failure, it returns the contiguous prefix of synced blocks.
+type Resilient struct {
+	logger applogger.Logger
+
+	size   int
+	params ResilientParams
+
+	failureCountsMutex sync.Mutex
+	failureCounts      map[int64]int
+}
+
+type ResilientParams struct {
+	// MaxInFlight is the maximum number of concurrent sync block workers. Default to window size.
+	MaxInFlight int
+	// MaxRetries is the maximum number of retries of a height before giving up
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewResilient(logger applogger.Logger, size int, params ResilientParams) *Resilient {
+	if params.MaxInFlight <= 0 || params.MaxInFlight > size {
+		params.MaxInFlight = size
+	}
+	if params.MaxRetries <= 0 {
+		params.MaxRetries = DEFAULT_RESILIENT_MAX_RETRIES
+	}
+	if params.InitialBackoff <= 0 {
+		params.InitialBackoff = DEFAULT_RESILIENT_INITIAL_BACKOFF
+	}
+	if params.MaxBackoff <= 0 {
+		params.MaxBackoff = DEFAULT_RESILIENT_MAX_BACKOFF
+	}
+
+	return &Resilient{
+		logger: logger.WithFields(applogger.LogFields{
+			"module":      "ResilientStrategy",
+			"size":        size,
+			"maxInFlight": params.MaxInFlight,
+		}),
+
+		size:   size,
+		params: params,
+
+		failureCounts: make(map[int64]int),
+	}
+}
+
+func (resilient *Resilient) Sync(
+	currentHeight int64,
+	latestHeight int64,
+	worker SyncBlockWorker,
+) ([][]command.Command, SyncedHeight, error) {
+	beginHeight := currentHeight
+	var endHeight int64
+	if latestHeight-currentHeight+1 < int64(resilient.size) {
+		endHeight = latestHeight
+	} else {
+		endHeight = beginHeight + int64(resilient.size) - 1
+	}
+
+	logger := resilient.logger.WithFields(applogger.LogFields{
+		"beginHeight": beginHeight,
+		"endHeight":   endHeight,
+	})
+	logger.Debug("spawning goroutines for sync block workers")
+
+	resilient.resetFailureCounts()
+
+	commandWindow := newUnsafeCommandWindow(beginHeight, endHeight)
+	// heights after a failed height are useless because only the contiguous prefix is returned
+	failedHeight := newAtomicMinHeight(endHeight + 1)
+	errs := make(map[int64]error)
+	var resultMutex sync.Mutex
+
+	inFlightCh := make(chan struct{}, resilient.params.MaxInFlight)
+	var wg sync.WaitGroup
+	for height := beginHeight; height <= endHeight; height += 1 {
+		inFlightCh <- struct{}{}
+		if height > failedHeight.Get() {
+			<-inFlightCh
+			break
+		}
+
+		wg.Add(1)
+		go func(height int64) {
+			defer func() {
+				<-inFlightCh
+				wg.Done()
+			}()
+
+			commands, err := resilient.syncWithRetry(logger, height, worker, failedHeight)
+
+			resultMutex.Lock()
+			defer resultMutex.Unlock()
+			if err != nil {
+				failedHeight.SetIfLess(height)
+				errs[height] = err
+				return
+			}
+			commandWindow.Put(height, commands)
+		}(height)
+	}
+	wg.Wait()
+
+	resilient.logFailureCounts(logger)
+
+	syncedHeight := failedHeight.Get() - 1
+	if syncedHeight < beginHeight {
+		return nil, beginHeight - 1, errs[failedHeight.Get()]
+	}
+	if syncedHeight < endHeight {
+		logger.Errorf(
+			"error syncing block #%d, keeping synced blocks up to #%d: %v",
+			failedHeight.Get(), syncedHeight, errs[failedHeight.Get()],
+		)
+	} else {
+		logger.Info("all sync block workers completed")
+	}
+
+	return commandWindow.Export()[:syncedHeight-beginHeight+1], syncedHeight, nil
+}
+
+func (resilient *Resilient) syncWithRetry(
+	logger applogger.Logger,
+	height int64,
+	worker SyncBlockWorker,
+	failedHeight *atomicMinHeight,
+) ([]command.Command, error) {
+	backoff := resilient.params.InitialBackoff
+	for attempt := 0; ; attempt += 1 {
+		commands, err := worker(height)
+		if err == nil {
+			return commands, nil
+		}
+
+		resilient.increaseFailureCount(height)
+		if attempt >= resilient.params.MaxRetries {
+			return nil, fmt.Errorf("error syncing block #%d after %d retries: %v", height, attempt, err)
+		}
+		if height > failedHeight.Get() {
+			return nil, fmt.Errorf("error syncing block #%d and an earlier block has failed: %v", height, err)
+		}
+
+		logger.Errorf("error from sync block worker #%d, retrying in %s: %v", height, backoff, err)
+		<-time.After(backoff)
+
+		backoff *= 2
+		if backoff > resilient.params.MaxBackoff {
+			backoff = resilient.params.MaxBackoff
+		}
+	}
+}
+
+// FailureCounts returns the number of failed attempts of each height in the last Sync
+func (resilient *Resilient) FailureCounts() map[int64]int {
+	resilient.failureCountsMutex.Lock()
+	defer resilient.failureCountsMutex.Unlock()
+
+	failureCounts := make(map[int64]int, len(resilient.failureCounts))
+	for height, count := range resilient.failureCounts {
+		failureCounts[height] = count
+	}
+	return failureCounts
+}
+
+func (resilient *Resilient) resetFailureCounts() {
+	resilient.failureCountsMutex.Lock()
+	defer resilient.failureCountsMutex.Unlock()
+
+	resilient.failureCounts = make(map[int64]int)
+}
+
+func (resilient *Resilient) increaseFailureCount(height int64) {
+	resilient.failureCountsMutex.Lock()
+	defer resilient.failureCountsMutex.Unlock()
+
+	resilient.failureCounts[height] += 1
+}
+
+func (resilient *Resilient) logFailureCounts(logger applogger.Logger) {
+	failureCounts := resilient.FailureCounts()
+	if len(failureCounts) == 0 {
+		return
+	}
+
+	logger.WithFields(applogger.LogFields{
+		"failureCounts": failureCounts,
+	}).Infof("%d block heights failed at least once", len(failureCounts))
+}
+
+type atomicMinHeight struct {
+	mutex  sync.RWMutex
+	height int64
+}
+
+func newAtomicMinHeight(height int64) *atomicMinHeight {
+	return &atomicMinHeight{
+		height: height,
+	}
+}
+
+func (minHeight *atomicMinHeight) Get() int64 {
+	minHeight.mutex.RLock()
+	defer minHeight.mutex.RUnlock()
+
+	return minHeight.height
+}
+
+func (minHeight *atomicMinHeight) SetIfLess(height int64) {
+	minHeight.mutex.Lock()
+	defer minHeight.mutex.Unlock()
+
+	if height < minHeight.height {
+		minHeight.height = height
+	}
+}
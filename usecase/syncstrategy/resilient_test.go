@@ -0,0 +1,145 @@
+package syncstrategy_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/crypto-com/chain-indexing/entity/command"
+	entity_event "github.com/crypto-com/chain-indexing/entity/event"
+	. "github.com/crypto-com/chain-indexing/internal/logger/test"
+	"github.com/crypto-com/chain-indexing/usecase/syncstrategy"
+)
+
+var _ = Describe("Resilient", func() {
+	anyParams := syncstrategy.ResilientParams{
+		MaxInFlight:    2,
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}
+
+	It("should return commands of all heights when all workers succeed", func() {
+		resilient := syncstrategy.NewResilient(NewFakeLogger(), 5, anyParams)
+
+		blocksCommands, syncedHeight, err := resilient.Sync(10, 100, heightCommandWorker)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(14)))
+		Expect(blocksCommands).To(HaveLen(5))
+		for i, commands := range blocksCommands {
+			Expect(commands[0].(*heightCommand).height).To(Equal(int64(10 + i)))
+		}
+		Expect(resilient.FailureCounts()).To(BeEmpty())
+	})
+
+	It("should retry failed height and report its failure count", func() {
+		resilient := syncstrategy.NewResilient(NewFakeLogger(), 5, anyParams)
+
+		var mutex sync.Mutex
+		remainingFailures := 2
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if blockHeight == 12 && remainingFailures > 0 {
+				remainingFailures -= 1
+				return nil, errors.New("timeout")
+			}
+			return heightCommandWorker(blockHeight)
+		}
+
+		blocksCommands, syncedHeight, err := resilient.Sync(10, 100, worker)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(14)))
+		Expect(blocksCommands).To(HaveLen(5))
+		Expect(resilient.FailureCounts()).To(Equal(map[int64]int{12: 2}))
+	})
+
+	It("should keep the synced contiguous prefix when a height keeps failing", func() {
+		resilient := syncstrategy.NewResilient(NewFakeLogger(), 5, anyParams)
+
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			if blockHeight == 12 {
+				return nil, errors.New("timeout")
+			}
+			return heightCommandWorker(blockHeight)
+		}
+
+		blocksCommands, syncedHeight, err := resilient.Sync(10, 100, worker)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(11)))
+		Expect(blocksCommands).To(HaveLen(2))
+		Expect(resilient.FailureCounts()[12]).To(Equal(anyParams.MaxRetries + 1))
+	})
+
+	It("should return Error when the first height keeps failing", func() {
+		resilient := syncstrategy.NewResilient(NewFakeLogger(), 5, anyParams)
+
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			if blockHeight == 10 {
+				return nil, errors.New("timeout")
+			}
+			return heightCommandWorker(blockHeight)
+		}
+
+		blocksCommands, syncedHeight, err := resilient.Sync(10, 100, worker)
+
+		Expect(err).To(MatchError("error syncing block #10 after 3 retries: timeout"))
+		Expect(syncedHeight).To(Equal(int64(9)))
+		Expect(blocksCommands).To(BeNil())
+	})
+
+	It("should not exceed the maximum in-flight workers", func() {
+		resilient := syncstrategy.NewResilient(NewFakeLogger(), 10, anyParams)
+
+		var mutex sync.Mutex
+		inFlight := 0
+		maxInFlight := 0
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			mutex.Lock()
+			inFlight += 1
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mutex.Unlock()
+
+			<-time.After(5 * time.Millisecond)
+
+			mutex.Lock()
+			inFlight -= 1
+			mutex.Unlock()
+			return heightCommandWorker(blockHeight)
+		}
+
+		_, syncedHeight, err := resilient.Sync(0, 100, worker)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(9)))
+		Expect(maxInFlight).To(Equal(anyParams.MaxInFlight))
+	})
+})
+
+func heightCommandWorker(blockHeight int64) ([]command.Command, error) {
+	return []command.Command{&heightCommand{blockHeight}}, nil
+}
+
+type heightCommand struct {
+	height int64
+}
+
+func (_ *heightCommand) Name() string {
+	return "HeightCommand"
+}
+
+func (_ *heightCommand) Version() int {
+	return 1
+}
+
+func (_ *heightCommand) Exec() (entity_event.Event, error) {
+	return nil, nil
+}
@@ -0,0 +1,13 @@
+package syncstrategy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSyncStrategy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SyncStrategy Suite")
+}
@@ -51,6 +51,7 @@ func (window *Window) Sync(
 			commands, err := worker(height)
 			if err != nil {
 				workResultCh <- workResult{height, nil, err}
+				return
 			}
 
 			workResultCh <- workResult{height, commands, nil}